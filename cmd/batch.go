@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/utils"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	batchParallel int  // 동시에 생성할 모델 수
+	batchNoCache  bool // 기여 데이터 캐시 사용 안 함
+)
+
+// batchCmd generates the models listed in a manifest file.
+var batchCmd = &cobra.Command{
+	Use:   "batch <manifest.yaml>",
+	Short: "Generate the models listed in a YAML manifest at the same time",
+	Long: `Batch generates one model per entry of a YAML manifest, several at the same
+time, and prints a summary of the models written and the entries that failed.
+
+Each entry sets the options of one model with the names of the command line
+flags; the defaults apply to every entry that does not set them:
+
+  defaults:
+    year: 2025
+    start-month: 3
+    end-month: 5
+    right-text: Wintertech Internship 2025
+  entries:
+    - user: wlgh1553
+      top-text: Hoji
+    - user: kiru211
+      top-text: Kiru
+      output: kiru.3mf
+
+Supported keys: user, year, full, start-month, end-month, top-text, right-text,
+text (a list of --text specifications), logo, org, hostname and output. An
+entry with full set needs an output, as its year range starts at the user's
+join year.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBatch(cmd.Context(), args[0], batchParallel, !batchNoCache, cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	batchCmd.Flags().IntVarP(&batchParallel, "parallel", "p", defaultBatchParallel, "Number of models generated at the same time")
+	batchCmd.Flags().BoolVar(&batchNoCache, "no-cache", false, "Always fetch contributions from GitHub without reading or writing the cache")
+	rootCmd.AddCommand(batchCmd)
+}
+
+// defaultBatchParallel is the default number of models generated at the same time.
+const defaultBatchParallel = 4
+
+// batchEntry is one model of a batch manifest. Unset fields take the manifest's defaults.
+type batchEntry struct {
+	User       string   `yaml:"user"`
+	Year       string   `yaml:"year"`
+	Full       *bool    `yaml:"full"` // Unset takes the default, so an entry can turn it off
+	StartMonth int      `yaml:"start-month"`
+	EndMonth   int      `yaml:"end-month"`
+	TopText    string   `yaml:"top-text"`
+	RightText  string   `yaml:"right-text"`
+	Texts      []string `yaml:"text"`
+	Logo       string   `yaml:"logo"`
+	Org        string   `yaml:"org"`
+	Hostname   string   `yaml:"hostname"`
+	Output     string   `yaml:"output"`
+}
+
+// batchManifest lists the models generated by the batch command.
+type batchManifest struct {
+	Defaults batchEntry   `yaml:"defaults"`
+	Entries  []batchEntry `yaml:"entries"`
+}
+
+// batchResult is the outcome of generating one entry.
+type batchResult struct {
+	entry    batchEntry
+	err      error
+	duration time.Duration
+}
+
+// loadManifest reads a batch manifest and returns its entries with the defaults
+// applied and the output paths resolved.
+func loadManifest(path string) ([]batchEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to open manifest", err)
+	}
+	defer file.Close()
+
+	var manifest batchManifest
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, errors.New(errors.ValidationError, "invalid manifest", err)
+	}
+	if len(manifest.Entries) == 0 {
+		return nil, errors.New(errors.ValidationError, "manifest has no entries", nil)
+	}
+
+	outputs := make(map[string]int, len(manifest.Entries))
+	entries := make([]batchEntry, len(manifest.Entries))
+	for i, entry := range manifest.Entries {
+		entry = entry.withDefaults(manifest.Defaults)
+		if entry.User == "" {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("entry %d has no user", i+1), nil)
+		}
+		startYear, endYear, err := utils.ParseYearRange(entry.Year)
+		if err != nil {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("entry %d (%s) has an invalid year range", i+1, entry.User), err)
+		}
+		// The range of a full entry starts at the user's join year, which is only known once fetched
+		if entry.full() && entry.Output == "" {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("entry %d (%s) has full set and needs an output", i+1, entry.User), nil)
+		}
+		entry.Output = utils.GenerateOutputFilename(entry.User, startYear, endYear, entry.Output)
+		if first, ok := outputs[entry.Output]; ok {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("entries %d and %d both write %s", first, i+1, entry.Output), nil)
+		}
+		outputs[entry.Output] = i + 1
+		entries[i] = entry
+	}
+	return entries, nil
+}
+
+// withDefaults returns the entry with its unset fields taken from the defaults.
+// The default texts come before the entry's, so the entry's replace them slot by slot.
+func (e batchEntry) withDefaults(defaults batchEntry) batchEntry {
+	pick := func(value, fallback string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	}
+	e.User = pick(e.User, defaults.User)
+	e.Year = pick(e.Year, pick(defaults.Year, fmt.Sprintf("%d", time.Now().Year())))
+	if e.Full == nil {
+		e.Full = defaults.Full
+	}
+	if e.StartMonth == 0 {
+		e.StartMonth = max(defaults.StartMonth, 1)
+	}
+	if e.EndMonth == 0 {
+		e.EndMonth = defaults.EndMonth
+		if e.EndMonth == 0 {
+			e.EndMonth = 12
+		}
+	}
+	e.TopText = pick(e.TopText, defaults.TopText)
+	e.RightText = pick(e.RightText, defaults.RightText)
+	e.Texts = append(append([]string{}, defaults.Texts...), e.Texts...)
+	e.Logo = pick(e.Logo, pick(defaults.Logo, "logo.png"))
+	e.Org = pick(e.Org, defaults.Org)
+	e.Hostname = pick(e.Hostname, defaults.Hostname)
+	e.Output = pick(e.Output, defaults.Output)
+	return e
+}
+
+// full reports whether the entry covers every year since the user joined.
+func (e batchEntry) full() bool {
+	return e.Full != nil && *e.Full
+}
+
+// runBatch generates the models of a manifest, up to parallel at the same time,
+// and writes a summary table. It fails when any entry failed.
+func runBatch(ctx context.Context, path string, parallel int, useCache bool, out io.Writer) error {
+	if parallel < 1 {
+		return errors.New(errors.ValidationError, "parallel model count must be at least 1", nil)
+	}
+	entries, err := loadManifest(path)
+	if err != nil {
+		return err
+	}
+
+	// Previews of models generated at the same time would be interleaved
+	preview := skyline.PreviewOutput
+	skyline.PreviewOutput = io.Discard
+	defer func() { skyline.PreviewOutput = preview }()
+
+	results := make([]batchResult, len(entries))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, parallel)
+	for i, entry := range entries {
+		wg.Add(1)
+		go func(i int, entry batchEntry) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			start := time.Now()
+			err := generateBatchEntry(ctx, entry, useCache)
+			results[i] = batchResult{entry: entry, err: err, duration: time.Since(start)}
+		}(i, entry)
+	}
+	wg.Wait()
+
+	failed := writeBatchSummary(out, results)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d models failed", failed, len(results))
+	}
+	return nil
+}
+
+// generateBatchEntry generates the model of one manifest entry.
+func generateBatchEntry(ctx context.Context, entry batchEntry, useCache bool) error {
+	startYear, endYear, err := utils.ParseYearRange(entry.Year)
+	if err != nil {
+		return err
+	}
+	modelOpts := stl.ModelOptions{
+		TopText:   entry.TopText,
+		RightText: entry.RightText,
+		LogoPath:  entry.Logo,
+		Profile:   stl.DefaultPrintProfile(),
+		Base:      geometry.BaseOptions{Shape: geometry.BaseBox},
+	}
+	for _, spec := range entry.Texts {
+		text, err := geometry.ParseTextSpec(spec)
+		if err != nil {
+			return fmt.Errorf("invalid text %q: %v", spec, err)
+		}
+		modelOpts.Texts = append(modelOpts.Texts, text)
+	}
+	fetchOpts := skyline.FetchOptions{
+		Cache:        cache.Options{Enabled: useCache, TTL: cache.DefaultTTL},
+		Organization: entry.Org,
+		Hostname:     entry.Hostname,
+	}
+	return skyline.GenerateSkyline(ctx, startYear, endYear, entry.User, entry.full(), entry.Output, false, entry.StartMonth, entry.EndMonth, fetchOpts, modelOpts)
+}
+
+// writeBatchSummary writes a table of the entries' outcomes and returns the number that failed.
+func writeBatchSummary(out io.Writer, results []batchResult) int {
+	failed := 0
+	table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "USER\tOUTPUT\tRESULT")
+	for _, result := range results {
+		status := fmt.Sprintf("ok (%s)", result.duration.Round(100*time.Millisecond))
+		if result.err != nil {
+			failed++
+			status = "failed: " + strings.ReplaceAll(result.err.Error(), "\n", "; ")
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s\n", result.entry.User, result.entry.Output, status)
+	}
+	table.Flush()
+	fmt.Fprintf(out, "%d of %d models generated\n", len(results)-failed, len(results))
+	return failed
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+)
+
+func writeManifest(t *testing.T, manifest string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	if err := os.WriteFile(path, []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadManifest(t *testing.T) {
+	path := writeManifest(t, `
+defaults:
+  year: 2025
+  start-month: 3
+  end-month: 5
+  right-text: Wintertech Internship 2025
+  text: ["back:Thanks"]
+entries:
+  - user: wlgh1553
+    top-text: Hoji
+  - user: kiru211
+    year: 2023-2024
+    text: ["left:Kiru"]
+    hostname: ghe.example.com
+    output: kiru.3mf
+`)
+	entries, err := loadManifest(path)
+	if err != nil {
+		t.Fatalf("loadManifest() error = %v", err)
+	}
+	want := []batchEntry{
+		{User: "wlgh1553", Year: "2025", StartMonth: 3, EndMonth: 5, TopText: "Hoji", RightText: "Wintertech Internship 2025",
+			Texts: []string{"back:Thanks"}, Logo: "logo.png", Output: "wlgh1553-2025-github-skyline.stl"},
+		{User: "kiru211", Year: "2023-2024", StartMonth: 3, EndMonth: 5, RightText: "Wintertech Internship 2025",
+			Texts: []string{"back:Thanks", "left:Kiru"}, Logo: "logo.png", Hostname: "ghe.example.com", Output: "kiru.3mf"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("loadManifest() = %+v, want %+v", entries, want)
+	}
+
+	tests := []struct {
+		name     string
+		manifest string
+	}{
+		{name: "no entries", manifest: "defaults:\n  year: 2025\n"},
+		{name: "unknown key", manifest: "entries:\n  - user: mona\n    colour: red\n"},
+		{name: "no user", manifest: "entries:\n  - top-text: Mona\n"},
+		{name: "invalid year", manifest: "entries:\n  - user: mona\n    year: twenty\n"},
+		{name: "same output", manifest: "entries:\n  - user: mona\n    output: a.stl\n  - user: hubot\n    output: a.stl\n"},
+		{name: "full without output", manifest: "entries:\n  - user: mona\n    full: true\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadManifest(writeManifest(t, tt.manifest)); err == nil {
+				t.Error("loadManifest() expected error")
+			}
+		})
+	}
+	// An entry's explicit false overrides a full default
+	entries, err = loadManifest(writeManifest(t, "defaults:\n  full: true\n  year: 2025\nentries:\n  - user: mona\n    output: mona.stl\n  - user: hubot\n    full: false\n"))
+	if err != nil {
+		t.Fatalf("loadManifest() error = %v", err)
+	}
+	if !entries[0].full() || entries[1].full() {
+		t.Errorf("full = %v, %v, want true, false", entries[0].full(), entries[1].full())
+	}
+	if entries[1].Output != "hubot-2025-github-skyline.stl" {
+		t.Errorf("output = %q, want the name of the year range", entries[1].Output)
+	}
+
+	if _, err := loadManifest(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("loadManifest() expected error for a missing file")
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func(string) (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "mona", JoinYear: 2020}), nil
+	}
+
+	dir := t.TempDir()
+	path := writeManifest(t, `
+defaults:
+  year: 2024
+entries:
+  - user: mona
+    top-text: Mona
+    output: `+filepath.Join(dir, "mona.stl")+`
+  - user: hubot
+    start-month: 13
+    output: `+filepath.Join(dir, "hubot.stl")+`
+`)
+
+	var out bytes.Buffer
+	err := runBatch(context.Background(), path, 2, false, &out)
+	if err == nil || !strings.Contains(err.Error(), "1 of 2 models failed") {
+		t.Errorf("runBatch() error = %v, want one failed model", err)
+	}
+	summary := out.String()
+	if !strings.Contains(summary, "mona") || !strings.Contains(summary, "ok (") || !strings.Contains(summary, "failed:") || !strings.Contains(summary, "1 of 2 models generated") {
+		t.Errorf("summary does not list both outcomes:\n%s", summary)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "mona.stl")); err != nil {
+		t.Errorf("model of the successful entry missing: %v", err)
+	}
+
+	if err := runBatch(context.Background(), path, 0, false, &out); err == nil {
+		t.Error("runBatch() accepted zero parallel models")
+	}
+}
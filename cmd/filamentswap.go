@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/gcode"
+	"github.com/spf13/cobra"
+)
+
+var (
+	swapHeights []float64 // 필라멘트를 교체할 높이 (mm)
+	swapOutput  string    // 결과 G-code 경로 (기본값: 입력 파일 덮어쓰기)
+)
+
+// filamentSwapCmd inserts filament changes into sliced G-code.
+var filamentSwapCmd = &cobra.Command{
+	Use:   "filament-swap <file.gcode>",
+	Short: "Insert M600 filament changes at the given heights into a G-code file",
+	Long: `Filament-swap inserts an M600 filament change at the start of the first
+layer above each height, so a single-extruder printer can colour the skyline by
+height. Run skyline with --color-bands to get the heights of the intensity bands
+and the matching command.
+
+The file is modified in place unless --output is given, so the command can be
+set up as a post-processing script in PrusaSlicer, OrcaSlicer or Bambu Studio.
+Layers are found from the slicer's layer change comments (;LAYER_CHANGE or ;LAYER:).`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runFilamentSwap(args[0], swapOutput, swapHeights, cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	filamentSwapCmd.Flags().Float64SliceVar(&swapHeights, "heights", nil, "Heights in mm above the bed at which to change filament, e.g. 10,24.82,29.19")
+	filamentSwapCmd.Flags().StringVarP(&swapOutput, "output", "o", "", "Output G-code path (default: modify the input file)")
+	_ = filamentSwapCmd.MarkFlagRequired("heights")
+	rootCmd.AddCommand(filamentSwapCmd)
+}
+
+// runFilamentSwap inserts the filament changes into the G-code file and writes the result.
+func runFilamentSwap(path, output string, heights []float64, out io.Writer) error {
+	if len(heights) == 0 {
+		return errors.New(errors.ValidationError, "at least one height is required", nil)
+	}
+	if output == "" {
+		output = path
+	}
+
+	input, err := os.ReadFile(path)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to read G-code file", err)
+	}
+	var result bytes.Buffer
+	inserted, err := gcode.InsertFilamentChanges(bytes.NewReader(input), &result, heights)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(output, result.Bytes(), 0o644); err != nil {
+		return errors.New(errors.IOError, "failed to write G-code file", err)
+	}
+
+	fmt.Fprintf(out, "Inserted %d of %d filament changes into %s\n", inserted, len(heights), output)
+	if inserted < len(heights) {
+		fmt.Fprintf(out, "The print ends below the remaining %d heights\n", len(heights)-inserted)
+	}
+	return nil
+}
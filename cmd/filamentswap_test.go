@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunFilamentSwap(t *testing.T) {
+	dir := t.TempDir()
+	gcode := "G28\n;LAYER_CHANGE\n;Z:10\nG1 X1 E1\n;LAYER_CHANGE\n;Z:10.2\nG1 X2 E1\n"
+	input := filepath.Join(dir, "skyline.gcode")
+
+	tests := []struct {
+		name       string
+		output     string
+		heights    []float64
+		gcode      string
+		wantM600   int
+		wantText   string
+		wantErr    bool
+		wantOutput string // File expected to hold the result
+	}{
+		{name: "in place", heights: []float64{10}, gcode: gcode, wantM600: 1, wantText: "Inserted 1 of 1", wantOutput: input},
+		{name: "separate output", output: filepath.Join(dir, "out.gcode"), heights: []float64{10, 30}, gcode: gcode, wantM600: 1, wantText: "remaining 1 heights", wantOutput: filepath.Join(dir, "out.gcode")},
+		{name: "no heights", gcode: gcode, wantErr: true},
+		{name: "no layers", heights: []float64{10}, gcode: "G28\nG1 Z10.2\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := os.WriteFile(input, []byte(tt.gcode), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			var out bytes.Buffer
+			err := runFilamentSwap(input, tt.output, tt.heights, &out)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runFilamentSwap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !strings.Contains(out.String(), tt.wantText) {
+				t.Errorf("runFilamentSwap() output = %q, missing %q", out.String(), tt.wantText)
+			}
+			result, err := os.ReadFile(tt.wantOutput)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Count(string(result), "M600\n"); got != tt.wantM600 {
+				t.Errorf("output has %d M600 commands, want %d", got, tt.wantM600)
+			}
+		})
+	}
+
+	if err := runFilamentSwap(filepath.Join(dir, "missing.gcode"), "", []float64{10}, &bytes.Buffer{}); err == nil {
+		t.Error("runFilamentSwap() expected error for a missing file")
+	}
+}
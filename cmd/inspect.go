@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/spf13/cobra"
+)
+
+// inspectCmd reports measurements and print estimates for an existing STL file.
+var inspectCmd = &cobra.Command{
+	Use:   "inspect <file.stl>",
+	Short: "Show size, volume and filament/print-time estimates for an STL file",
+	Long: `Inspect loads a binary or ASCII STL file and reports its bounding box,
+triangle count, surface area and enclosed volume, together with an estimate of
+the filament length and weight and the print time.
+
+Estimates assume solid perimeters and sparse infill and are meant for budgeting
+filament across batches; a slicer gives exact numbers.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runInspect(args[0], printProfile, cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	addPrintProfileFlags(inspectCmd.Flags())
+	rootCmd.AddCommand(inspectCmd)
+}
+
+// runInspect reads the STL file and prints its statistics and print estimates.
+func runInspect(path string, profile stl.PrintProfile, out io.Writer) error {
+	if err := profile.Validate(); err != nil {
+		return err
+	}
+
+	triangles, err := stl.ReadSTL(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "%s\n%s", path, stl.ComputeMeshStats(triangles).Summary(profile))
+	return nil
+}
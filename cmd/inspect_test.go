@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+)
+
+func TestRunInspect(t *testing.T) {
+	cube, err := geometry.CreateCube(0, 0, 0, 20, 20, 20)
+	if err != nil {
+		t.Fatalf("CreateCube() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "cube.stl")
+	if err := stl.WriteSTLBinary(path, cube); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := runInspect(path, stl.DefaultPrintProfile(), &out); err != nil {
+		t.Fatalf("runInspect() error = %v", err)
+	}
+	for _, want := range []string{"20.00 x 20.00 x 20.00 mm", "Volume:       8.00 cm³", "Print time:"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("runInspect() output = %q, missing %q", out.String(), want)
+		}
+	}
+
+	badProfile := stl.DefaultPrintProfile()
+	badProfile.Infill = 2
+	if err := runInspect(path, badProfile, &out); err == nil {
+		t.Error("runInspect() expected error for invalid profile")
+	}
+	if err := runInspect(filepath.Join(t.TempDir(), "missing.stl"), stl.DefaultPrintProfile(), &out); err == nil {
+		t.Error("runInspect() expected error for missing file")
+	}
+}
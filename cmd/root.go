@@ -3,18 +3,24 @@ package cmd
 
 import (
 	"context"
+	stderrors "errors"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
-	"github.com/cli/go-gh/v2/pkg/auth"
 	"github.com/cli/go-gh/v2/pkg/browser"
 	"github.com/github/gh-skyline/cmd/skyline"
+	"github.com/github/gh-skyline/internal/cache"
 	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/github"
 	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/qrcode"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
 	"github.com/github/gh-skyline/internal/utils"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // Command line variables and root command configuration
@@ -30,6 +36,57 @@ var (
 	startMonth int    // 시작 월
 	endMonth   int    // 종료 월
 	rightText  string // 우측 텍스트 입력값
+	logoPath   string // 전면 좌측에 양각할 이미지 경로
+	logoRelief string // 이미지 양각 방식 (binary, grayscale)
+	logoMin    float64
+	logoMax    float64
+	logoInvert bool
+	logoGamma  float64
+	characters []string // 윗면에 올릴 캐릭터 STL 모델
+	noCheck    bool     // 생성 후 메시 검사 생략
+	maxTris    int      // 최대 삼각형 수 (0이면 제한 없음)
+	fonts      []string // 모든 텍스트에 사용할 글꼴 (순서대로 대체)
+	topFonts   []string // 상단 텍스트 전용 글꼴
+	rightFonts []string // 우측 텍스트 전용 글꼴
+	fontDirs   []string // 글꼴을 찾을 추가 디렉터리
+	texts      []string // 슬롯별 텍스트 배치
+	qrEnabled  bool     // 프로필 QR 코드 양각
+	qrURL      string   // QR 코드에 담을 사용자 지정 URL
+	qrFace     string   // QR 코드 위치 (top, back)
+	qrLevel    string   // QR 코드 오류 정정 수준 (L, M, Q, H)
+	qrSize     float64  // QR 코드 한 변 길이 (mm, 여백 포함)
+	marks      []string // 강조할 날짜
+	markFile   string   // 강조할 날짜 목록 파일
+	streakKind string   // 강조할 연속 기여 (longest, current)
+	streakCol  string   // 연속 기여 레일 색상
+	streakSlot string   // 연속 기여 일수를 양각할 텍스트 슬롯
+	colorBands int      // 높이별 색 변경 계획의 강도 구간 수 (0이면 생략)
+	breakdown  bool     // 기여 유형별로 기둥을 쌓아 색 구분
+	mold       bool     // 모델 대신 주형(네거티브 몰드) 생성
+	moldWall   float64  // 주형 벽 두께 (mm)
+	baseShape  string   // 바닥판 모양 (box, rounded, chamfered, oval)
+	baseRadius float64  // 둥근 모서리 반지름 (mm)
+	baseBevel  float64  // 윗모서리 모따기 크기 (mm)
+	frame      bool     // 윗면 가장자리에 장식 테두리
+	frameWidth float64  // 테두리 폭 (mm)
+	frameHigh  float64  // 테두리 높이 (mm)
+
+	printProfile = stl.DefaultPrintProfile() // 필라멘트/출력 시간 추정 설정
+
+	timeout  time.Duration // 데이터 조회와 모델 생성 제한 시간 (0이면 제한 없음)
+	workers  int           // 동시에 보낼 조회 요청 수
+	cacheTTL time.Duration // 올해 기여 데이터 캐시 유효 기간
+	noCache  bool          // 기여 데이터 캐시 사용 안 함
+	offline  bool          // API 호출 없이 캐시만으로 생성
+	input    string        // GitHub 대신 읽을 기여 데이터 파일 (JSON, CSV)
+	export   bool          // 기여 데이터를 JSON, CSV로 함께 저장
+	org      string        // 이 조직에서 한 기여만 집계
+	hostname string        // 조회할 GitHub 호스트 (GHES 등, 기본값: gh 기본 호스트)
+
+	users      []string // 한 모델에 모을 팀원 목록
+	teamFile   string   // 팀원 목록 파일 (한 줄에 한 명)
+	teamLayout string   // 팀 배치 방식 (sum, rows)
+	teamName   string   // 팀 이름 (출력 파일 이름)
 )
 
 // rootCmd is the root command for the GitHub Skyline CLI tool.
@@ -48,6 +105,7 @@ ASCII Preview Legend:
   '▒' Medium level  - Moderate contribution activity
   '▓' High level    - Heavy contribution activity
   '╻┃╽' Top level   - Last block with contributions in the week (Low, Medium, High)
+  '▔' Streak        - Weeks of the highlighted streak (with --streak)
 
 Layout:
 Each column represents one week. Days within each week are reordered vertically
@@ -61,8 +119,9 @@ func init() {
 }
 
 // Execute initializes and executes the root command for the GitHub Skyline CLI.
-func Execute(_ context.Context) error {
-	if err := rootCmd.Execute(); err != nil {
+// Cancelling ctx aborts a running fetch or model generation.
+func Execute(ctx context.Context) error {
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		return err
 	}
 	return nil
@@ -73,19 +132,87 @@ func initFlags() {
 	flags := rootCmd.Flags()
 	flags.StringVarP(&yearRange, "year", "y", fmt.Sprintf("%d", time.Now().Year()), "Year or year range (e.g., 2024 or 2014-2024)")
 	flags.StringVarP(&user, "user", "u", "", "GitHub username (optional, defaults to authenticated user)")
+	flags.StringSliceVar(&users, "users", nil, "Generate one model for a team of users, e.g. mona,hubot (see --team-layout)")
+	flags.StringVar(&teamFile, "team-file", "", "File with the logins of a team's members, one per line, added to --users")
+	flags.StringVar(&teamLayout, "team-layout", string(skyline.TeamSum), "How a team is combined: sum (add up each day in one grid) or rows (one row per member with their name, for a single year)")
+	flags.StringVar(&teamName, "team-name", skyline.DefaultTeamName, "Name of the team, used for the output file name")
+	flags.StringVar(&hostname, "hostname", "", "GitHub host to use, e.g. a GitHub Enterprise Server instance (default: the gh default host); log in with 'gh auth login --hostname'")
+	flags.StringVar(&org, "org", "", "Count only contributions made in this organization (login)")
 	flags.BoolVarP(&full, "full", "f", false, "Generate contribution graph from join year to current year")
 	flags.BoolVarP(&debug, "debug", "d", false, "Enable debug logging")
 	flags.BoolVarP(&web, "web", "w", false, "Open GitHub profile (authenticated or specified user).")
 	flags.BoolVarP(&artOnly, "art-only", "a", false, "Generate only ASCII preview")
-	flags.StringVarP(&output, "output", "o", "", "Output file path (optional)")
+	flags.IntVar(&workers, "workers", skyline.DefaultFetchWorkers, "Number of contribution queries sent at the same time, each for a batch of years")
+	flags.DurationVar(&cacheTTL, "cache-ttl", cache.DefaultTTL, "How long cached contributions of the current year stay fresh; past years are cached for good")
+	flags.BoolVar(&noCache, "no-cache", false, "Always fetch contributions from GitHub without reading or writing the cache")
+	flags.BoolVar(&offline, "offline", false, "Generate from cached contributions only, without calling the GitHub API")
+	flags.StringVar(&input, "input", "", "Read contributions from a JSON (GitHub API response) or date,count CSV file instead of GitHub; without --year, uses every year in the file")
+	flags.BoolVar(&export, "export-data", false, "Also write the month-filtered contribution data, per day with totals, as JSON and CSV next to the model")
+	flags.DurationVar(&timeout, "timeout", 0, "Abort fetching and model generation after this long, e.g. 2m (0 for no limit)")
+	flags.StringVarP(&output, "output", "o", "", "Output file path (optional); a .3mf file keeps date markers as separate objects")
 	flags.StringVar(&topText, "top-text", "", "상단에 들어갈 텍스트 (optional)")
 	flags.IntVar(&startMonth, "start-month", 1, "시작 월 (1-12)")
 	flags.IntVar(&endMonth, "end-month", 12, "종료 월 (1-12)")
 	flags.StringVar(&rightText, "right-text", "", "우측에 들어갈 텍스트 (optional)")
+	flags.StringVar(&logoPath, "logo", "logo.png", "Image (PNG or JPEG) embossed on the left of the front face")
+	flags.StringVar(&logoRelief, "logo-relief", string(geometry.ReliefBinary), "Logo relief mode: binary (on/off) or grayscale (luminance to depth)")
+	flags.Float64Var(&logoMin, "logo-min-depth", 0, "Grayscale relief depth in mm for the darkest pixels")
+	flags.Float64Var(&logoMax, "logo-max-depth", 1.0, "Relief depth in mm for the brightest pixels")
+	flags.BoolVar(&logoInvert, "logo-invert", false, "Emboss dark pixels instead of bright ones")
+	flags.Float64Var(&logoGamma, "logo-gamma", 1.0, "Gamma applied to pixel luminance in grayscale relief mode")
+	flags.BoolVar(&noCheck, "no-check", false, "Skip the mesh defect check after generating the STL file")
+	flags.StringArrayVar(&texts, "text", nil, "Text placed in a named slot, as slot[,align=][,rotate=deg][,size=mm][,depth=mm][,font=]:text (repeatable). Slots: "+strings.Join(textSlotNames(), ", "))
+	flags.StringSliceVar(&fonts, "font", nil, "Fonts (file path or name, e.g. NanumGothic) for all text, tried in order before Mona Sans and CJK fallbacks")
+	flags.StringSliceVar(&topFonts, "top-text-font", nil, "Fonts for the top text, tried before --font")
+	flags.StringSliceVar(&rightFonts, "right-text-font", nil, "Fonts for the right text, tried before --font")
+	flags.StringSliceVar(&fontDirs, "font-dir", nil, "Additional directories searched for fonts before the system font directories")
+	flags.IntVar(&maxTris, "max-triangles", 0, "Maximum triangle count; text, logo and characters are simplified to fit (0 for no limit)")
+	addPrintProfileFlags(flags)
+	flags.BoolVar(&qrEnabled, "qr", false, "Emboss a QR code linking to the GitHub profile")
+	flags.StringVar(&qrURL, "qr-url", "", "URL encoded in the QR code instead of the profile URL (implies --qr)")
+	flags.StringVar(&qrFace, "qr-face", string(geometry.FaceTop), "Where to emboss the QR code: top (on a tab extending the base behind the grid) or back (on a plate against the back face)")
+	flags.StringVar(&qrLevel, "qr-ecc", "M", "QR code error correction level: L, M, Q or H")
+	flags.Float64Var(&qrSize, "qr-size", geometry.DefaultQRCodeSize, "QR code side length in mm, including the quiet zone")
+	flags.StringArrayVar(&marks, "mark", nil, "Date whose column is highlighted, as YYYY-MM-DD or MM-DD (every year)[,style=cap|pin|body][,color=#FFD700][,label=] (repeatable)")
+	flags.StringVar(&markFile, "mark-file", "", "File with one --mark date specification per line")
+	flags.IntVar(&colorBands, "color-bands", 0, fmt.Sprintf("Print a filament-change plan that colours columns by height in this many intensity bands (e.g. 4 like GitHub, max %d); see 'skyline filament-swap'", stl.MaxColorBands))
+	flags.StringVar(&streakKind, "streak", "", "Highlight a contribution streak with a rail on its columns and emboss its length: longest or current")
+	flags.Lookup("streak").NoOptDefVal = string(geometry.StreakLongest)
+	flags.StringVar(&streakCol, "streak-color", geometry.DefaultStreakColor, "Display colour of the streak rail in .3mf files")
+	flags.StringVar(&streakSlot, "streak-slot", geometry.SlotTopBack, fmt.Sprintf("Text slot the streak length is embossed in (%s)", strings.Join(textSlotNames(), ", ")))
+	flags.StringVar(&baseShape, "base-shape", string(geometry.BaseBox), fmt.Sprintf("Shape of the base plate: %s", strings.Join(geometry.BaseShapes(), ", ")))
+	flags.Float64Var(&baseRadius, "base-radius", geometry.DefaultBaseRadius, "Corner radius in mm of a rounded base")
+	flags.Float64Var(&baseBevel, "base-chamfer", geometry.DefaultBaseChamfer, "Size in mm of the bevel along the top edges of a chamfered base")
+	flags.BoolVar(&frame, "frame", false, "Raise a decorative frame along the edge of the base's top face")
+	flags.Float64Var(&frameWidth, "frame-width", geometry.DefaultFrameWidth, "Width of the frame in mm")
+	flags.Float64Var(&frameHigh, "frame-height", geometry.DefaultFrameHeight, "Height of the frame above the top face in mm")
+	flags.BoolVar(&breakdown, "breakdown", false, "Stack each column by contribution type (commits, pull requests, reviews, issues, other), one colour per type in .3mf output")
+	flags.BoolVar(&mold, "mold", false, "Write a negative mold of the model for casting (resin, silicone, chocolate) instead of the model")
+	flags.Float64Var(&moldWall, "mold-wall", geometry.DefaultMoldWall, "Thickness in mm of the mold's sides and floor around the cavity")
+	flags.StringArrayVar(&characters, "character", nil, "STL model merged onto the base top, as path[,scale=0.7][,height=mm][,rotate=deg][,anchor=back-right][,z=-0.5] (repeatable)")
+}
+
+// addPrintProfileFlags registers the material and printer settings used for print estimates.
+func addPrintProfileFlags(flags *pflag.FlagSet) {
+	flags.Float64Var(&printProfile.FilamentDiameter, "filament-diameter", printProfile.FilamentDiameter, "Filament diameter in mm for the print estimate")
+	flags.Float64Var(&printProfile.Density, "filament-density", printProfile.Density, "Filament density in g/cm³ for the print estimate (PLA 1.24, PETG 1.27)")
+	flags.Float64Var(&printProfile.Infill, "infill", printProfile.Infill, "Infill fraction (0-1) for the print estimate")
+	flags.Float64Var(&printProfile.LayerHeight, "layer-height", printProfile.LayerHeight, "Layer height in mm for the print estimate")
+	flags.Float64Var(&printProfile.Speed, "print-speed", printProfile.Speed, "Average print speed in mm/s for the print estimate")
+}
+
+// textSlotNames returns the names of the slots accepted by --text.
+func textSlotNames() []string {
+	var names []string
+	for _, slot := range geometry.TextSlots(1, 1, 1) {
+		names = append(names, slot.Name)
+	}
+	// One row slot per row of the grid, which depends on the model
+	return append(names, geometry.SlotRowPrefix+"N")
 }
 
 // executeRootCmd is the main execution function for the root command.
-func handleSkylineCommand(_ *cobra.Command, _ []string) error {
+func handleSkylineCommand(cmd *cobra.Command, _ []string) error {
 	log := logger.GetLogger()
 	if debug {
 		log.SetLevel(logger.DEBUG)
@@ -94,14 +221,27 @@ func handleSkylineCommand(_ *cobra.Command, _ []string) error {
 		}
 	}
 
-	client, err := github.InitializeGitHubClient()
-	if err != nil {
-		return errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+	if timeout < 0 {
+		return fmt.Errorf("invalid timeout: %v must not be negative", timeout)
+	}
+	ctx := cmd.Context()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
 	if web {
+		var host string
+		if hostname != "" {
+			host = github.ResolveHost(hostname)
+		}
+		client, err := github.InitializeGitHubClient(host)
+		if err != nil {
+			return errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+		}
 		b := browser.New("", os.Stdout, os.Stderr)
-		if err := openGitHubProfile(user, client, b); err != nil {
+		if err := openGitHubProfile(ctx, user, host, client, b); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
@@ -113,7 +253,157 @@ func handleSkylineCommand(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("invalid year range: %v", err)
 	}
 
-	return skyline.GenerateSkyline(startYear, endYear, user, full, output, artOnly, topText, startMonth, endMonth, rightText)
+	modelOpts := stl.ModelOptions{
+		TopText:      topText,
+		RightText:    rightText,
+		LogoPath:     logoPath,
+		SkipCheck:    noCheck,
+		Profile:      printProfile,
+		MaxTriangles: maxTris,
+		ColorBands:   colorBands,
+		Breakdown:    breakdown,
+		Mold:         geometry.MoldOptions{Enabled: mold, Wall: moldWall},
+		Base: geometry.BaseOptions{
+			Shape:       geometry.BaseShape(strings.ToLower(baseShape)),
+			Radius:      baseRadius,
+			Chamfer:     baseBevel,
+			Frame:       frame,
+			FrameWidth:  frameWidth,
+			FrameHeight: frameHigh,
+		},
+		Fonts: geometry.TextFonts{
+			Registry: geometry.NewFontRegistry(fontDirs...),
+			Default:  fonts,
+			Top:      topFonts,
+			Right:    rightFonts,
+		},
+		LogoRelief: geometry.ReliefOptions{
+			Mode:     geometry.ReliefMode(logoRelief),
+			MinDepth: logoMin,
+			MaxDepth: logoMax,
+			Invert:   logoInvert,
+			Gamma:    logoGamma,
+		},
+	}
+	if err := modelOpts.Base.Validate(); err != nil {
+		return fmt.Errorf("invalid base: %v", err)
+	}
+	if err := modelOpts.Mold.Validate(); err != nil {
+		return fmt.Errorf("invalid mold: %v", err)
+	}
+	// The relief options read a zero max depth or gamma as unset, so an explicit zero is rejected here
+	if cmd.Flags().Changed("logo-max-depth") && logoMax <= 0 {
+		return fmt.Errorf("invalid logo relief: --logo-max-depth must be greater than 0")
+	}
+	if cmd.Flags().Changed("logo-gamma") && logoGamma <= 0 {
+		return fmt.Errorf("invalid logo relief: --logo-gamma must be greater than 0")
+	}
+	if err := modelOpts.LogoRelief.Validate(); err != nil {
+		return fmt.Errorf("invalid logo relief: %v", err)
+	}
+	if qrEnabled || qrURL != "" {
+		level, err := qrcode.ParseLevel(qrLevel)
+		if err != nil {
+			return fmt.Errorf("invalid QR code: %v", err)
+		}
+		modelOpts.QRCode = geometry.QRCodeOptions{
+			Enabled: true,
+			Content: qrURL,
+			Level:   level,
+			Face:    geometry.Face(qrFace),
+			Size:    qrSize,
+		}
+	}
+	for _, spec := range texts {
+		text, err := geometry.ParseTextSpec(spec)
+		if err != nil {
+			return fmt.Errorf("invalid text %q: %v", spec, err)
+		}
+		modelOpts.Texts = append(modelOpts.Texts, text)
+	}
+	if markFile != "" {
+		markers, err := geometry.ReadMarkerFile(markFile)
+		if err != nil {
+			return fmt.Errorf("invalid mark file: %v", err)
+		}
+		modelOpts.Markers = append(modelOpts.Markers, markers...)
+	}
+	for _, spec := range marks {
+		marker, err := geometry.ParseMarkerSpec(spec)
+		if err != nil {
+			return fmt.Errorf("invalid mark %q: %v", spec, err)
+		}
+		modelOpts.Markers = append(modelOpts.Markers, marker)
+	}
+	modelOpts.Streak = geometry.StreakOptions{
+		Kind:  geometry.StreakKind(strings.ToLower(streakKind)),
+		Color: streakCol,
+		Slot:  streakSlot,
+	}
+	if err := modelOpts.Streak.Validate(); err != nil {
+		return fmt.Errorf("invalid streak: %v", err)
+	}
+	for _, spec := range characters {
+		character, err := stl.ParseCharacterSpec(spec)
+		if err != nil {
+			return fmt.Errorf("invalid character %q: %v", spec, err)
+		}
+		modelOpts.Characters = append(modelOpts.Characters, character)
+	}
+
+	if offline && noCache {
+		return fmt.Errorf("--offline cannot be used with --no-cache")
+	}
+	fetchOpts := skyline.FetchOptions{
+		Workers:      workers,
+		Cache:        cache.Options{Enabled: !noCache, TTL: cacheTTL, Offline: offline},
+		Input:        input,
+		Export:       export,
+		Breakdown:    breakdown,
+		Organization: org,
+		Hostname:     hostname,
+	}
+	// Without a year, a contribution file is used from its first to its last year
+	fullRange := full || (input != "" && !cmd.Flags().Changed("year"))
+	if err := fetchOpts.Validate(); err != nil {
+		return fmt.Errorf("invalid fetch options: %v", err)
+	}
+
+	if len(users) > 0 || teamFile != "" {
+		team, teamErr := buildTeam()
+		if teamErr != nil {
+			return teamErr
+		}
+		err = skyline.GenerateTeamSkyline(ctx, startYear, endYear, team, fullRange, output, artOnly, startMonth, endMonth, fetchOpts, modelOpts)
+	} else {
+		err = skyline.GenerateSkyline(ctx, startYear, endYear, user, fullRange, output, artOnly, startMonth, endMonth, fetchOpts, modelOpts)
+	}
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %v (raise --timeout): %w", timeout, err)
+	}
+	return err
+}
+
+// buildTeam returns the team given by --users and --team-file.
+func buildTeam() (skyline.Team, error) {
+	if user != "" {
+		return skyline.Team{}, fmt.Errorf("--user cannot be used with --users or --team-file")
+	}
+	team := skyline.Team{Name: teamName, Layout: skyline.TeamLayout(strings.ToLower(teamLayout))}
+	for _, member := range users {
+		team.Members = append(team.Members, strings.TrimSpace(member))
+	}
+	if teamFile != "" {
+		members, err := skyline.ReadTeamFile(teamFile)
+		if err != nil {
+			return skyline.Team{}, fmt.Errorf("invalid team file: %v", err)
+		}
+		team.Members = append(team.Members, members...)
+	}
+	if err := team.Validate(); err != nil {
+		return skyline.Team{}, fmt.Errorf("invalid team: %v", err)
+	}
+	return team, nil
 }
 
 // Browser interface matches browser.Browser functionality.
@@ -121,17 +411,16 @@ type Browser interface {
 	Browse(url string) error
 }
 
-// openGitHubProfile opens the GitHub profile page for the specified user or authenticated user.
-func openGitHubProfile(targetUser string, client skyline.GitHubClientInterface, b Browser) error {
+// openGitHubProfile opens the GitHub profile page on the host for the specified user
+// or authenticated user. An empty hostname uses the gh default host.
+func openGitHubProfile(ctx context.Context, targetUser, hostname string, client skyline.GitHubClientInterface, b Browser) error {
 	if targetUser == "" {
-		username, err := client.GetAuthenticatedUser()
+		username, err := client.GetAuthenticatedUser(ctx)
 		if err != nil {
 			return errors.New(errors.NetworkError, "failed to get authenticated user", err)
 		}
 		targetUser = username
 	}
 
-	hostname, _ := auth.DefaultHost()
-	profileURL := fmt.Sprintf("https://%s/%s", hostname, targetUser)
-	return b.Browse(profileURL)
+	return b.Browse(github.ProfileURL(github.ResolveHost(hostname), targetUser))
 }
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -47,6 +48,7 @@ func TestOpenGitHubProfile(t *testing.T) {
 	tests := []struct {
 		name       string
 		targetUser string
+		hostname   string
 		mockClient *mocks.MockGitHubClient
 		wantURL    string
 		wantErr    bool
@@ -67,6 +69,14 @@ func TestOpenGitHubProfile(t *testing.T) {
 			wantURL: "https://github.com/authuser",
 			wantErr: false,
 		},
+		{
+			name:       "enterprise host",
+			targetUser: "mona",
+			hostname:   "ghe.example.com",
+			mockClient: &mocks.MockGitHubClient{},
+			wantURL:    "https://ghe.example.com/mona",
+			wantErr:    false,
+		},
 		{
 			name:       "client error",
 			targetUser: "",
@@ -83,7 +93,7 @@ func TestOpenGitHubProfile(t *testing.T) {
 			if tt.wantErr {
 				mockBrowser.Err = fmt.Errorf("mock error")
 			}
-			err := openGitHubProfile(tt.targetUser, tt.mockClient, mockBrowser)
+			err := openGitHubProfile(context.Background(), tt.targetUser, tt.hostname, tt.mockClient, mockBrowser)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("openGitHubProfile() error = %v, wantErr %v", err, tt.wantErr)
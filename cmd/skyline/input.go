@@ -0,0 +1,85 @@
+package skyline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/github/gh-skyline/internal/datafile"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// fileClient serves contributions read from a local file in place of the GitHub API.
+type fileClient struct {
+	data datafile.Contributions
+}
+
+// newFileClient loads a contribution file.
+func newFileClient(path string) (*fileClient, error) {
+	data, err := datafile.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return &fileClient{data: data}, nil
+}
+
+// GetAuthenticatedUser returns the user named in the file.
+func (c *fileClient) GetAuthenticatedUser(context.Context) (string, error) {
+	if c.data.Login == "" {
+		return "", errors.New(errors.ValidationError, "the contribution file names no user; pass --user", nil)
+	}
+	return c.data.Login, nil
+}
+
+// GetUserJoinYear returns the first year in the file.
+func (c *fileClient) GetUserJoinYear(context.Context, string) (int, error) {
+	startYear, _ := c.data.Years()
+	return startYear, nil
+}
+
+// FetchContributions returns the contributions of a year in the file.
+func (c *fileClient) FetchContributions(_ context.Context, username string, year int) (*types.ContributionsResponse, error) {
+	if !c.data.HasYear(year) {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("the contribution file has no days in %d", year), nil)
+	}
+	response := &types.ContributionsResponse{}
+	response.User.Login = username
+	calendar := &response.User.ContributionsCollection.ContributionCalendar
+	for _, days := range c.data.Grid(year) {
+		for _, day := range days {
+			calendar.TotalContributions += day.ContributionCount
+		}
+		calendar.Weeks = append(calendar.Weeks, struct {
+			ContributionDays []types.ContributionDay `json:"contributionDays"`
+		}{ContributionDays: days})
+	}
+	return response, nil
+}
+
+// FetchContributionsBatch returns the contributions of each year in the file.
+func (c *fileClient) FetchContributionsBatch(ctx context.Context, username string, years []int) ([]github.YearResult, error) {
+	results := make([]github.YearResult, len(years))
+	for i, year := range years {
+		results[i].Year = year
+		results[i].Response, results[i].Err = c.FetchContributions(ctx, username, year)
+	}
+	return results, nil
+}
+
+// FetchContributionBreakdown returns the contributions of a year by type, as
+// saved in the file by --export-data with --breakdown.
+func (c *fileClient) FetchContributionBreakdown(_ context.Context, _ string, year int) (map[string]types.ContributionBreakdown, error) {
+	days := make(map[string]types.ContributionBreakdown)
+	for _, week := range c.data.Grid(year) {
+		for _, day := range week {
+			if day.Breakdown != nil {
+				days[day.Date] = *day.Breakdown
+			}
+		}
+	}
+	if len(days) == 0 {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("the contribution file has no breakdown by type in %d", year), nil)
+	}
+	return days, nil
+}
@@ -0,0 +1,117 @@
+package skyline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/stl"
+)
+
+func TestGenerateSkylineInput(t *testing.T) {
+	// Fail the test if the GitHub client is used at all
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func(string) (*github.Client, error) {
+		t.Fatal("GitHub client initialized with --input")
+		return nil, nil
+	}
+
+	path := filepath.Join(t.TempDir(), "contributions.csv")
+	if err := os.WriteFile(path, []byte("date,count\n2022-03-01,4\n2023-07-15,9\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		startYear  int
+		endYear    int
+		targetUser string
+		full       bool
+		wantErr    bool
+	}{
+		{name: "whole file", targetUser: "testuser", full: true},
+		{name: "one year", startYear: 2023, endYear: 2023, targetUser: "testuser"},
+		{name: "year missing from file", startYear: 2021, endYear: 2022, targetUser: "testuser", wantErr: true},
+		{name: "no user", full: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := GenerateSkyline(context.Background(), tt.startYear, tt.endYear, tt.targetUser, tt.full, "", true, 1, 12, FetchOptions{Input: path}, stl.ModelOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateSkyline() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.targetUser == "" && (err == nil || strings.Contains(err.Error(), string(errors.NetworkError)) || !strings.Contains(err.Error(), "--user")) {
+				t.Errorf("GenerateSkyline() error = %v, want a validation error asking for --user", err)
+			}
+		})
+	}
+}
+
+func TestGenerateSkylineExport(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input.csv")
+	if err := os.WriteFile(input, []byte("2023-03-01,4\n2023-07-15,9\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	output := filepath.Join(dir, "model.stl")
+	err := GenerateSkyline(context.Background(), 0, 0, "testuser", true, output, true, 3, 5, FetchOptions{Input: input, Export: true}, stl.ModelOptions{})
+	if err != nil {
+		t.Fatalf("GenerateSkyline() error = %v", err)
+	}
+
+	// Only the filtered months are exported
+	data, err := os.ReadFile(filepath.Join(dir, "model.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "2023-03-01,4") || strings.Contains(string(data), "2023-07-15") {
+		t.Errorf("exported CSV has the wrong months:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "model.json")); err != nil {
+		t.Errorf("JSON export missing: %v", err)
+	}
+}
+
+func TestGenerateSkylineInputBreakdown(t *testing.T) {
+	dir := t.TempDir()
+	withBreakdown := filepath.Join(dir, "breakdown.json")
+	data := `{"login": "testuser", "years": [{"year": 2023, "days": [
+		{"date": "2023-03-01", "contributionCount": 4, "breakdown": {"commits": 3, "reviews": 1}},
+		{"date": "2023-03-02", "contributionCount": 2}
+	]}]}`
+	if err := os.WriteFile(withBreakdown, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	plain := filepath.Join(dir, "plain.csv")
+	if err := os.WriteFile(plain, []byte("2023-03-01,4\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "file with breakdown", input: withBreakdown},
+		{name: "file without breakdown", input: plain, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(dir, "model.3mf")
+			err := GenerateSkyline(context.Background(), 0, 0, "testuser", true, output, false, 1, 12, FetchOptions{Input: tt.input, Breakdown: true}, stl.ModelOptions{Breakdown: true, SkipCheck: true})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateSkyline() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -3,11 +3,18 @@
 package skyline
 
 import (
+	"context"
+	stderrors "errors"
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/github/gh-skyline/internal/ascii"
+	"github.com/github/gh-skyline/internal/cache"
+	"github.com/github/gh-skyline/internal/datafile"
 	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/github"
 	"github.com/github/gh-skyline/internal/logger"
@@ -18,38 +25,101 @@ import (
 
 // GitHubClientInterface defines the methods for interacting with GitHub API
 type GitHubClientInterface interface {
-	GetAuthenticatedUser() (string, error)
-	GetUserJoinYear(username string) (int, error)
-	FetchContributions(username string, year int) (*types.ContributionsResponse, error)
+	GetAuthenticatedUser(ctx context.Context) (string, error)
+	GetUserJoinYear(ctx context.Context, username string) (int, error)
+	FetchContributions(ctx context.Context, username string, year int) (*types.ContributionsResponse, error)
 }
 
-// GenerateSkyline creates a 3D model with ASCII art preview of GitHub contributions for the specified year range, or "full lifetime" of the user
-func GenerateSkyline(startYear, endYear int, targetUser string, full bool, output string, artOnly bool, text string, startMonth, endMonth int, rightText string) error {
+// contributionClient is a GitHubClientInterface that can fetch several years at once.
+type contributionClient interface {
+	GitHubClientInterface
+	FetchContributionsBatch(ctx context.Context, username string, years []int) ([]github.YearResult, error)
+	FetchContributionBreakdown(ctx context.Context, username string, year int) (map[string]types.ContributionBreakdown, error)
+}
+
+// PreviewOutput receives the ASCII previews printed while generating. Set it to
+// io.Discard before generating several models at the same time.
+var PreviewOutput io.Writer = os.Stdout
+
+// DefaultFetchWorkers is the default number of contribution queries sent at the same time.
+const DefaultFetchWorkers = 4
+
+// FetchOptions controls how contribution data is fetched and whether it is saved.
+type FetchOptions struct {
+	Workers   int           // Queries sent at the same time; zero uses DefaultFetchWorkers
+	Cache     cache.Options // On-disk contribution cache; the zero value disables it
+	Input     string        // Contribution file (JSON or CSV) read instead of the GitHub API
+	Export    bool          // Also write the month-filtered data as JSON and CSV next to the model
+	Breakdown bool          // Also fetch the contributions of each day by type
+	// Organization limits the contributions to those made in the organization with this login.
+	Organization string
+	// Hostname is the GitHub host to fetch from, such as a GitHub Enterprise Server
+	// instance; empty uses the default host of the gh CLI.
+	Hostname string
+}
+
+// Validate checks the fetch options.
+func (o FetchOptions) Validate() error {
+	if o.Workers < 0 {
+		return errors.New(errors.ValidationError, "fetch worker count cannot be negative", nil)
+	}
+	if o.Organization != "" && o.Input != "" {
+		return errors.New(errors.ValidationError, "a contribution file cannot be limited to an organization", nil)
+	}
+	return o.Cache.Validate()
+}
+
+// GenerateSkyline creates a 3D model with ASCII art preview of GitHub contributions for the specified year range, or "full lifetime" of the user.
+// Cancelling ctx aborts the requests in flight and the model generation.
+func GenerateSkyline(ctx context.Context, startYear, endYear int, targetUser string, full bool, output string, artOnly bool, startMonth, endMonth int, fetchOpts FetchOptions, modelOpts stl.ModelOptions) error {
 	log := logger.GetLogger()
+	if err := fetchOpts.Validate(); err != nil {
+		return err
+	}
 
-	client, err := github.InitializeGitHubClient()
+	var client contributionClient
+	var err error
+	if fetchOpts.Input != "" {
+		client, err = newFileClient(fetchOpts.Input)
+	} else {
+		client, err = newContributionClient(ctx, fetchOpts)
+	}
 	if err != nil {
-		return errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
+		return err
 	}
 
 	if targetUser == "" {
 		if err := log.Debug("No target user specified, using authenticated user"); err != nil {
 			return err
 		}
-		username, err := client.GetAuthenticatedUser()
+		username, err := client.GetAuthenticatedUser(ctx)
 		if err != nil {
+			// A contribution file or the offline cache cannot name the user; return their validation error unwrapped
+			var skylineErr *errors.SkylineError
+			if stderrors.As(err, &skylineErr) && skylineErr.Type == errors.ValidationError {
+				return err
+			}
 			return errors.New(errors.NetworkError, "failed to get authenticated user", err)
 		}
 		targetUser = username
 	}
 
+	// Without a custom URL, the QR code links to the profile page opened by --web
+	if modelOpts.QRCode.Enabled && modelOpts.QRCode.Content == "" {
+		modelOpts.QRCode.Content = github.ProfileURL(github.ResolveHost(fetchOpts.Hostname), targetUser)
+	}
+
 	if full {
-		joinYear, err := client.GetUserJoinYear(targetUser)
+		joinYear, err := client.GetUserJoinYear(ctx, targetUser)
 		if err != nil {
 			return errors.New(errors.NetworkError, "failed to get user join year", err)
 		}
 		startYear = joinYear
 		endYear = time.Now().Year()
+		if file, ok := client.(*fileClient); ok {
+			// A file ends where its data ends
+			_, endYear = file.data.Years()
+		}
 	}
 
 	// 월 범위 검증
@@ -57,29 +127,79 @@ func GenerateSkyline(startYear, endYear int, targetUser string, full bool, outpu
 		return fmt.Errorf("invalid month range: %v", err)
 	}
 
+	fetched, err := fetchYears(ctx, client, targetUser, startYear, endYear, fetchOpts)
+	if err != nil {
+		return err
+	}
 	var allContributions [][][]types.ContributionDay
-	for year := startYear; year <= endYear; year++ {
-		contributions, err := fetchContributionData(client, targetUser, year)
+	for i, contributions := range fetched {
+		// 월 단위 필터링
+		filteredContributions := filterContributionsByMonth(contributions, startYear+i, startMonth, endMonth)
+		allContributions = append(allContributions, filteredContributions)
+	}
+
+	// The streak may run across years, so it is found once all years are fetched
+	var streak types.Streak
+	if modelOpts.Streak.Kind != "" {
+		var weeks [][]types.ContributionDay
+		for _, contributions := range allContributions {
+			weeks = append(weeks, contributions...)
+		}
+		streak = modelOpts.Streak.Find(weeks, time.Now())
+	}
+
+	usernames := make([]string, len(allContributions))
+	years := make([]int, len(allContributions))
+	for i := range allContributions {
+		usernames[i], years[i] = targetUser, startYear+i
+	}
+	if err := printPreviews(allContributions, usernames, years, artOnly, streak); err != nil {
+		return err
+	}
+
+	// Generate filename
+	outputPath := utils.GenerateOutputFilename(targetUser, startYear, endYear, output)
+
+	if fetchOpts.Export {
+		export := datafile.NewExport(targetUser, startYear, startMonth, endMonth, allContributions)
+		jsonPath, csvPath, err := export.Write(outputPath)
 		if err != nil {
 			return err
 		}
+		if err := log.Info("Contribution data written to %s and %s", jsonPath, csvPath); err != nil {
+			return err
+		}
+	}
 
-		// 월 단위 필터링
-		filteredContributions := filterContributionsByMonth(contributions, year, startMonth, endMonth)
-		allContributions = append(allContributions, filteredContributions)
+	if !artOnly {
 
-		// Generate ASCII art for each year
-		asciiArt, err := ascii.GenerateASCII(filteredContributions, targetUser, year, (year == startYear) && !artOnly, !artOnly)
+		// Generate the STL file
+		if len(allContributions) == 1 {
+			return stl.GenerateSTL(ctx, allContributions[0], outputPath, targetUser, startYear, modelOpts)
+		}
+		return stl.GenerateSTLRange(ctx, allContributions, outputPath, targetUser, startYear, endYear, modelOpts)
+	}
+
+	return nil
+}
+
+// printPreviews prints the ASCII preview of each grid row, labelled with its user
+// and year. Only the first row has the header.
+func printPreviews(rows [][][]types.ContributionDay, usernames []string, years []int, artOnly bool, streak types.Streak) error {
+	log := logger.GetLogger()
+	for i, filteredContributions := range rows {
+		// Generate ASCII art for each row
+		asciiArt, err := ascii.GenerateASCIIWithStreak(filteredContributions, usernames[i], years[i], (i == 0) && !artOnly, !artOnly, streak)
 		if err != nil {
 			if warnErr := log.Warning("Failed to generate ASCII preview: %v", err); warnErr != nil {
 				return warnErr
 			}
 		} else {
-			if year == startYear {
-				// For first year, show full ASCII art including header
-				fmt.Println(asciiArt)
+			if i == 0 {
+				// For the first row, show full ASCII art including header
+				fmt.Fprintln(PreviewOutput, asciiArt)
 			} else {
-				// For subsequent years, skip the header
+				// For subsequent rows, skip the header
 				lines := strings.Split(asciiArt, "\n")
 				gridStart := 0
 				for i, line := range lines {
@@ -93,23 +213,51 @@ func GenerateSkyline(startYear, endYear int, targetUser string, full bool, outpu
 					}
 				}
 				// Print just the grid and user info
-				fmt.Println(strings.Join(lines[gridStart:], "\n"))
+				fmt.Fprintln(PreviewOutput, strings.Join(lines[gridStart:], "\n"))
 			}
 		}
 	}
+	return nil
+}
 
-	if !artOnly {
-		// Generate filename
-		outputPath := utils.GenerateOutputFilename(targetUser, startYear, endYear, output)
-
-		// Generate the STL file
-		if len(allContributions) == 1 {
-			return stl.GenerateSTL(allContributions[0], outputPath, targetUser, startYear, text, rightText)
+// newContributionClient returns the GitHub client for the host, limited to the
+// organization if one is set and wrapped in the contribution cache when it is enabled.
+// Offline, no GitHub client is needed at all.
+func newContributionClient(ctx context.Context, opts FetchOptions) (contributionClient, error) {
+	// The host is resolved once for the client and the cache. Without a hostname the
+	// client is left to the gh defaults, which the resolved host matches.
+	host := github.ResolveHost(opts.Hostname)
+	var clientHost string
+	if opts.Hostname != "" {
+		clientHost = host
+	}
+	var source cache.Source
+	if !opts.Cache.Offline {
+		client, err := github.InitializeGitHubClient(clientHost)
+		if err != nil {
+			return nil, errors.New(errors.NetworkError, "failed to initialize GitHub client", err)
 		}
-		return stl.GenerateSTLRange(allContributions, outputPath, targetUser, startYear, endYear, text, rightText)
+		if opts.Organization != "" {
+			if err := client.ScopeToOrganization(ctx, opts.Organization); err != nil {
+				return nil, err
+			}
+			if err := logger.GetLogger().Info("Counting only contributions made in the %s organization", opts.Organization); err != nil {
+				return nil, err
+			}
+		}
+		if !opts.Cache.Enabled {
+			return client, nil
+		}
+		source = client
 	}
-
-	return nil
+	cacheOpts := opts.Cache
+	cacheOpts.Organization = opts.Organization
+	cacheOpts.Host = host
+	cached, err := cache.New(source, cacheOpts)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to open the contribution cache", err)
+	}
+	return cached, nil
 }
 
 // filterContributionsByMonth filters contributions by the specified month range
@@ -136,19 +284,85 @@ func filterContributionsByMonth(contributions [][]types.ContributionDay, year, s
 	return filteredWeeks
 }
 
-// fetchContributionData retrieves and formats the contribution data for the specified year.
-func fetchContributionData(client *github.Client, username string, year int) ([][]types.ContributionDay, error) {
-	response, err := client.FetchContributions(username, year)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch contributions: %w", err)
+// fetchYears fetches the contributions of each year from startYear to endYear
+// and returns them in year order. The years are split into batches fetched with
+// one query each, up to opts.Workers batches at the same time, so every worker has
+// a share of the years. With opts.Breakdown, each year's breakdown by type is
+// fetched after its batch. When any year fails, the error names every year that failed.
+func fetchYears(ctx context.Context, client contributionClient, username string, startYear, endYear int, opts FetchOptions) ([][][]types.ContributionDay, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultFetchWorkers
 	}
+	years := endYear - startYear + 1
+	batchSize := min((years+workers-1)/workers, github.MaxBatchYears)
+	contributions := make([][][]types.ContributionDay, years)
+	failures := make([]error, years)
 
-	// Convert weeks data to 2D array for STL generation
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, workers)
+	for first := 0; first < years; first += batchSize {
+		batch := make([]int, 0, batchSize)
+		for i := first; i < min(first+batchSize, years); i++ {
+			batch = append(batch, startYear+i)
+		}
+		wg.Add(1)
+		go func(first int, batch []int) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				for i := range batch {
+					failures[first+i] = ctx.Err()
+				}
+				return
+			}
+			results, err := client.FetchContributionsBatch(ctx, username, batch)
+			for i := range batch {
+				switch {
+				case err != nil:
+					failures[first+i] = err
+				case results[i].Err != nil:
+					failures[first+i] = fmt.Errorf("failed to fetch contributions: %w", results[i].Err)
+				case opts.Breakdown:
+					breakdown, err := client.FetchContributionBreakdown(ctx, username, batch[i])
+					if err != nil {
+						failures[first+i] = fmt.Errorf("failed to fetch contribution breakdown: %w", err)
+						continue
+					}
+					results[i].Response.ApplyBreakdown(breakdown)
+					contributions[first+i] = contributionGrid(results[i].Response)
+				default:
+					contributions[first+i] = contributionGrid(results[i].Response)
+				}
+			}
+		}(first, batch)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, errors.New(errors.NetworkError, "contribution fetch cancelled", err)
+	}
+	var failed []error
+	for i, err := range failures {
+		if err != nil {
+			failed = append(failed, fmt.Errorf("%d: %w", startYear+i, err))
+		}
+	}
+	if len(failed) > 0 {
+		return nil, errors.New(errors.NetworkError, fmt.Sprintf("failed to fetch %d of %d years", len(failed), years), stderrors.Join(failed...))
+	}
+	return contributions, nil
+}
+
+// contributionGrid converts the weeks of a contributions response into the
+// [week][day] grid used for the preview and the model.
+func contributionGrid(response *types.ContributionsResponse) [][]types.ContributionDay {
 	weeks := response.User.ContributionsCollection.ContributionCalendar.Weeks
-	contributionGrid := make([][]types.ContributionDay, len(weeks))
+	grid := make([][]types.ContributionDay, len(weeks))
 	for i, week := range weeks {
-		contributionGrid[i] = week.ContributionDays
+		grid[i] = week.ContributionDays
 	}
-
-	return contributionGrid, nil
+	return grid
 }
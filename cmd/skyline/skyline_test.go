@@ -1,11 +1,22 @@
 package skyline
 
 import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/stl"
 	"github.com/github/gh-skyline/internal/testutil/fixtures"
 	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/github/gh-skyline/internal/types"
 )
 
 func TestGenerateSkyline(t *testing.T) {
@@ -68,14 +79,180 @@ func TestGenerateSkyline(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create a closure that returns our mock client
-			github.InitializeGitHubClient = func() (*github.Client, error) {
+			github.InitializeGitHubClient = func(string) (*github.Client, error) {
 				return github.NewClient(tt.mockClient), nil
 			}
 
-			err := GenerateSkyline(tt.startYear, tt.endYear, tt.targetUser, tt.full, "", false)
+			output := filepath.Join(t.TempDir(), "skyline.stl")
+			err := GenerateSkyline(context.Background(), tt.startYear, tt.endYear, tt.targetUser, tt.full, output, false, 1, 12, FetchOptions{}, stl.ModelOptions{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GenerateSkyline() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestGenerateSkylineCancelled(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func(string) (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "testuser", JoinYear: 2020}), nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := GenerateSkyline(ctx, 2020, 2024, "testuser", false, "", false, 1, 12, FetchOptions{}, stl.ModelOptions{})
+	if !stderrors.Is(err, context.Canceled) {
+		t.Errorf("GenerateSkyline() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestGenerateSkylineHostname(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+
+	tests := []struct {
+		hostname string
+		want     string
+	}{
+		{hostname: "", want: ""},
+		{hostname: "https://GHE.example.com/", want: "ghe.example.com"},
+	}
+	for _, tt := range tests {
+		var got string
+		github.InitializeGitHubClient = func(hostname string) (*github.Client, error) {
+			got = hostname
+			return github.NewClient(&mocks.MockGitHubClient{Username: "testuser", JoinYear: 2020}), nil
+		}
+		err := GenerateSkyline(context.Background(), 2024, 2024, "testuser", false, "", true, 1, 12, FetchOptions{Hostname: tt.hostname}, stl.ModelOptions{})
+		if err != nil {
+			t.Fatalf("GenerateSkyline() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("client initialized for %q, want %q", got, tt.want)
+		}
+	}
+}
+
+// yearAPI serves contributions for the requested years, fails the listed years
+// and records the batched queries and the highest number of requests in flight.
+type yearAPI struct {
+	fail     map[int]bool
+	batches  atomic.Int32
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func (a *yearAPI) DoWithContext(_ context.Context, _ string, variables map[string]interface{}, response interface{}) error {
+	n := a.inFlight.Add(1)
+	defer a.inFlight.Add(-1)
+	for {
+		peak := a.peak.Load()
+		if n <= peak || a.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	username := variables["username"].(string)
+	if v, ok := response.(*types.ContributionsResponse); ok {
+		year, _ := strconv.Atoi(variables["from"].(string)[:4])
+		if a.fail[year] {
+			return fmt.Errorf("server error for %d", year)
+		}
+		*v = *fixtures.GenerateContributionsResponse(username, year)
+		return nil
+	}
+
+	// A batched query fails as a whole when any of its years fails
+	a.batches.Add(1)
+	user := map[string]interface{}{"login": username}
+	for name, value := range variables {
+		if !strings.HasPrefix(name, "from") {
+			continue
+		}
+		year, _ := strconv.Atoi(value.(string)[:4])
+		if a.fail[year] {
+			return fmt.Errorf("server error for %d", year)
+		}
+		user[fmt.Sprintf("y%d", year)] = fixtures.GenerateContributionsResponse(username, year).User.ContributionsCollection
+	}
+	data, err := json.Marshal(map[string]interface{}{"user": user})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+func TestFetchYears(t *testing.T) {
+	tests := []struct {
+		name       string
+		workers    int
+		fail       map[int]bool
+		wantBatch  int      // Batched queries sent
+		wantErr    []string // Years named in the error
+		wantNotErr []string
+	}{
+		{name: "one worker", workers: 1, wantBatch: 1},
+		{name: "default workers", wantBatch: 4},
+		{name: "more workers than years", workers: 20, wantBatch: 10},
+		{name: "partial failure", workers: 3, fail: map[int]bool{2012: true, 2017: true}, wantBatch: 3, wantErr: []string{"2 of 10 years", "2012: ", "2017: "}, wantNotErr: []string{"2013: "}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := &yearAPI{fail: tt.fail}
+			got, err := fetchYears(context.Background(), github.NewClient(api), "testuser", 2010, 2019, FetchOptions{Workers: tt.workers})
+
+			limit := tt.workers
+			if limit == 0 {
+				limit = DefaultFetchWorkers
+			}
+			if peak := int(api.peak.Load()); peak > limit {
+				t.Errorf("%d requests in flight, want at most %d", peak, limit)
+			}
+			if batches := int(api.batches.Load()); batches != tt.wantBatch {
+				t.Errorf("sent %d batched queries, want %d", batches, tt.wantBatch)
+			}
+
+			if len(tt.wantErr) > 0 {
+				if err == nil {
+					t.Fatal("fetchYears() expected error")
+				}
+				for _, want := range tt.wantErr {
+					if !strings.Contains(err.Error(), want) {
+						t.Errorf("error %q does not mention %q", err, want)
+					}
+				}
+				for _, unwanted := range tt.wantNotErr {
+					if strings.Contains(err.Error(), unwanted) {
+						t.Errorf("error %q mentions %q", err, unwanted)
+					}
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("fetchYears() error = %v", err)
+			}
+			if len(got) != 10 {
+				t.Fatalf("got %d years, want 10", len(got))
+			}
+			for i, weeks := range got {
+				if want := fmt.Sprintf("%d-", 2010+i); !strings.HasPrefix(weeks[0][0].Date, want) {
+					t.Errorf("year %d starts on %s", 2010+i, weeks[0][0].Date)
+				}
+			}
+		})
+	}
+
+	if err := (FetchOptions{Organization: "acme", Input: "contributions.csv"}).Validate(); err == nil {
+		t.Error("Validate() accepted an organization for a contribution file")
+	}
+	if err := (FetchOptions{Workers: -1}).Validate(); err == nil {
+		t.Error("Validate() expected error for a negative worker count")
+	}
+}
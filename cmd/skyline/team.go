@@ -0,0 +1,245 @@
+package skyline
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/datafile"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/types"
+	"github.com/github/gh-skyline/internal/utils"
+)
+
+// TeamLayout is how the contributions of a team's members are combined in one model.
+type TeamLayout string
+
+// Supported team layouts.
+const (
+	TeamSum  TeamLayout = "sum"  // Add up the members' contributions of each day in one grid
+	TeamRows TeamLayout = "rows" // One grid row per member on a shared base, each with the member's name
+)
+
+// DefaultTeamName names the model of a team without a name.
+const DefaultTeamName = "team"
+
+// Team is a group of users whose contributions go into one model.
+type Team struct {
+	Name    string     // Name used for the output file and preview; empty uses DefaultTeamName
+	Members []string   // Logins of the members, from the back row to the front in the rows layout
+	Layout  TeamLayout // Empty uses TeamSum
+}
+
+// Validate checks the team's members and layout.
+func (t Team) Validate() error {
+	if len(t.Members) == 0 {
+		return errors.New(errors.ValidationError, "a team needs at least one member", nil)
+	}
+	seen := make(map[string]bool, len(t.Members))
+	for _, member := range t.Members {
+		if member == "" {
+			return errors.New(errors.ValidationError, "team member login cannot be empty", nil)
+		}
+		if seen[strings.ToLower(member)] {
+			return errors.New(errors.ValidationError, fmt.Sprintf("team member %s is listed twice", member), nil)
+		}
+		seen[strings.ToLower(member)] = true
+	}
+	switch t.Layout {
+	case "", TeamSum, TeamRows:
+		return nil
+	default:
+		return errors.New(errors.ValidationError, fmt.Sprintf("unknown team layout %q (expected %s or %s)", t.Layout, TeamSum, TeamRows), nil)
+	}
+}
+
+// ReadTeamFile reads the logins of a team's members, one per line. Blank lines
+// and lines starting with # are skipped.
+func ReadTeamFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to open team file", err)
+	}
+	defer file.Close()
+
+	var members []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		members = append(members, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New(errors.IOError, "failed to read team file", err)
+	}
+	return members, nil
+}
+
+// GenerateTeamSkyline creates one model with ASCII art preview of the contributions
+// of a team's members. The sum layout adds them up per day over the year range;
+// the rows layout gives each member a row of a single year. Either way, the column
+// heights share one normalization maximum, so the members compare fairly.
+func GenerateTeamSkyline(ctx context.Context, startYear, endYear int, team Team, full bool, output string, artOnly bool, startMonth, endMonth int, fetchOpts FetchOptions, modelOpts stl.ModelOptions) error {
+	log := logger.GetLogger()
+	if err := team.Validate(); err != nil {
+		return err
+	}
+	if team.Name == "" {
+		team.Name = DefaultTeamName
+	}
+	if team.Layout == "" {
+		team.Layout = TeamSum
+	}
+	if err := fetchOpts.Validate(); err != nil {
+		return err
+	}
+	if fetchOpts.Input != "" {
+		return errors.New(errors.ValidationError, "a contribution file holds a single user; it cannot be used for a team", nil)
+	}
+	if modelOpts.QRCode.Enabled && modelOpts.QRCode.Content == "" {
+		return errors.New(errors.ValidationError, "a team has no profile page; set the URL of the QR code", nil)
+	}
+	if team.Layout == TeamRows {
+		if full || startYear != endYear {
+			return errors.New(errors.ValidationError, "the rows layout shows one year per member; choose a single year", nil)
+		}
+		if modelOpts.Streak.Kind != "" {
+			return errors.New(errors.ValidationError, "a streak cannot run across members; it cannot be used with the rows layout", nil)
+		}
+		if fetchOpts.Export {
+			return errors.New(errors.ValidationError, "the rows layout has no combined contribution data to export; use the sum layout", nil)
+		}
+	}
+	if err := utils.ValidateMonthRange(startMonth, endMonth); err != nil {
+		return fmt.Errorf("invalid month range: %v", err)
+	}
+
+	client, err := newContributionClient(ctx, fetchOpts)
+	if err != nil {
+		return err
+	}
+
+	if full {
+		// The model starts when the first member joined
+		startYear, endYear = time.Now().Year(), time.Now().Year()
+		for _, member := range team.Members {
+			joinYear, err := client.GetUserJoinYear(ctx, member)
+			if err != nil {
+				return errors.New(errors.NetworkError, fmt.Sprintf("failed to get join year of %s", member), err)
+			}
+			startYear = min(startYear, joinYear)
+		}
+	}
+
+	if err := log.Info("Fetching contributions of %d team members", len(team.Members)); err != nil {
+		return err
+	}
+	members := make([][][][]types.ContributionDay, len(team.Members))
+	for i, member := range team.Members {
+		fetched, err := fetchYears(ctx, client, member, startYear, endYear, fetchOpts)
+		if err != nil {
+			return errors.Wrap(err, fmt.Sprintf("failed to fetch contributions of %s", member))
+		}
+		for y := range fetched {
+			fetched[y] = filterContributionsByMonth(fetched[y], startYear+y, startMonth, endMonth)
+		}
+		members[i] = fetched
+	}
+
+	var rows [][][]types.ContributionDay
+	var usernames []string
+	var years []int
+	if team.Layout == TeamRows {
+		for i, member := range team.Members {
+			rows = append(rows, members[i][0])
+			usernames = append(usernames, member)
+			years = append(years, startYear)
+		}
+		modelOpts.RowLabels = team.Members
+	} else {
+		for y := 0; y <= endYear-startYear; y++ {
+			year := make([][][]types.ContributionDay, len(members))
+			for i := range members {
+				year[i] = members[i][y]
+			}
+			rows = append(rows, sumContributions(year))
+			usernames = append(usernames, team.Name)
+			years = append(years, startYear+y)
+		}
+	}
+
+	var streak types.Streak
+	if modelOpts.Streak.Kind != "" {
+		var weeks [][]types.ContributionDay
+		for _, contributions := range rows {
+			weeks = append(weeks, contributions...)
+		}
+		streak = modelOpts.Streak.Find(weeks, time.Now())
+	}
+	if err := printPreviews(rows, usernames, years, artOnly, streak); err != nil {
+		return err
+	}
+
+	outputPath := utils.GenerateOutputFilename(team.Name, startYear, endYear, output)
+
+	if fetchOpts.Export {
+		export := datafile.NewExport(team.Name, startYear, startMonth, endMonth, rows)
+		jsonPath, csvPath, err := export.Write(outputPath)
+		if err != nil {
+			return err
+		}
+		if err := log.Info("Contribution data written to %s and %s", jsonPath, csvPath); err != nil {
+			return err
+		}
+	}
+
+	if artOnly {
+		return nil
+	}
+	return stl.GenerateSTLRange(ctx, rows, outputPath, team.Name, startYear, endYear, modelOpts)
+}
+
+// sumContributions adds up the members' contributions of each day of a year. The
+// members' grids cover the same calendar, so the first one gives the layout.
+func sumContributions(members [][][]types.ContributionDay) [][]types.ContributionDay {
+	totals := make(map[string]types.ContributionDay)
+	for _, weeks := range members {
+		for _, week := range weeks {
+			for _, day := range week {
+				total := totals[day.Date]
+				total.ContributionCount += day.ContributionCount
+				if day.Breakdown != nil {
+					breakdown := types.ContributionBreakdown{}
+					if total.Breakdown != nil {
+						breakdown = *total.Breakdown
+					}
+					breakdown.Commits += day.Breakdown.Commits
+					breakdown.PullRequests += day.Breakdown.PullRequests
+					breakdown.Reviews += day.Breakdown.Reviews
+					breakdown.Issues += day.Breakdown.Issues
+					breakdown.Other += day.Breakdown.Other
+					total.Breakdown = &breakdown
+				}
+				totals[day.Date] = total
+			}
+		}
+	}
+
+	grid := make([][]types.ContributionDay, len(members[0]))
+	for w, week := range members[0] {
+		grid[w] = make([]types.ContributionDay, len(week))
+		for d, day := range week {
+			total := totals[day.Date]
+			total.Date = day.Date
+			grid[w][d] = total
+		}
+	}
+	return grid
+}
@@ -0,0 +1,125 @@
+package skyline
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/testutil/mocks"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestTeamValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		team    Team
+		wantErr bool
+	}{
+		{name: "sum", team: Team{Members: []string{"mona", "hubot"}}},
+		{name: "rows", team: Team{Members: []string{"mona", "hubot"}, Layout: TeamRows}},
+		{name: "no members", team: Team{}, wantErr: true},
+		{name: "empty login", team: Team{Members: []string{"mona", ""}}, wantErr: true},
+		{name: "duplicate member", team: Team{Members: []string{"mona", "Mona"}}, wantErr: true},
+		{name: "unknown layout", team: Team{Members: []string{"mona"}, Layout: "grid"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.team.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadTeamFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "team.txt")
+	if err := os.WriteFile(path, []byte("# Platform team\nmona\n\n  hubot  \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	members, err := ReadTeamFile(path)
+	if err != nil {
+		t.Fatalf("ReadTeamFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(members, []string{"mona", "hubot"}) {
+		t.Errorf("ReadTeamFile() = %v, want [mona hubot]", members)
+	}
+
+	if _, err := ReadTeamFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("ReadTeamFile() succeeded for a missing file")
+	}
+}
+
+func TestSumContributions(t *testing.T) {
+	mona := [][]types.ContributionDay{
+		{{Date: "2024-01-01", ContributionCount: 2, Breakdown: &types.ContributionBreakdown{Commits: 2}}},
+		{{Date: "2024-01-07", ContributionCount: 0}},
+	}
+	hubot := [][]types.ContributionDay{
+		{{Date: "2024-01-01", ContributionCount: 3, Breakdown: &types.ContributionBreakdown{Reviews: 1, Other: 2}}},
+		{{Date: "2024-01-07", ContributionCount: 5}},
+	}
+
+	got := sumContributions([][][]types.ContributionDay{mona, hubot})
+	want := [][]types.ContributionDay{
+		{{Date: "2024-01-01", ContributionCount: 5, Breakdown: &types.ContributionBreakdown{Commits: 2, Reviews: 1, Other: 2}}},
+		{{Date: "2024-01-07", ContributionCount: 5}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sumContributions() = %+v, want %+v", got, want)
+	}
+	if mona[0][0].Breakdown.Reviews != 0 {
+		t.Error("sumContributions() changed a member's breakdown")
+	}
+}
+
+func TestGenerateTeamSkyline(t *testing.T) {
+	originalInit := github.InitializeGitHubClient
+	defer func() {
+		github.InitializeGitHubClient = originalInit
+	}()
+	github.InitializeGitHubClient = func(string) (*github.Client, error) {
+		return github.NewClient(&mocks.MockGitHubClient{Username: "mona", JoinYear: 2020}), nil
+	}
+
+	members := []string{"mona", "hubot", "octocat"}
+	tests := []struct {
+		name      string
+		startYear int
+		endYear   int
+		team      Team
+		fetchOpts FetchOptions
+		modelOpts stl.ModelOptions
+		wantErr   bool
+	}{
+		{name: "sum", startYear: 2022, endYear: 2023, team: Team{Members: members}},
+		{name: "rows", startYear: 2023, endYear: 2023, team: Team{Name: "platform", Members: members, Layout: TeamRows}},
+		{name: "rows over several years", startYear: 2022, endYear: 2023, team: Team{Members: members, Layout: TeamRows}, wantErr: true},
+		{name: "rows with a streak", startYear: 2023, endYear: 2023, team: Team{Members: members, Layout: TeamRows},
+			modelOpts: stl.ModelOptions{Streak: geometry.StreakOptions{Kind: geometry.StreakLongest}}, wantErr: true},
+		{name: "rows export", startYear: 2023, endYear: 2023, team: Team{Members: members, Layout: TeamRows}, fetchOpts: FetchOptions{Export: true}, wantErr: true},
+		{name: "contribution file", startYear: 2023, endYear: 2023, team: Team{Members: members}, fetchOpts: FetchOptions{Input: "contributions.csv"}, wantErr: true},
+		{name: "QR code without URL", startYear: 2023, endYear: 2023, team: Team{Members: members},
+			modelOpts: stl.ModelOptions{QRCode: geometry.QRCodeOptions{Enabled: true}}, wantErr: true},
+		{name: "invalid team", startYear: 2023, endYear: 2023, team: Team{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "team.stl")
+			tt.modelOpts.SkipCheck = true
+			err := GenerateTeamSkyline(context.Background(), tt.startYear, tt.endYear, tt.team, false, output, false, 1, 12, tt.fetchOpts, tt.modelOpts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateTeamSkyline() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if _, statErr := os.Stat(output); !tt.wantErr && statErr != nil {
+				t.Errorf("model not written: %v", statErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/spf13/cobra"
+)
+
+// validateCmd checks an existing STL file for mesh defects.
+var validateCmd = &cobra.Command{
+	Use:   "validate <file.stl>",
+	Short: "Check an STL file for mesh defects",
+	Long: `Validate loads a binary or ASCII STL file and reports mesh defects that
+can break slicing or printing:
+
+  - open boundary edges (holes)
+  - non-manifold edges (shared by more than two triangles, other than edges
+    where two closed boxes touch, as in generated models)
+  - edges with inconsistent winding between neighbouring triangles
+  - normals pointing against the vertex order
+  - degenerate (zero-area) triangles
+  - shells whose volumes overlap each other
+
+The command exits with a non-zero status when any defect is found.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runValidate(args[0], cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+// runValidate reads the STL file, prints the mesh report and fails if defects were found.
+func runValidate(path string, out io.Writer) error {
+	triangles, err := stl.ReadSTL(path)
+	if err != nil {
+		return err
+	}
+
+	report := geometry.ValidateMesh(triangles)
+	fmt.Fprintf(out, "%s\n%s", path, report)
+
+	if report.HasDefects() {
+		return errors.New(errors.ValidationError, fmt.Sprintf("%s has mesh defects", path), nil)
+	}
+	return nil
+}
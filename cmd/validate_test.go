@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+)
+
+func TestRunValidate(t *testing.T) {
+	cube, err := geometry.CreateCube(0, 0, 0, 1, 1, 1)
+	if err != nil {
+		t.Fatalf("CreateCube() error = %v", err)
+	}
+	dir := t.TempDir()
+
+	goodPath := filepath.Join(dir, "good.stl")
+	if err := stl.WriteSTLBinary(goodPath, cube); err != nil {
+		t.Fatal(err)
+	}
+	badPath := filepath.Join(dir, "bad.stl")
+	if err := stl.WriteSTLBinary(badPath, cube[2:]); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		path     string
+		wantErr  bool
+		wantText string
+	}{
+		{"valid mesh", goodPath, false, "No defects found"},
+		{"mesh with hole", badPath, true, "open boundary edges"},
+		{"missing file", filepath.Join(dir, "missing.stl"), true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := runValidate(tt.path, &out)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("runValidate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.wantText) {
+				t.Errorf("runValidate() output = %q, want %q", out.String(), tt.wantText)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ const (
 	// Basic blocks
 	EmptyBlock  = ' ' // Represents days with no contributions
 	FutureBlock = '.' // Represents future dates
+	StreakBlock = '▔' // Underlines the weeks of a highlighted streak
 
 	// Foundation blocks (bottom layer)
 	FoundationLow  = '░' // 1-33% intensity
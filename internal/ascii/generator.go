@@ -17,6 +17,13 @@ var ErrInvalidGrid = errors.New("invalid contribution grid")
 // It returns the generated ASCII art as a string and an error if the operation fails.
 // When includeHeader is true, the output includes the header template.
 func GenerateASCII(contributionGrid [][]types.ContributionDay, username string, year int, includeHeader bool, includeUserInfo bool) (string, error) {
+	return GenerateASCIIWithStreak(contributionGrid, username, year, includeHeader, includeUserInfo, types.Streak{})
+}
+
+// GenerateASCIIWithStreak works like GenerateASCII and highlights a streak: the
+// weeks it runs through are underlined below the grid, and its length is shown
+// with the user info. A streak outside the grid is not highlighted.
+func GenerateASCIIWithStreak(contributionGrid [][]types.ContributionDay, username string, year int, includeHeader bool, includeUserInfo bool, streak types.Streak) (string, error) {
 	if len(contributionGrid) == 0 {
 		return "", ErrInvalidGrid
 	}
@@ -77,16 +84,42 @@ func GenerateASCII(contributionGrid [][]types.ContributionDay, username string,
 		buffer.WriteRune('\n')
 	}
 
+	streakLine, inGrid := streakRun(contributionGrid, streak)
+	if inGrid {
+		buffer.WriteString(streakLine + "\n")
+	}
+
 	if includeUserInfo {
 		// Add centered user info below
 		buffer.WriteString("\n")
 		buffer.WriteString(centerText(username))
 		buffer.WriteString(centerText(fmt.Sprintf("%d", year)))
+		if inGrid {
+			buffer.WriteString(centerText(fmt.Sprintf("%d-day streak (%s to %s)", streak.Length, streak.Start, streak.End)))
+		}
 	}
 
 	return buffer.String(), nil
 }
 
+// streakRun returns a line that underlines the weeks of the grid the streak runs
+// through, and false when none of its days are in the grid.
+func streakRun(contributionGrid [][]types.ContributionDay, streak types.Streak) (string, bool) {
+	line := make([]rune, len(contributionGrid))
+	found := false
+	for weekIdx, week := range contributionGrid {
+		line[weekIdx] = EmptyBlock
+		for _, day := range week {
+			if streak.Contains(day.Date) {
+				line[weekIdx] = StreakBlock
+				found = true
+				break
+			}
+		}
+	}
+	return strings.TrimRight(string(line), string(EmptyBlock)), found
+}
+
 // sortContributionDays sorts the contribution days within a week.
 // It places non-zero contributions first, followed by zero contributions, and future dates last.
 func sortContributionDays(week []types.ContributionDay, now time.Time) ([]types.ContributionDay, int) {
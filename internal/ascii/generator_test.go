@@ -179,3 +179,51 @@ func TestGenerateASCIIZeroContributions(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateASCIIWithStreak(t *testing.T) {
+	grid := [][]types.ContributionDay{
+		{{Date: "2020-03-01", ContributionCount: 1}, {Date: "2020-03-02", ContributionCount: 0}},
+		{{Date: "2020-03-08", ContributionCount: 2}, {Date: "2020-03-09", ContributionCount: 2}},
+		{{Date: "2020-03-15", ContributionCount: 3}, {Date: "2020-03-16", ContributionCount: 0}},
+		{{Date: "2020-03-22", ContributionCount: 1}, {Date: "2020-03-23", ContributionCount: 0}},
+	}
+
+	tests := []struct {
+		name       string
+		streak     types.Streak
+		wantLine   string
+		wantLength bool
+	}{
+		{name: "no streak"},
+		{
+			name:       "streak across weeks",
+			streak:     types.Streak{Length: 8, Start: "2020-03-09", End: "2020-03-16"},
+			wantLine:   " ▔▔",
+			wantLength: true,
+		},
+		{
+			name:   "streak outside the grid",
+			streak: types.Streak{Length: 3, Start: "2021-01-01", End: "2021-01-03"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GenerateASCIIWithStreak(grid, "testuser", 2020, false, true, tt.streak)
+			if err != nil {
+				t.Fatalf("GenerateASCIIWithStreak() error = %v", err)
+			}
+			lines := strings.Split(result, "\n")
+			// 7 grid rows, then the streak line when the streak is in the grid
+			if got := strings.ContainsRune(result, StreakBlock); got != (tt.wantLine != "") {
+				t.Errorf("streak line present = %v, want %v", got, tt.wantLine != "")
+			}
+			if tt.wantLine != "" && lines[7] != tt.wantLine {
+				t.Errorf("streak line = %q, want %q", lines[7], tt.wantLine)
+			}
+			if got := strings.Contains(result, "-day streak"); got != tt.wantLength {
+				t.Errorf("streak length shown = %v, want %v", got, tt.wantLength)
+			}
+		})
+	}
+}
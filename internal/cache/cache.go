@@ -0,0 +1,351 @@
+// Package cache provides an on-disk cache of GitHub contribution data, so the
+// same models can be regenerated without calling the API every time.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// DefaultTTL is how long contributions of a year that has not ended stay fresh.
+const DefaultTTL = time.Hour
+
+// Source is the GitHub client the cache reads through.
+type Source interface {
+	GetAuthenticatedUser(ctx context.Context) (string, error)
+	GetUserJoinYear(ctx context.Context, username string) (int, error)
+	FetchContributions(ctx context.Context, username string, year int) (*types.ContributionsResponse, error)
+	FetchContributionsBatch(ctx context.Context, username string, years []int) ([]github.YearResult, error)
+	FetchContributionBreakdown(ctx context.Context, username string, year int) (map[string]types.ContributionBreakdown, error)
+}
+
+// defaultHost is the host whose contributions are cached at the top of the cache directory.
+const defaultHost = "github.com"
+
+// Options controls the contribution cache. The zero value disables it.
+type Options struct {
+	Enabled bool
+	Dir     string        // Cache directory; empty uses DefaultDir
+	TTL     time.Duration // How long the current year's contributions stay fresh; zero always fetches them
+	Offline bool          // Serve everything from the cache and never call the API
+	// Organization keeps the contributions of a source limited to an organization
+	// apart from the user's other contributions.
+	Organization string
+	// Host keeps the contributions of a GitHub Enterprise Server instance apart from
+	// those of github.com, where users of the same login are different people.
+	// Empty means github.com.
+	Host string
+}
+
+// Validate checks the cache options.
+func (o Options) Validate() error {
+	if o.TTL < 0 {
+		return errors.New(errors.ValidationError, "cache TTL cannot be negative", nil)
+	}
+	if o.Offline && !o.Enabled {
+		return errors.New(errors.ValidationError, "offline mode needs the cache", nil)
+	}
+	return nil
+}
+
+// DefaultDir returns the cache directory under the user cache directory.
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", errors.New(errors.IOError, "failed to find the user cache directory", err)
+	}
+	return filepath.Join(dir, "gh-skyline"), nil
+}
+
+// Client wraps a GitHub client and stores contributions per user and year on disk.
+// A year fetched after it ended never changes and is kept for good; other years
+// are fetched again once they are older than the TTL.
+type Client struct {
+	source Source // Nil in offline mode
+	dir    string
+	scope  string // Organization the contributions are limited to, if any
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// entry is a cached response with the time it was fetched.
+type entry struct {
+	FetchedAt time.Time                    `json:"fetchedAt"`
+	Response  *types.ContributionsResponse `json:"response"`
+}
+
+// breakdownEntry is a cached contribution breakdown with the time it was fetched.
+type breakdownEntry struct {
+	FetchedAt time.Time                              `json:"fetchedAt"`
+	Days      map[string]types.ContributionBreakdown `json:"days"`
+}
+
+// profile holds the cached account details of a user.
+type profile struct {
+	JoinYear int `json:"joinYear"`
+}
+
+// New returns a caching client reading through source. In offline mode source
+// may be nil, as it is never called.
+func New(source Source, opts Options) (*Client, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	dir := opts.Dir
+	if dir == "" {
+		var err error
+		if dir, err = DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Offline {
+		source = nil
+	}
+	if opts.Organization != "" && !validName(opts.Organization) {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid organization %q", opts.Organization), nil)
+	}
+	if host := strings.ToLower(opts.Host); host != "" && host != defaultHost {
+		if !validName(host) {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid host %q", opts.Host), nil)
+		}
+		dir = filepath.Join(dir, "hosts", host)
+	}
+	return &Client{source: source, dir: filepath.Join(dir, "contributions"), scope: strings.ToLower(opts.Organization), ttl: opts.TTL, now: time.Now}, nil
+}
+
+// GetAuthenticatedUser returns the authenticated user from the API; it is not cached.
+func (c *Client) GetAuthenticatedUser(ctx context.Context) (string, error) {
+	if c.source == nil {
+		return "", errors.New(errors.ValidationError, "the authenticated user is not available offline; pass --user", nil)
+	}
+	return c.source.GetAuthenticatedUser(ctx)
+}
+
+// GetUserJoinYear returns the year the user joined GitHub, which never changes
+// and is cached for good.
+func (c *Client) GetUserJoinYear(ctx context.Context, username string) (int, error) {
+	dir, err := c.userDir(username)
+	if err != nil {
+		return 0, err
+	}
+	path := filepath.Join(dir, "profile.json")
+
+	var cached profile
+	if err := readJSON(path, &cached); err == nil && cached.JoinYear > 0 {
+		return cached.JoinYear, nil
+	}
+	if c.source == nil {
+		return 0, errors.New(errors.IOError, fmt.Sprintf("join year of %s is not cached", username), nil)
+	}
+
+	joinYear, err := c.source.GetUserJoinYear(ctx, username)
+	if err != nil {
+		return 0, err
+	}
+	if err := writeJSON(path, profile{JoinYear: joinYear}); err != nil {
+		if logErr := logger.GetLogger().Warning("Failed to cache the join year of %s: %v", username, err); logErr != nil {
+			return 0, logErr
+		}
+	}
+	return joinYear, nil
+}
+
+// FetchContributions returns the contributions of a year from the cache, or
+// from the API when the cached copy is missing or stale.
+func (c *Client) FetchContributions(ctx context.Context, username string, year int) (*types.ContributionsResponse, error) {
+	results, err := c.FetchContributionsBatch(ctx, username, []int{year})
+	if err != nil {
+		return nil, err
+	}
+	return results[0].Response, results[0].Err
+}
+
+// FetchContributionsBatch returns the contributions of the years, fetching the
+// years that are not fresh in the cache with one batched request. A year the
+// API fails to return is served from a stale copy when there is one.
+func (c *Client) FetchContributionsBatch(ctx context.Context, username string, years []int) ([]github.YearResult, error) {
+	dir, err := c.contributionDir(username)
+	if err != nil {
+		return nil, err
+	}
+	log := logger.GetLogger()
+
+	results := make([]github.YearResult, len(years))
+	stale := make(map[int]*types.ContributionsResponse)
+	var missing []int
+	var missingAt []int // Index of each missing year in results
+	for i, year := range years {
+		results[i].Year = year
+		var cached entry
+		if err := readJSON(c.yearPath(dir, year), &cached); err == nil && cached.Response != nil {
+			if c.fresh(cached.FetchedAt, year) {
+				results[i].Response = cached.Response
+				continue
+			}
+			stale[year] = cached.Response
+		}
+		missing = append(missing, year)
+		missingAt = append(missingAt, i)
+	}
+	if err := log.Debug("Contribution cache for %s: %d of %d years fresh", username, len(years)-len(missing), len(years)); err != nil {
+		return nil, err
+	}
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	var fetched []github.YearResult
+	if c.source == nil {
+		fetched = make([]github.YearResult, len(missing))
+		for i, year := range missing {
+			fetched[i] = github.YearResult{Year: year, Err: errors.New(errors.IOError, fmt.Sprintf("contributions of %s in %d are not cached", username, year), nil)}
+		}
+	} else if fetched, err = c.source.FetchContributionsBatch(ctx, username, missing); err != nil {
+		return nil, err
+	}
+
+	fetchedAt := c.now()
+	for i, result := range fetched {
+		index, year := missingAt[i], missing[i]
+		if result.Err != nil {
+			if response, ok := stale[year]; ok {
+				if err := log.Warning("Using cached contributions of %s in %d that may be out of date: %v", username, year, result.Err); err != nil {
+					return nil, err
+				}
+				results[index].Response = response
+				continue
+			}
+			results[index].Err = result.Err
+			continue
+		}
+		results[index].Response = result.Response
+		if err := writeJSON(c.yearPath(dir, year), entry{FetchedAt: fetchedAt, Response: result.Response}); err != nil {
+			if err := log.Warning("Failed to cache the contributions of %s in %d: %v", username, year, err); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return results, nil
+}
+
+// FetchContributionBreakdown returns the contributions of a year by type from
+// the cache, or from the API when the cached copy is missing or stale.
+func (c *Client) FetchContributionBreakdown(ctx context.Context, username string, year int) (map[string]types.ContributionBreakdown, error) {
+	dir, err := c.contributionDir(username)
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, fmt.Sprintf("%d-breakdown.json", year))
+
+	var cached breakdownEntry
+	found := readJSON(path, &cached) == nil && cached.Days != nil
+	if found && c.fresh(cached.FetchedAt, year) {
+		return cached.Days, nil
+	}
+	if c.source == nil {
+		if found {
+			return cached.Days, nil
+		}
+		return nil, errors.New(errors.IOError, fmt.Sprintf("contribution breakdown of %s in %d is not cached", username, year), nil)
+	}
+
+	fetchedAt := c.now()
+	days, err := c.source.FetchContributionBreakdown(ctx, username, year)
+	log := logger.GetLogger()
+	if err != nil {
+		if !found || ctx.Err() != nil {
+			return nil, err
+		}
+		if err := log.Warning("Using cached contribution breakdown of %s in %d that may be out of date: %v", username, year, err); err != nil {
+			return nil, err
+		}
+		return cached.Days, nil
+	}
+	if err := writeJSON(path, breakdownEntry{FetchedAt: fetchedAt, Days: days}); err != nil {
+		if err := log.Warning("Failed to cache the contribution breakdown of %s in %d: %v", username, year, err); err != nil {
+			return nil, err
+		}
+	}
+	return days, nil
+}
+
+// fresh reports whether a cached year can be used without fetching it again.
+func (c *Client) fresh(fetchedAt time.Time, year int) bool {
+	if fetchedAt.UTC().Year() > year {
+		return true // Fetched after the year ended in UTC, so it is complete
+	}
+	return c.now().Sub(fetchedAt) < c.ttl
+}
+
+// userDir returns the cache directory of a user, rejecting names that are not
+// a single path element.
+func (c *Client) userDir(username string) (string, error) {
+	if !validName(username) {
+		return "", errors.New(errors.ValidationError, fmt.Sprintf("invalid username %q", username), nil)
+	}
+	return filepath.Join(c.dir, strings.ToLower(username)), nil
+}
+
+// contributionDir returns the directory of the user's cached contributions,
+// separate for each organization they are limited to.
+func (c *Client) contributionDir(username string) (string, error) {
+	dir, err := c.userDir(username)
+	if err != nil || c.scope == "" {
+		return dir, err
+	}
+	return filepath.Join(dir, "orgs", c.scope), nil
+}
+
+// validName reports whether a login can be used as a single path element.
+func validName(name string) bool {
+	return name != "" && name != "." && name != ".." && !strings.ContainsAny(name, `/\`)
+}
+
+// yearPath returns the cache file of a year.
+func (c *Client) yearPath(dir string, year int) string {
+	return filepath.Join(dir, fmt.Sprintf("%d.json", year))
+}
+
+// readJSON decodes a JSON file.
+func readJSON(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJSON encodes v to a JSON file, replacing it at once so a reader never
+// sees a partial file.
+func writeJSON(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,283 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/github"
+	"github.com/github/gh-skyline/internal/testutil/fixtures"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// countingSource answers from fixtures and records the years it was asked for.
+type countingSource struct {
+	fetched   []int
+	joinCalls int
+	failYears map[int]bool
+}
+
+func (s *countingSource) GetAuthenticatedUser(context.Context) (string, error) {
+	return "testuser", nil
+}
+
+func (s *countingSource) GetUserJoinYear(context.Context, string) (int, error) {
+	s.joinCalls++
+	return 2015, nil
+}
+
+func (s *countingSource) FetchContributions(ctx context.Context, username string, year int) (*types.ContributionsResponse, error) {
+	results, err := s.FetchContributionsBatch(ctx, username, []int{year})
+	if err != nil {
+		return nil, err
+	}
+	return results[0].Response, results[0].Err
+}
+
+func (s *countingSource) FetchContributionsBatch(_ context.Context, username string, years []int) ([]github.YearResult, error) {
+	results := make([]github.YearResult, len(years))
+	for i, year := range years {
+		s.fetched = append(s.fetched, year)
+		results[i].Year = year
+		if s.failYears[year] {
+			results[i].Err = fmt.Errorf("no data for %d", year)
+			continue
+		}
+		results[i].Response = fixtures.GenerateContributionsResponse(username, year)
+	}
+	return results, nil
+}
+
+func (s *countingSource) FetchContributionBreakdown(_ context.Context, _ string, year int) (map[string]types.ContributionBreakdown, error) {
+	s.fetched = append(s.fetched, year)
+	if s.failYears[year] {
+		return nil, fmt.Errorf("no data for %d", year)
+	}
+	return map[string]types.ContributionBreakdown{fmt.Sprintf("%d-01-01", year): {Commits: 2, Reviews: 1}}, nil
+}
+
+func TestFetchContributionsBatch(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		fetchedAt   time.Time // When the cache was filled
+		ttl         time.Duration
+		failYears   map[int]bool
+		wantFetched []int // Years fetched again
+	}{
+		{name: "fresh", fetchedAt: now.Add(-time.Minute), ttl: time.Hour, wantFetched: nil},
+		{name: "current year expired", fetchedAt: now.Add(-2 * time.Hour), ttl: time.Hour, wantFetched: []int{2024}},
+		{name: "past year fetched before it ended", fetchedAt: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC), ttl: time.Hour, wantFetched: []int{2023, 2024}},
+		{name: "past year fetched before it ended in UTC", fetchedAt: time.Date(2024, time.January, 1, 8, 0, 0, 0, time.FixedZone("KST", 9*60*60)), ttl: time.Hour, wantFetched: []int{2023, 2024}},
+		{name: "zero TTL", fetchedAt: now, ttl: 0, wantFetched: []int{2024}},
+		{name: "stale copy on failure", fetchedAt: now.Add(-2 * time.Hour), ttl: time.Hour, failYears: map[int]bool{2024: true}, wantFetched: []int{2024}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			years := []int{2022, 2023, 2024}
+
+			// Fill the cache
+			fill, err := New(&countingSource{}, Options{Enabled: true, Dir: dir, TTL: tt.ttl})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			fill.now = func() time.Time { return tt.fetchedAt }
+			if _, err := fill.FetchContributionsBatch(context.Background(), "TestUser", years[:2]); err != nil {
+				t.Fatalf("FetchContributionsBatch() error = %v", err)
+			}
+			fill.now = func() time.Time { return tt.fetchedAt.Add(-time.Second) }
+			if _, err := fill.FetchContributionsBatch(context.Background(), "TestUser", years[2:]); err != nil {
+				t.Fatalf("FetchContributionsBatch() error = %v", err)
+			}
+
+			source := &countingSource{failYears: tt.failYears}
+			client, err := New(source, Options{Enabled: true, Dir: dir, TTL: tt.ttl})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			client.now = func() time.Time { return now }
+			results, err := client.FetchContributionsBatch(context.Background(), "testuser", years)
+			if err != nil {
+				t.Fatalf("FetchContributionsBatch() error = %v", err)
+			}
+			if fmt.Sprint(source.fetched) != fmt.Sprint(tt.wantFetched) && len(source.fetched)+len(tt.wantFetched) > 0 {
+				t.Errorf("fetched %v, want %v", source.fetched, tt.wantFetched)
+			}
+			for i, result := range results {
+				if result.Year != years[i] || result.Err != nil || result.Response == nil {
+					t.Errorf("result %d = {%d, %v}, want contributions of %d", i, result.Year, result.Err, years[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOffline(t *testing.T) {
+	dir := t.TempDir()
+	source := &countingSource{}
+	online, err := New(source, Options{Enabled: true, Dir: dir, TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := online.GetUserJoinYear(context.Background(), "testuser"); err != nil {
+		t.Fatalf("GetUserJoinYear() error = %v", err)
+	}
+	if _, err := online.FetchContributions(context.Background(), "testuser", 2020); err != nil {
+		t.Fatalf("FetchContributions() error = %v", err)
+	}
+
+	offline, err := New(source, Options{Enabled: true, Dir: dir, Offline: true})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	offline.now = func() time.Time { return time.Now().Add(24 * time.Hour) }
+
+	if year, err := offline.GetUserJoinYear(context.Background(), "testuser"); err != nil || year != 2015 {
+		t.Errorf("GetUserJoinYear() = %d, %v, want 2015", year, err)
+	}
+	results, err := offline.FetchContributionsBatch(context.Background(), "testuser", []int{2020, 2021})
+	if err != nil {
+		t.Fatalf("FetchContributionsBatch() error = %v", err)
+	}
+	if results[0].Err != nil || results[0].Response == nil {
+		t.Errorf("cached year failed offline: %v", results[0].Err)
+	}
+	if results[1].Err == nil {
+		t.Error("uncached year succeeded offline")
+	}
+	if _, err := offline.GetAuthenticatedUser(context.Background()); err == nil {
+		t.Error("GetAuthenticatedUser() succeeded offline")
+	}
+	if _, err := offline.GetUserJoinYear(context.Background(), "other"); err == nil {
+		t.Error("GetUserJoinYear() of an uncached user succeeded offline")
+	}
+	if len(source.fetched) != 1 || source.joinCalls != 1 {
+		t.Errorf("offline client called the API: fetched %v, %d join year calls", source.fetched, source.joinCalls)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr bool
+	}{
+		{name: "disabled", opts: Options{}},
+		{name: "enabled", opts: Options{Enabled: true, TTL: time.Hour}},
+		{name: "offline", opts: Options{Enabled: true, Offline: true}},
+		{name: "negative TTL", opts: Options{Enabled: true, TTL: -time.Second}, wantErr: true},
+		{name: "offline without cache", opts: Options{Offline: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInvalidUsername(t *testing.T) {
+	client, err := New(&countingSource{}, Options{Enabled: true, Dir: t.TempDir()})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for _, username := range []string{"", "..", "a/b", `a\b`} {
+		if _, err := client.FetchContributions(context.Background(), username, 2020); err == nil {
+			t.Errorf("FetchContributions(%q) succeeded", username)
+		}
+	}
+}
+
+func TestFetchContributionBreakdown(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	source := &countingSource{}
+	client, err := New(source, Options{Enabled: true, Dir: dir, TTL: time.Hour})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	client.now = func() time.Time { return now }
+
+	for _, year := range []int{2023, 2024, 2023, 2024} {
+		days, err := client.FetchContributionBreakdown(context.Background(), "testuser", year)
+		if err != nil {
+			t.Fatalf("FetchContributionBreakdown(%d) error = %v", year, err)
+		}
+		if days[fmt.Sprintf("%d-01-01", year)].Commits != 2 {
+			t.Errorf("FetchContributionBreakdown(%d) = %v", year, days)
+		}
+	}
+	if fmt.Sprint(source.fetched) != "[2023 2024]" {
+		t.Errorf("fetched %v, want each year once", source.fetched)
+	}
+
+	// The current year expires, and a failed refresh falls back to the cached copy
+	client.now = func() time.Time { return now.Add(2 * time.Hour) }
+	source.failYears = map[int]bool{2024: true}
+	if _, err := client.FetchContributionBreakdown(context.Background(), "testuser", 2024); err != nil {
+		t.Errorf("FetchContributionBreakdown() error = %v, want the stale copy", err)
+	}
+	if fmt.Sprint(source.fetched) != "[2023 2024 2024]" {
+		t.Errorf("fetched %v, want 2024 again", source.fetched)
+	}
+
+	offline, err := New(nil, Options{Enabled: true, Dir: dir, Offline: true})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := offline.FetchContributionBreakdown(context.Background(), "testuser", 2023); err != nil {
+		t.Errorf("cached breakdown failed offline: %v", err)
+	}
+	if _, err := offline.FetchContributionBreakdown(context.Background(), "testuser", 2022); err == nil {
+		t.Error("uncached breakdown succeeded offline")
+	}
+}
+
+func TestOrganizationScope(t *testing.T) {
+	dir := t.TempDir()
+	source := &countingSource{}
+	for _, org := range []string{"", "acme", "ACME"} {
+		client, err := New(source, Options{Enabled: true, Dir: dir, TTL: time.Hour, Organization: org})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if _, err := client.FetchContributions(context.Background(), "testuser", 2020); err != nil {
+			t.Fatalf("FetchContributions() error = %v", err)
+		}
+	}
+	// The user's own contributions and the organization's are cached apart
+	if len(source.fetched) != 2 {
+		t.Errorf("fetched %v, want once for the user and once for the organization", source.fetched)
+	}
+
+	if _, err := New(source, Options{Enabled: true, Dir: dir, Organization: "../acme"}); err == nil {
+		t.Error("New() accepted an organization that is not a single path element")
+	}
+}
+
+func TestHostScope(t *testing.T) {
+	dir := t.TempDir()
+	source := &countingSource{}
+	for _, host := range []string{"", "github.com", "ghe.example.com", "GHE.example.com"} {
+		client, err := New(source, Options{Enabled: true, Dir: dir, TTL: time.Hour, Host: host})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		if _, err := client.FetchContributions(context.Background(), "testuser", 2020); err != nil {
+			t.Fatalf("FetchContributions() error = %v", err)
+		}
+	}
+	// The same login on github.com and on the enterprise host are cached apart
+	if len(source.fetched) != 2 {
+		t.Errorf("fetched %v, want once for github.com and once for the enterprise host", source.fetched)
+	}
+
+	if _, err := New(source, Options{Enabled: true, Dir: dir, Host: "ghe.example.com/evil"}); err == nil {
+		t.Error("New() accepted a host that is not a single path element")
+	}
+}
@@ -0,0 +1,195 @@
+// Package datafile reads and writes contribution data files, so models can be
+// generated without access to the GitHub API and regenerated later.
+package datafile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const dateLayout = "2006-01-02"
+
+// Contributions is the contribution data read from a file.
+type Contributions struct {
+	Login string                  // Login named in the file; empty for CSV
+	Days  []types.ContributionDay // Sorted by date, at most one per day
+}
+
+// Load reads a contribution file. A .csv file holds date,count rows, with an
+// optional header; any other file is JSON holding a ContributionsResponse, the
+// same wrapped in the GraphQL "data" field, an array of them for several years,
+// or an Export.
+func Load(path string) (Contributions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Contributions{}, errors.New(errors.IOError, "failed to read contribution file", err)
+	}
+
+	var contributions Contributions
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		contributions.Days, err = parseCSV(bytes.NewReader(data))
+	} else {
+		contributions, err = parseJSON(data)
+	}
+	if err != nil {
+		return Contributions{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid contribution file %s", path), err)
+	}
+	if len(contributions.Days) == 0 {
+		return Contributions{}, errors.New(errors.ValidationError, fmt.Sprintf("contribution file %s has no days", path), nil)
+	}
+
+	sort.Slice(contributions.Days, func(i, j int) bool { return contributions.Days[i].Date < contributions.Days[j].Date })
+	for i := 1; i < len(contributions.Days); i++ {
+		if contributions.Days[i].Date == contributions.Days[i-1].Date {
+			return Contributions{}, errors.New(errors.ValidationError, fmt.Sprintf("contribution file %s lists %s twice", path, contributions.Days[i].Date), nil)
+		}
+	}
+	return contributions, nil
+}
+
+// parseJSON decodes one or more contribution responses, or an Export.
+func parseJSON(data []byte) (Contributions, error) {
+	var responses []types.ContributionsResponse
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(data, &responses); err != nil {
+			return Contributions{}, err
+		}
+	} else {
+		var wrapped struct {
+			Data *types.ContributionsResponse `json:"data"`
+			Export
+		}
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return Contributions{}, err
+		}
+		if wrapped.Years != nil {
+			return fromExport(wrapped.Export)
+		}
+		if wrapped.Data == nil {
+			var response types.ContributionsResponse
+			if err := json.Unmarshal(data, &response); err != nil {
+				return Contributions{}, err
+			}
+			wrapped.Data = &response
+		}
+		responses = []types.ContributionsResponse{*wrapped.Data}
+	}
+
+	var contributions Contributions
+	for _, response := range responses {
+		if login := response.User.Login; login != "" {
+			if contributions.Login != "" && !strings.EqualFold(contributions.Login, login) {
+				return Contributions{}, fmt.Errorf("file mixes users %s and %s", contributions.Login, login)
+			}
+			contributions.Login = login
+		}
+		for _, week := range response.User.ContributionsCollection.ContributionCalendar.Weeks {
+			for _, day := range week.ContributionDays {
+				if err := day.Validate(); err != nil {
+					return Contributions{}, fmt.Errorf("day %q: %w", day.Date, err)
+				}
+				contributions.Days = append(contributions.Days, day)
+			}
+		}
+	}
+	return contributions, nil
+}
+
+// fromExport reads the days of a file written by Export.Write.
+func fromExport(export Export) (Contributions, error) {
+	contributions := Contributions{Login: export.Login}
+	for _, year := range export.Years {
+		for _, day := range year.Days {
+			if err := day.Validate(); err != nil {
+				return Contributions{}, fmt.Errorf("day %q: %w", day.Date, err)
+			}
+			contributions.Days = append(contributions.Days, day)
+		}
+	}
+	return contributions, nil
+}
+
+// parseCSV reads date,count rows, skipping a header row and blank lines.
+func parseCSV(r io.Reader) ([]types.ContributionDay, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	var days []types.ContributionDay
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return days, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if row == 1 && strings.EqualFold(strings.TrimSpace(record[0]), "date") {
+			continue
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(record[1]))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid count %q", row, record[1])
+		}
+		day := types.ContributionDay{Date: strings.TrimSpace(record[0]), ContributionCount: count}
+		if err := day.Validate(); err != nil {
+			return nil, fmt.Errorf("row %d: %w", row, err)
+		}
+		days = append(days, day)
+	}
+}
+
+// Years returns the first and last year with days in the file.
+func (c Contributions) Years() (startYear, endYear int) {
+	first, _ := time.Parse(dateLayout, c.Days[0].Date)
+	last, _ := time.Parse(dateLayout, c.Days[len(c.Days)-1].Date)
+	return first.Year(), last.Year()
+}
+
+// HasYear reports whether the file has any days in the year.
+func (c Contributions) HasYear(year int) bool {
+	prefix := strconv.Itoa(year) + "-"
+	for _, day := range c.Days {
+		if strings.HasPrefix(day.Date, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Grid returns the year as weeks starting on Sunday, like the GitHub
+// contribution calendar. Days of the year missing from the file have no contributions.
+func (c Contributions) Grid(year int) [][]types.ContributionDay {
+	byDate := make(map[string]types.ContributionDay, len(c.Days))
+	for _, day := range c.Days {
+		byDate[day.Date] = day
+	}
+
+	var weeks [][]types.ContributionDay
+	var week []types.ContributionDay
+	for date := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC); date.Year() == year; date = date.AddDate(0, 0, 1) {
+		if date.Weekday() == time.Sunday && len(week) > 0 {
+			weeks = append(weeks, week)
+			week = nil
+		}
+		key := date.Format(dateLayout)
+		day, ok := byDate[key]
+		if !ok {
+			day = types.ContributionDay{Date: key}
+		}
+		week = append(week, day)
+	}
+	return append(weeks, week)
+}
@@ -0,0 +1,115 @@
+package datafile
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/testutil/fixtures"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestLoad(t *testing.T) {
+	response := fixtures.GenerateContributionsResponse("testuser", 2023)
+	single, err := json.Marshal(response)
+	if err != nil {
+		t.Fatal(err)
+	}
+	several, err := json.Marshal([]*types.ContributionsResponse{response, fixtures.GenerateContributionsResponse("testuser", 2024)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := json.Marshal(map[string]interface{}{"data": response})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mixed, err := json.Marshal([]*types.ContributionsResponse{response, fixtures.GenerateContributionsResponse("other", 2024)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		file      string
+		content   string
+		wantLogin string
+		wantDays  int
+		wantYears [2]int
+		wantErr   bool
+	}{
+		{name: "json response", file: "data.json", content: string(single), wantLogin: "testuser", wantDays: 364, wantYears: [2]int{2023, 2023}},
+		{name: "json array", file: "data.json", content: string(several), wantLogin: "testuser", wantDays: 728, wantYears: [2]int{2023, 2024}},
+		{name: "graphql data", file: "data.json", content: string(wrapped), wantLogin: "testuser", wantDays: 364, wantYears: [2]int{2023, 2023}},
+		{name: "csv with header", file: "data.csv", content: "date,count\n2024-01-02,3\n2023-12-31, 1\n", wantDays: 2, wantYears: [2]int{2023, 2024}},
+		{name: "csv without header", file: "DATA.CSV", content: "2024-01-02,3\n", wantDays: 1, wantYears: [2]int{2024, 2024}},
+		{name: "mixed users", file: "data.json", content: string(mixed), wantErr: true},
+		{name: "duplicate day", file: "data.csv", content: "2024-01-02,3\n2024-01-02,1\n", wantErr: true},
+		{name: "bad count", file: "data.csv", content: "2024-01-02,many\n", wantErr: true},
+		{name: "negative count", file: "data.csv", content: "2024-01-02,-1\n", wantErr: true},
+		{name: "bad date", file: "data.csv", content: "02/01/2024,1\n", wantErr: true},
+		{name: "extra column", file: "data.csv", content: "2024-01-02,1,x\n", wantErr: true},
+		{name: "empty", file: "data.csv", content: "date,count\n", wantErr: true},
+		{name: "invalid json", file: "data.json", content: "{", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.Login != tt.wantLogin || len(got.Days) != tt.wantDays {
+				t.Errorf("Load() = %q with %d days, want %q with %d", got.Login, len(got.Days), tt.wantLogin, tt.wantDays)
+			}
+			if startYear, endYear := got.Years(); [2]int{startYear, endYear} != tt.wantYears {
+				t.Errorf("Years() = %d-%d, want %d-%d", startYear, endYear, tt.wantYears[0], tt.wantYears[1])
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Load() of a missing file succeeded")
+	}
+}
+
+func TestGrid(t *testing.T) {
+	data := Contributions{Days: []types.ContributionDay{
+		{Date: "2024-01-01", ContributionCount: 2},
+		{Date: "2024-12-31", ContributionCount: 5},
+	}}
+	grid := data.Grid(2024)
+
+	// 2024 starts on a Monday and ends on a Tuesday
+	if len(grid) != 53 {
+		t.Fatalf("Grid() has %d weeks, want 53", len(grid))
+	}
+	if len(grid[0]) != 6 || len(grid[52]) != 3 {
+		t.Errorf("partial weeks have %d and %d days, want 6 and 3", len(grid[0]), len(grid[52]))
+	}
+	days, total := 0, 0
+	for _, week := range grid {
+		days += len(week)
+		for _, day := range week {
+			total += day.ContributionCount
+		}
+	}
+	if days != 366 || total != 7 {
+		t.Errorf("Grid() has %d days with %d contributions, want 366 with 7", days, total)
+	}
+	if first := grid[0][0]; first.Date != "2024-01-01" || first.ContributionCount != 2 {
+		t.Errorf("first day = %+v", first)
+	}
+	if !data.HasYear(2024) || data.HasYear(2023) {
+		t.Error("HasYear() is wrong")
+	}
+}
@@ -0,0 +1,79 @@
+package datafile
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Export is the contribution data behind a model. Its JSON form can be read
+// back with Load to generate the model again.
+type Export struct {
+	Login      string       `json:"login"`
+	StartMonth int          `json:"startMonth"`
+	EndMonth   int          `json:"endMonth"`
+	Total      int          `json:"totalContributions"`
+	Years      []YearExport `json:"years"`
+}
+
+// YearExport is the contribution data of one year of an Export.
+type YearExport struct {
+	Year  int                     `json:"year"`
+	Total int                     `json:"totalContributions"`
+	Days  []types.ContributionDay `json:"days"`
+}
+
+// NewExport collects the weeks of each year from startYear on, as used for the model.
+func NewExport(login string, startYear, startMonth, endMonth int, years [][][]types.ContributionDay) Export {
+	export := Export{Login: login, StartMonth: startMonth, EndMonth: endMonth}
+	for i, weeks := range years {
+		year := YearExport{Year: startYear + i, Days: []types.ContributionDay{}}
+		for _, week := range weeks {
+			for _, day := range week {
+				year.Days = append(year.Days, day)
+				year.Total += day.ContributionCount
+			}
+		}
+		export.Total += year.Total
+		export.Years = append(export.Years, year)
+	}
+	return export
+}
+
+// Write writes the export as JSON and as date,count CSV next to the model at
+// modelPath, replacing its extension, and returns the paths written.
+func (e Export) Write(modelPath string) (jsonPath, csvPath string, err error) {
+	base := strings.TrimSuffix(modelPath, filepath.Ext(modelPath))
+	jsonPath, csvPath = base+".json", base+".csv"
+
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return "", "", errors.New(errors.IOError, "failed to encode contribution data", err)
+	}
+	if err := os.WriteFile(jsonPath, append(data, '\n'), 0o644); err != nil {
+		return "", "", errors.New(errors.IOError, "failed to write contribution data", err)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	records := [][]string{{"date", "count"}}
+	for _, year := range e.Years {
+		for _, day := range year.Days {
+			records = append(records, []string{day.Date, strconv.Itoa(day.ContributionCount)})
+		}
+	}
+	if err := w.WriteAll(records); err != nil {
+		return "", "", errors.New(errors.IOError, "failed to encode contribution data", err)
+	}
+	if err := os.WriteFile(csvPath, buf.Bytes(), 0o644); err != nil {
+		return "", "", errors.New(errors.IOError, "failed to write contribution data", err)
+	}
+	return jsonPath, csvPath, nil
+}
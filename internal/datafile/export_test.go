@@ -0,0 +1,52 @@
+package datafile
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestExportWrite(t *testing.T) {
+	years := [][][]types.ContributionDay{
+		{{{Date: "2023-12-30", ContributionCount: 1}, {Date: "2023-12-31", ContributionCount: 2}}},
+		{{{Date: "2024-01-01", ContributionCount: 4}}},
+	}
+	export := NewExport("testuser", 2023, 1, 12, years)
+	if export.Total != 7 || export.Years[0].Total != 3 || export.Years[1].Year != 2024 {
+		t.Errorf("NewExport() totals = %d, %d, year %d; want 7, 3, 2024", export.Total, export.Years[0].Total, export.Years[1].Year)
+	}
+
+	modelPath := filepath.Join(t.TempDir(), "testuser-2023-24-github-skyline.stl")
+	jsonPath, csvPath, err := export.Write(modelPath)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if want := strings.TrimSuffix(modelPath, ".stl") + ".json"; jsonPath != want {
+		t.Errorf("JSON path = %s, want %s", jsonPath, want)
+	}
+
+	csvData, err := os.ReadFile(csvPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "date,count\n2023-12-30,1\n2023-12-31,2\n2024-01-01,4\n"; string(csvData) != want {
+		t.Errorf("CSV = %q, want %q", csvData, want)
+	}
+
+	// Both files read back to the same days
+	for _, path := range []string{jsonPath, csvPath} {
+		got, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load(%s) error = %v", path, err)
+		}
+		if len(got.Days) != 3 || got.Days[2].ContributionCount != 4 {
+			t.Errorf("Load(%s) = %+v", path, got.Days)
+		}
+	}
+	if got, _ := Load(jsonPath); got.Login != "testuser" {
+		t.Errorf("Load() login = %q, want testuser", got.Login)
+	}
+}
@@ -0,0 +1,118 @@
+// Package gcode post-processes G-code produced by slicers.
+package gcode
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// changeComment marks the filament changes inserted by InsertFilamentChanges.
+const changeComment = "; gh-skyline filament change"
+
+// zTolerance absorbs rounding in the Z values written by slicers (mm).
+const zTolerance = 1e-3
+
+// maxLineLength is the longest G-code line read, including thumbnails embedded as comments.
+const maxLineLength = 1024 * 1024
+
+// InsertFilamentChanges copies G-code from r to w and inserts an M600 filament
+// change at the start of the first layer above each height, in mm above the bed.
+// Layers are recognised by the comments slicers write at each layer change:
+// ";LAYER_CHANGE" (PrusaSlicer, OrcaSlicer, Bambu Studio) or ";LAYER:n" (Cura).
+// A layer's height is read from the ";Z:" comment or Z move that follows.
+// It returns the number of changes inserted, which is lower than the number of
+// heights when the print ends below some of them.
+func InsertFilamentChanges(r io.Reader, w io.Writer, heights []float64) (int, error) {
+	pendingHeights := append([]float64(nil), heights...)
+	sort.Float64s(pendingHeights)
+
+	out := bufio.NewWriter(w)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength)
+
+	var layer []string // Lines of the current layer change, held until its Z is known
+	inserted, layers := 0, 0
+	flush := func(lines []string) {
+		for _, line := range lines {
+			fmt.Fprintln(out, line)
+		}
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, changeComment) {
+			return 0, errors.New(errors.ValidationError, "G-code already contains filament changes inserted by gh-skyline", nil)
+		}
+
+		if isLayerChange(line) {
+			flush(layer)
+			layer = []string{line}
+			layers++
+			continue
+		}
+		if layer == nil {
+			fmt.Fprintln(out, line)
+			continue
+		}
+
+		layer = append(layer, line)
+		z, ok := parseZ(line)
+		if !ok {
+			continue
+		}
+		flush(layer[:1])
+		for len(pendingHeights) > 0 && z > pendingHeights[0]+zTolerance {
+			fmt.Fprintf(out, "%s above %.2f mm\nM600\n", changeComment, pendingHeights[0])
+			pendingHeights = pendingHeights[1:]
+			inserted++
+		}
+		flush(layer[1:])
+		layer = nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, errors.New(errors.IOError, "failed to read G-code", err)
+	}
+	flush(layer)
+
+	if layers == 0 {
+		return 0, errors.New(errors.ValidationError, "no layer change comments found in G-code (expected ;LAYER_CHANGE or ;LAYER:)", nil)
+	}
+	if err := out.Flush(); err != nil {
+		return 0, errors.New(errors.IOError, "failed to write G-code", err)
+	}
+	return inserted, nil
+}
+
+// isLayerChange reports whether the line is a slicer's layer change comment.
+func isLayerChange(line string) bool {
+	line = strings.TrimSpace(line)
+	return line == ";LAYER_CHANGE" || strings.HasPrefix(line, ";LAYER:")
+}
+
+// parseZ returns the height set by a ";Z:" comment or a G0/G1 move with a Z word.
+func parseZ(line string) (float64, bool) {
+	line = strings.TrimSpace(line)
+	if value, ok := strings.CutPrefix(line, ";Z:"); ok {
+		z, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		return z, err == nil
+	}
+
+	code, _, _ := strings.Cut(line, ";")
+	fields := strings.Fields(strings.ToUpper(code))
+	if len(fields) == 0 || (fields[0] != "G0" && fields[0] != "G1" && fields[0] != "G00" && fields[0] != "G01") {
+		return 0, false
+	}
+	for _, field := range fields[1:] {
+		if value, ok := strings.CutPrefix(field, "Z"); ok {
+			z, err := strconv.ParseFloat(value, 64)
+			return z, err == nil
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,142 @@
+package gcode
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// prusaLayers returns G-code with PrusaSlicer style layer changes at the given heights.
+func prusaLayers(heights ...string) string {
+	var b strings.Builder
+	b.WriteString("; generated by PrusaSlicer\nG28\n")
+	for _, z := range heights {
+		b.WriteString(";LAYER_CHANGE\n;Z:" + z + "\n;HEIGHT:0.2\nG1 Z" + z + " F720\nG1 X10 Y10 E1\n")
+	}
+	return b.String()
+}
+
+// curaLayers returns G-code with Cura style layer changes at the given heights.
+func curaLayers(heights ...string) string {
+	var b strings.Builder
+	b.WriteString(";FLAVOR:Marlin\nG28\n")
+	for i, z := range heights {
+		b.WriteString(";LAYER:" + string(rune('0'+i)) + "\n;TYPE:WALL-OUTER\nG0 F6000 X10 Y10 Z" + z + " ; travel\nG1 X20 E1\n")
+	}
+	return b.String()
+}
+
+func TestInsertFilamentChanges(t *testing.T) {
+	tests := []struct {
+		name         string
+		gcode        string
+		heights      []float64
+		wantInserted int
+		wantAfter    []string // Lines directly after each M600, in order
+		wantErr      bool
+	}{
+		{
+			name:         "PrusaSlicer layers",
+			gcode:        prusaLayers("9.8", "10", "10.2", "10.4", "12.6"),
+			heights:      []float64{12.5, 10},
+			wantInserted: 2,
+			wantAfter:    []string{";Z:10.2", ";Z:12.6"},
+		},
+		{
+			name:         "Cura layers",
+			gcode:        curaLayers("0.2", "0.4", "0.6"),
+			heights:      []float64{0.3},
+			wantInserted: 1,
+			wantAfter:    []string{";TYPE:WALL-OUTER"},
+		},
+		{
+			name:         "heights above the print",
+			gcode:        prusaLayers("0.2", "0.4"),
+			heights:      []float64{0.2, 5},
+			wantInserted: 1,
+			wantAfter:    []string{";Z:0.4"},
+		},
+		{
+			name:         "several heights in one layer",
+			gcode:        prusaLayers("0.2", "1.0"),
+			heights:      []float64{0.4, 0.6},
+			wantInserted: 2,
+			wantAfter:    []string{changeComment + " above 0.60 mm", ";Z:1.0"},
+		},
+		{
+			name:    "no layer comments",
+			gcode:   "G28\nG1 Z0.2\nG1 X1 E1\n",
+			heights: []float64{0.1},
+			wantErr: true,
+		},
+		{
+			name:    "already processed",
+			gcode:   prusaLayers("0.2") + changeComment + " above 0.10 mm\nM600\n",
+			heights: []float64{0.1},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			inserted, err := InsertFilamentChanges(strings.NewReader(tt.gcode), &out, tt.heights)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("InsertFilamentChanges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if inserted != tt.wantInserted {
+				t.Errorf("inserted %d changes, want %d", inserted, tt.wantInserted)
+			}
+
+			lines := strings.Split(out.String(), "\n")
+			var after []string
+			for i, line := range lines {
+				if line == "M600" {
+					if !strings.HasPrefix(lines[i-1], changeComment) {
+						t.Errorf("M600 at line %d is not preceded by the change comment", i)
+					}
+					after = append(after, lines[i+1])
+				}
+			}
+			if strings.Join(after, "|") != strings.Join(tt.wantAfter, "|") {
+				t.Errorf("M600 is followed by %q, want %q", after, tt.wantAfter)
+			}
+
+			// Apart from the inserted changes, the G-code is unchanged
+			var kept []string
+			for _, line := range lines {
+				if line != "M600" && !strings.HasPrefix(line, changeComment) {
+					kept = append(kept, line)
+				}
+			}
+			if strings.Join(kept, "\n") != tt.gcode {
+				t.Errorf("G-code was modified beyond the inserted changes:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestParseZ(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   float64
+		wantOK bool
+	}{
+		{";Z:10.2", 10.2, true},
+		{"G1 Z0.6 F720", 0.6, true},
+		{"g0 x1 y2 z3.5 ; travel", 3.5, true},
+		{"G1 X1 Y2 E0.5", 0, false},
+		{"G92 Z0", 0, false},
+		{"; G1 Z5", 0, false},
+		{"G1 Zabc", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseZ(tt.line)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseZ(%q) = %v, %v, want %v, %v", tt.line, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
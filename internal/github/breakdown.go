@@ -0,0 +1,162 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// breakdownQuery selects the typed contributions of a contributionsCollection,
+// formatted with the organization parameter and argument. Commits come per
+// repository and day on the first page only; the other types are paged through,
+// each connection left out once it has no more pages.
+const breakdownQuery = `
+    query ContributionBreakdown($username: String!, $from: DateTime!, $to: DateTime!%s,
+        $commits: Boolean!, $pullRequests: Boolean!, $pullRequestCursor: String,
+        $reviews: Boolean!, $reviewCursor: String, $issues: Boolean!, $issueCursor: String) {
+        user(login: $username) {
+            contributionsCollection(from: $from, to: $to%s) {
+                commitContributionsByRepository(maxRepositories: 100) @include(if: $commits) {
+                    contributions(first: 100) {
+                        pageInfo { hasNextPage }
+                        nodes { occurredAt commitCount }
+                    }
+                }
+                pullRequestContributions(first: 100, after: $pullRequestCursor) @include(if: $pullRequests) {
+                    pageInfo { hasNextPage endCursor }
+                    nodes { occurredAt }
+                }
+                pullRequestReviewContributions(first: 100, after: $reviewCursor) @include(if: $reviews) {
+                    pageInfo { hasNextPage endCursor }
+                    nodes { occurredAt }
+                }
+                issueContributions(first: 100, after: $issueCursor) @include(if: $issues) {
+                    pageInfo { hasNextPage endCursor }
+                    nodes { occurredAt }
+                }
+            }
+        }
+    }`
+
+// pageInfo is the position of a page in a GraphQL connection.
+type pageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// contributionPage is a page of contributions of one type.
+type contributionPage struct {
+	PageInfo pageInfo `json:"pageInfo"`
+	Nodes    []struct {
+		OccurredAt string `json:"occurredAt"`
+	} `json:"nodes"`
+}
+
+// breakdownResponse is the response to breakdownQuery.
+type breakdownResponse struct {
+	User *struct {
+		ContributionsCollection struct {
+			CommitContributionsByRepository []struct {
+				Contributions struct {
+					PageInfo pageInfo `json:"pageInfo"`
+					Nodes    []struct {
+						OccurredAt  string `json:"occurredAt"`
+						CommitCount int    `json:"commitCount"`
+					} `json:"nodes"`
+				} `json:"contributions"`
+			} `json:"commitContributionsByRepository"`
+			PullRequestContributions       *contributionPage `json:"pullRequestContributions"`
+			PullRequestReviewContributions *contributionPage `json:"pullRequestReviewContributions"`
+			IssueContributions             *contributionPage `json:"issueContributions"`
+		} `json:"contributionsCollection"`
+	} `json:"user"`
+}
+
+// FetchContributionBreakdown retrieves the commit, pull request, review and issue
+// contributions of a year, counted per date (YYYY-MM-DD, UTC). Only the first
+// 100 days with commits of each repository are counted; see types.ContributionBreakdown.
+func (c *Client) FetchContributionBreakdown(ctx context.Context, username string, year int) (map[string]types.ContributionBreakdown, error) {
+	if username == "" {
+		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
+	}
+	if year < 2008 {
+		return nil, errors.New(errors.ValidationError, "year cannot be before GitHub's launch (2008)", nil)
+	}
+
+	variables := map[string]interface{}{
+		"username":          username,
+		"from":              fmt.Sprintf("%d-01-01T00:00:00Z", year),
+		"to":                fmt.Sprintf("%d-12-31T23:59:59Z", year),
+		"commits":           true,
+		"pullRequests":      true,
+		"pullRequestCursor": nil,
+		"reviews":           true,
+		"reviewCursor":      nil,
+		"issues":            true,
+		"issueCursor":       nil,
+	}
+	param, arg := c.organizationScope(variables)
+	query := fmt.Sprintf(breakdownQuery, param, arg)
+	days := make(map[string]types.ContributionBreakdown)
+	count := func(occurredAt string, add func(*types.ContributionBreakdown)) {
+		if len(occurredAt) < len("2006-01-02") {
+			return
+		}
+		day := days[occurredAt[:10]]
+		add(&day)
+		days[occurredAt[:10]] = day
+	}
+	// countPage counts a page of one type and sets up the next page, if any
+	countPage := func(page *contributionPage, include, cursor string, add func(*types.ContributionBreakdown)) {
+		if page == nil {
+			variables[include] = false
+			return
+		}
+		for _, node := range page.Nodes {
+			count(node.OccurredAt, add)
+		}
+		variables[include] = page.PageInfo.HasNextPage
+		variables[cursor] = page.PageInfo.EndCursor
+	}
+
+	truncated := 0
+	for {
+		var response breakdownResponse
+		if err := c.api.DoWithContext(ctx, query, variables, &response); err != nil {
+			return nil, errors.New(errors.NetworkError, "failed to fetch contribution breakdown", err)
+		}
+		if response.User == nil {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("user %s not found", username), nil)
+		}
+		collection := response.User.ContributionsCollection
+
+		if variables["commits"] == true {
+			for _, repository := range collection.CommitContributionsByRepository {
+				for _, node := range repository.Contributions.Nodes {
+					count(node.OccurredAt, func(d *types.ContributionBreakdown) { d.Commits += node.CommitCount })
+				}
+				if repository.Contributions.PageInfo.HasNextPage {
+					truncated++
+				}
+			}
+			variables["commits"] = false
+		}
+		countPage(collection.PullRequestContributions, "pullRequests", "pullRequestCursor", func(d *types.ContributionBreakdown) { d.PullRequests++ })
+		countPage(collection.PullRequestReviewContributions, "reviews", "reviewCursor", func(d *types.ContributionBreakdown) { d.Reviews++ })
+		countPage(collection.IssueContributions, "issues", "issueCursor", func(d *types.ContributionBreakdown) { d.Issues++ })
+
+		if variables["pullRequests"] == false && variables["reviews"] == false && variables["issues"] == false {
+			break
+		}
+	}
+
+	if truncated > 0 {
+		if err := logger.GetLogger().Warning("Commits of %d repositories in %d span more than 100 days; the rest count as other contributions", truncated, year); err != nil {
+			return nil, err
+		}
+	}
+	return days, nil
+}
@@ -0,0 +1,91 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+// breakdownAPI answers breakdown queries with pages of two reviews each and a
+// single page of commits and pull requests.
+type breakdownAPI struct {
+	reviewPages int
+	queries     []map[string]interface{}
+}
+
+func (a *breakdownAPI) DoWithContext(_ context.Context, _ string, variables map[string]interface{}, response interface{}) error {
+	a.queries = append(a.queries, variables)
+	collection := map[string]interface{}{}
+	if variables["commits"] == true {
+		collection["commitContributionsByRepository"] = []interface{}{
+			map[string]interface{}{"contributions": map[string]interface{}{
+				"pageInfo": map[string]interface{}{"hasNextPage": true},
+				"nodes": []interface{}{
+					map[string]interface{}{"occurredAt": "2024-03-01T10:00:00Z", "commitCount": 3},
+					map[string]interface{}{"occurredAt": "2024-03-02T10:00:00Z", "commitCount": 1},
+				},
+			}},
+		}
+	}
+	if variables["pullRequests"] == true {
+		collection["pullRequestContributions"] = map[string]interface{}{
+			"pageInfo": map[string]interface{}{"hasNextPage": false},
+			"nodes":    []interface{}{map[string]interface{}{"occurredAt": "2024-03-01T12:00:00Z"}},
+		}
+	}
+	if variables["reviews"] == true {
+		page := 1
+		if cursor, ok := variables["reviewCursor"].(string); ok {
+			fmt.Sscanf(cursor, "page%d", &page)
+			page++
+		}
+		collection["pullRequestReviewContributions"] = map[string]interface{}{
+			"pageInfo": map[string]interface{}{"hasNextPage": page < a.reviewPages, "endCursor": fmt.Sprintf("page%d", page)},
+			"nodes": []interface{}{
+				map[string]interface{}{"occurredAt": "2024-03-02T09:00:00Z"},
+				map[string]interface{}{"occurredAt": "2024-03-03T09:00:00Z"},
+			},
+		}
+	}
+	if variables["issues"] == true {
+		collection["issueContributions"] = map[string]interface{}{
+			"pageInfo": map[string]interface{}{"hasNextPage": false},
+			"nodes":    []interface{}{},
+		}
+	}
+	data, err := json.Marshal(map[string]interface{}{"user": map[string]interface{}{"contributionsCollection": collection}})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, response)
+}
+
+func TestFetchContributionBreakdown(t *testing.T) {
+	api := &breakdownAPI{reviewPages: 3}
+	days, err := NewClient(api).FetchContributionBreakdown(context.Background(), "testuser", 2024)
+	if err != nil {
+		t.Fatalf("FetchContributionBreakdown() error = %v", err)
+	}
+	if len(api.queries) != 3 {
+		t.Errorf("sent %d queries, want one per page of reviews (3)", len(api.queries))
+	}
+	if first := days["2024-03-01"]; first.Commits != 3 || first.PullRequests != 1 {
+		t.Errorf("2024-03-01 = %+v, want 3 commits and 1 pull request", first)
+	}
+	if second := days["2024-03-02"]; second.Commits != 1 || second.Reviews != 3 {
+		t.Errorf("2024-03-02 = %+v, want 1 commit and 3 reviews", second)
+	}
+	if third := days["2024-03-03"]; third.Reviews != 3 {
+		t.Errorf("2024-03-03 = %+v, want 3 reviews", third)
+	}
+
+	for _, tt := range []struct {
+		username string
+		year     int
+	}{{"", 2024}, {"testuser", 2007}} {
+		if _, err := NewClient(api).FetchContributionBreakdown(context.Background(), tt.username, tt.year); err == nil {
+			t.Errorf("FetchContributionBreakdown(%q, %d) succeeded", tt.username, tt.year)
+		}
+	}
+}
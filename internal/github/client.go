@@ -3,21 +3,41 @@
 package github
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
 	"github.com/github/gh-skyline/internal/types"
 )
 
+// MaxBatchYears is the largest number of years requested in one batched query.
+const MaxBatchYears = 10
+
+// contributionCalendarFields selects the contribution calendar of a contributionsCollection.
+const contributionCalendarFields = `
+                contributionCalendar {
+                    totalContributions
+                    weeks {
+                        contributionDays {
+                            contributionCount
+                            date
+                        }
+                    }
+                }`
+
 // APIClient interface defines the methods we need from the client
 type APIClient interface {
-	Do(query string, variables map[string]interface{}, response interface{}) error
+	DoWithContext(ctx context.Context, query string, variables map[string]interface{}, response interface{}) error
 }
 
 // Client holds the API client
 type Client struct {
-	api APIClient
+	api            APIClient
+	organizationID string // Limits contributions to an organization when set
 }
 
 // NewClient creates a new GitHub client
@@ -26,7 +46,7 @@ func NewClient(apiClient APIClient) *Client {
 }
 
 // GetAuthenticatedUser fetches the authenticated user's login name from GitHub.
-func (c *Client) GetAuthenticatedUser() (string, error) {
+func (c *Client) GetAuthenticatedUser(ctx context.Context) (string, error) {
 	// GraphQL query to fetch the authenticated user's login.
 	query := `
     query {
@@ -42,7 +62,7 @@ func (c *Client) GetAuthenticatedUser() (string, error) {
 	}
 
 	// Execute the GraphQL query.
-	err := c.api.Do(query, nil, &response)
+	err := c.api.DoWithContext(ctx, query, nil, &response)
 	if err != nil {
 		return "", errors.New(errors.NetworkError, "failed to fetch authenticated user", err)
 	}
@@ -54,8 +74,49 @@ func (c *Client) GetAuthenticatedUser() (string, error) {
 	return response.Viewer.Login, nil
 }
 
+// ScopeToOrganization resolves an organization login to its ID and limits the
+// contributions fetched from then on to those made in the organization.
+func (c *Client) ScopeToOrganization(ctx context.Context, login string) error {
+	if login == "" {
+		return errors.New(errors.ValidationError, "organization cannot be empty", nil)
+	}
+
+	query := `
+    query OrganizationID($login: String!) {
+        organization(login: $login) {
+            id
+        }
+    }`
+
+	var response struct {
+		Organization *struct {
+			ID string `json:"id"`
+		} `json:"organization"`
+	}
+
+	if err := c.api.DoWithContext(ctx, query, map[string]interface{}{"login": login}, &response); err != nil {
+		return errors.New(errors.NetworkError, fmt.Sprintf("failed to look up organization %s", login), err)
+	}
+	if response.Organization == nil || response.Organization.ID == "" {
+		return errors.New(errors.ValidationError, fmt.Sprintf("organization %s not found", login), nil)
+	}
+
+	c.organizationID = response.Organization.ID
+	return nil
+}
+
+// organizationScope adds the organization to the query variables, if one is set,
+// and returns the query parameter and contributionsCollection argument for it.
+func (c *Client) organizationScope(variables map[string]interface{}) (param, arg string) {
+	if c.organizationID == "" {
+		return "", ""
+	}
+	variables["organizationID"] = c.organizationID
+	return ", $organizationID: ID!", ", organizationID: $organizationID"
+}
+
 // FetchContributions retrieves the contribution data for a given username and year from GitHub.
-func (c *Client) FetchContributions(username string, year int) (*types.ContributionsResponse, error) {
+func (c *Client) FetchContributions(ctx context.Context, username string, year int) (*types.ContributionsResponse, error) {
 	if username == "" {
 		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
 	}
@@ -67,35 +128,27 @@ func (c *Client) FetchContributions(username string, year int) (*types.Contribut
 	startDate := fmt.Sprintf("%d-01-01T00:00:00Z", year)
 	endDate := fmt.Sprintf("%d-12-31T23:59:59Z", year)
 
+	variables := map[string]interface{}{
+		"username": username,
+		"from":     startDate,
+		"to":       endDate,
+	}
+	param, arg := c.organizationScope(variables)
+
 	// GraphQL query to fetch the user's contributions within the specified date range.
 	query := `
-    query ContributionGraph($username: String!, $from: DateTime!, $to: DateTime!) {
+    query ContributionGraph($username: String!, $from: DateTime!, $to: DateTime!` + param + `) {
         user(login: $username) {
             login
-            contributionsCollection(from: $from, to: $to) {
-                contributionCalendar {
-                    totalContributions
-                    weeks {
-                        contributionDays {
-                            contributionCount
-                            date
-                        }
-                    }
-                }
+            contributionsCollection(from: $from, to: $to` + arg + `) {` + contributionCalendarFields + `
             }
         }
     }`
 
-	variables := map[string]interface{}{
-		"username": username,
-		"from":     startDate,
-		"to":       endDate,
-	}
-
 	var response types.ContributionsResponse
 
 	// Execute the GraphQL query.
-	err := c.api.Do(query, variables, &response)
+	err := c.api.DoWithContext(ctx, query, variables, &response)
 	if err != nil {
 		return nil, errors.New(errors.NetworkError, "failed to fetch contributions", err)
 	}
@@ -107,8 +160,103 @@ func (c *Client) FetchContributions(username string, year int) (*types.Contribut
 	return &response, nil
 }
 
+// YearResult is the outcome of fetching the contributions of one year.
+type YearResult struct {
+	Year     int
+	Response *types.ContributionsResponse
+	Err      error
+}
+
+// FetchContributionsBatch retrieves the contributions of several years with one
+// GraphQL query per MaxBatchYears years, each year an aliased contributionsCollection
+// since a collection may span at most one year. When a batched query fails, its
+// years are fetched one query at a time. The results are in the order of years;
+// the error is only for invalid arguments, while each result carries its own.
+func (c *Client) FetchContributionsBatch(ctx context.Context, username string, years []int) ([]YearResult, error) {
+	if username == "" {
+		return nil, errors.New(errors.ValidationError, "username cannot be empty", nil)
+	}
+	for _, year := range years {
+		if year < 2008 {
+			return nil, errors.New(errors.ValidationError, "year cannot be before GitHub's launch (2008)", nil)
+		}
+	}
+
+	results := make([]YearResult, 0, len(years))
+	for start := 0; start < len(years); start += MaxBatchYears {
+		chunk := years[start:min(start+MaxBatchYears, len(years))]
+		batch, err := c.fetchBatch(ctx, username, chunk)
+		if err == nil {
+			results = append(results, batch...)
+			continue
+		}
+		if ctx.Err() != nil {
+			for _, year := range chunk {
+				results = append(results, YearResult{Year: year, Err: err})
+			}
+			continue
+		}
+
+		if logErr := logger.GetLogger().Debug("Batched query for %d years failed, fetching them one by one: %v", len(chunk), err); logErr != nil {
+			return nil, logErr
+		}
+		for _, year := range chunk {
+			response, err := c.FetchContributions(ctx, username, year)
+			results = append(results, YearResult{Year: year, Response: response, Err: err})
+		}
+	}
+	return results, nil
+}
+
+// fetchBatch fetches the years in one query with an aliased contributionsCollection per year.
+func (c *Client) fetchBatch(ctx context.Context, username string, years []int) ([]YearResult, error) {
+	var params, fields strings.Builder
+	variables := map[string]interface{}{"username": username}
+	param, arg := c.organizationScope(variables)
+	params.WriteString(param)
+	for _, year := range years {
+		fmt.Fprintf(&params, ", $from%[1]d: DateTime!, $to%[1]d: DateTime!", year)
+		fmt.Fprintf(&fields, "\n            y%[1]d: contributionsCollection(from: $from%[1]d, to: $to%[1]d%[2]s) {%[3]s\n            }", year, arg, contributionCalendarFields)
+		variables[fmt.Sprintf("from%d", year)] = fmt.Sprintf("%d-01-01T00:00:00Z", year)
+		variables[fmt.Sprintf("to%d", year)] = fmt.Sprintf("%d-12-31T23:59:59Z", year)
+	}
+	query := fmt.Sprintf(`
+    query ContributionGraphs($username: String!%s) {
+        user(login: $username) {
+            login%s
+        }
+    }`, params.String(), fields.String())
+
+	// Aliased fields are decoded by name once the response is in
+	var response struct {
+		User map[string]json.RawMessage `json:"user"`
+	}
+	if err := c.api.DoWithContext(ctx, query, variables, &response); err != nil {
+		return nil, errors.New(errors.NetworkError, "failed to fetch contributions", err)
+	}
+
+	var login string
+	if raw, ok := response.User["login"]; !ok || json.Unmarshal(raw, &login) != nil || login == "" {
+		return nil, errors.New(errors.ValidationError, "received empty username from GitHub API", nil)
+	}
+	results := make([]YearResult, len(years))
+	for i, year := range years {
+		raw, ok := response.User[fmt.Sprintf("y%d", year)]
+		if !ok {
+			return nil, errors.New(errors.GraphQLError, fmt.Sprintf("response is missing the contributions of %d", year), nil)
+		}
+		result := &types.ContributionsResponse{}
+		result.User.Login = login
+		if err := json.Unmarshal(raw, &result.User.ContributionsCollection); err != nil {
+			return nil, errors.New(errors.GraphQLError, fmt.Sprintf("failed to decode the contributions of %d", year), err)
+		}
+		results[i] = YearResult{Year: year, Response: result}
+	}
+	return results, nil
+}
+
 // GetUserJoinYear fetches the year a user joined GitHub using the GitHub API.
-func (c *Client) GetUserJoinYear(username string) (int, error) {
+func (c *Client) GetUserJoinYear(ctx context.Context, username string) (int, error) {
 	if username == "" {
 		return 0, errors.New(errors.ValidationError, "username cannot be empty", nil)
 	}
@@ -132,7 +280,7 @@ func (c *Client) GetUserJoinYear(username string) (int, error) {
 	}
 
 	// Execute the GraphQL query.
-	err := c.api.Do(query, variables, &response)
+	err := c.api.DoWithContext(ctx, query, variables, &response)
 	if err != nil {
 		return 0, errors.New(errors.NetworkError, "failed to fetch user's join date", err)
 	}
@@ -145,3 +293,8 @@ func (c *Client) GetUserJoinYear(username string) (int, error) {
 
 	return joinYear, nil
 }
+
+// ProfileURL returns the address of a user's profile page on the given GitHub host.
+func ProfileURL(hostname, username string) string {
+	return fmt.Sprintf("https://%s/%s", hostname, username)
+}
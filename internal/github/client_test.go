@@ -1,10 +1,17 @@
 package github
 
 import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/testutil/fixtures"
 	"github.com/github/gh-skyline/internal/testutil/mocks"
 	"github.com/github/gh-skyline/internal/types"
 )
@@ -42,7 +49,7 @@ func TestGetAuthenticatedUser(t *testing.T) {
 				Err:      tt.mockError,
 			})
 
-			user, err := client.GetAuthenticatedUser()
+			user, err := client.GetAuthenticatedUser(context.Background())
 			if (err != nil) != tt.expectedError {
 				t.Errorf("expected error: %v, got: %v", tt.expectedError, err)
 			}
@@ -89,7 +96,7 @@ func TestGetUserJoinYear(t *testing.T) {
 				Err:      tt.mockError,
 			})
 
-			year, err := client.GetUserJoinYear(tt.username)
+			year, err := client.GetUserJoinYear(context.Background(), tt.username)
 			if (err != nil) != tt.expectedError {
 				t.Errorf("expected error: %v, got: %v", tt.expectedError, err)
 			}
@@ -190,7 +197,7 @@ func TestFetchContributions(t *testing.T) {
 				Err:      tt.mockError,
 			})
 
-			resp, err := client.FetchContributions(tt.username, tt.year)
+			resp, err := client.FetchContributions(context.Background(), tt.username, tt.year)
 			if (err != nil) != tt.expectedError {
 				t.Errorf("expected error: %v, got: %v", tt.expectedError, err)
 			}
@@ -204,3 +211,200 @@ func TestFetchContributions(t *testing.T) {
 		})
 	}
 }
+
+func TestCancelledContext(t *testing.T) {
+	client := NewClient(&mocks.MockGitHubClient{Username: "testuser", JoinYear: 2015})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := client.GetAuthenticatedUser(ctx); !stderrors.Is(err, context.Canceled) {
+		t.Errorf("GetAuthenticatedUser() error = %v, want context.Canceled", err)
+	}
+	if _, err := client.GetUserJoinYear(ctx, "testuser"); !stderrors.Is(err, context.Canceled) {
+		t.Errorf("GetUserJoinYear() error = %v, want context.Canceled", err)
+	}
+	if _, err := client.FetchContributions(ctx, "testuser", 2024); !stderrors.Is(err, context.Canceled) {
+		t.Errorf("FetchContributions() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestProfileURL(t *testing.T) {
+	tests := []struct {
+		hostname string
+		username string
+		want     string
+	}{
+		{"github.com", "octocat", "https://github.com/octocat"},
+		{"ghe.example.com", "mona", "https://ghe.example.com/mona"},
+	}
+	for _, tt := range tests {
+		if got := ProfileURL(tt.hostname, tt.username); got != tt.want {
+			t.Errorf("ProfileURL(%q, %q) = %q, want %q", tt.hostname, tt.username, got, tt.want)
+		}
+	}
+}
+
+func TestResolveHost(t *testing.T) {
+	tests := []struct {
+		hostname string
+		want     string
+	}{
+		{"github.com", "github.com"},
+		{"GHE.example.com", "ghe.example.com"},
+		{"https://ghe.example.com/", "ghe.example.com"},
+		{"api.github.com", "github.com"},
+	}
+	for _, tt := range tests {
+		if got := ResolveHost(tt.hostname); got != tt.want {
+			t.Errorf("ResolveHost(%q) = %q, want %q", tt.hostname, got, tt.want)
+		}
+	}
+}
+
+// batchAPI answers batched and single-year contribution queries from fixtures,
+// failing batched queries or single years on request.
+type batchAPI struct {
+	failBatch bool
+	failYears map[int]bool
+	queries   []string
+}
+
+func (a *batchAPI) DoWithContext(_ context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	a.queries = append(a.queries, query)
+	username := variables["username"].(string)
+	switch v := response.(type) {
+	case *types.ContributionsResponse:
+		year, _ := strconv.Atoi(variables["from"].(string)[:4])
+		if a.failYears[year] {
+			return fmt.Errorf("no data for %d", year)
+		}
+		*v = *fixtures.GenerateContributionsResponse(username, year)
+	default:
+		if a.failBatch {
+			return fmt.Errorf("query too complex")
+		}
+		user := map[string]interface{}{"login": username}
+		for name, value := range variables {
+			if !strings.HasPrefix(name, "from") {
+				continue
+			}
+			year, _ := strconv.Atoi(value.(string)[:4])
+			user[fmt.Sprintf("y%d", year)] = fixtures.GenerateContributionsResponse(username, year).User.ContributionsCollection
+		}
+		data, err := json.Marshal(map[string]interface{}{"user": user})
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, response)
+	}
+	return nil
+}
+
+func TestFetchContributionsBatch(t *testing.T) {
+	years := make([]int, 0, 15)
+	for year := 2010; year < 2025; year++ {
+		years = append(years, year)
+	}
+
+	tests := []struct {
+		name        string
+		api         *batchAPI
+		years       []int
+		wantQueries int
+		wantFailed  []int
+	}{
+		{name: "one batch", api: &batchAPI{}, years: years[:3], wantQueries: 1},
+		{name: "split into batches", api: &batchAPI{}, years: years, wantQueries: 2},
+		{name: "fallback to single years", api: &batchAPI{failBatch: true}, years: years[:3], wantQueries: 4},
+		{name: "fallback with a failing year", api: &batchAPI{failBatch: true, failYears: map[int]bool{2011: true}}, years: years[:3], wantQueries: 4, wantFailed: []int{2011}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := NewClient(tt.api).FetchContributionsBatch(context.Background(), "testuser", tt.years)
+			if err != nil {
+				t.Fatalf("FetchContributionsBatch() error = %v", err)
+			}
+			if len(tt.api.queries) != tt.wantQueries {
+				t.Errorf("sent %d queries, want %d", len(tt.api.queries), tt.wantQueries)
+			}
+			if len(results) != len(tt.years) {
+				t.Fatalf("got %d results, want %d", len(results), len(tt.years))
+			}
+
+			var failed []int
+			for i, result := range results {
+				if result.Year != tt.years[i] {
+					t.Errorf("result %d is for %d, want %d", i, result.Year, tt.years[i])
+				}
+				if result.Err != nil {
+					failed = append(failed, result.Year)
+					continue
+				}
+				weeks := result.Response.User.ContributionsCollection.ContributionCalendar.Weeks
+				if result.Response.User.Login != "testuser" || len(weeks) == 0 || !strings.HasPrefix(weeks[0].ContributionDays[0].Date, strconv.Itoa(result.Year)) {
+					t.Errorf("result for %d has the wrong contributions", result.Year)
+				}
+			}
+			if fmt.Sprint(failed) != fmt.Sprint(tt.wantFailed) && len(failed)+len(tt.wantFailed) > 0 {
+				t.Errorf("failed years = %v, want %v", failed, tt.wantFailed)
+			}
+		})
+	}
+
+	if _, err := NewClient(&batchAPI{}).FetchContributionsBatch(context.Background(), "", years); err == nil {
+		t.Error("FetchContributionsBatch() expected error for an empty username")
+	}
+	if _, err := NewClient(&batchAPI{}).FetchContributionsBatch(context.Background(), "testuser", []int{2007}); err == nil {
+		t.Error("FetchContributionsBatch() expected error for a year before 2008")
+	}
+}
+
+// orgAPI resolves one organization and records the contribution queries it answers.
+type orgAPI struct {
+	queries   []string
+	variables []map[string]interface{}
+}
+
+func (a *orgAPI) DoWithContext(_ context.Context, query string, variables map[string]interface{}, response interface{}) error {
+	if login, ok := variables["login"]; ok {
+		if login != "acme" {
+			return json.Unmarshal([]byte(`{"organization": null}`), response)
+		}
+		return json.Unmarshal([]byte(`{"organization": {"id": "O_acme"}}`), response)
+	}
+	a.queries = append(a.queries, query)
+	a.variables = append(a.variables, variables)
+	if v, ok := response.(*types.ContributionsResponse); ok {
+		*v = *fixtures.GenerateContributionsResponse("testuser", 2024)
+		return nil
+	}
+	return fmt.Errorf("batched query")
+}
+
+func TestScopeToOrganization(t *testing.T) {
+	api := &orgAPI{}
+	client := NewClient(api)
+	if err := client.ScopeToOrganization(context.Background(), "nobody"); err == nil {
+		t.Error("ScopeToOrganization() succeeded for an unknown organization")
+	}
+	if err := client.ScopeToOrganization(context.Background(), "acme"); err != nil {
+		t.Fatalf("ScopeToOrganization() error = %v", err)
+	}
+
+	if _, err := client.FetchContributions(context.Background(), "testuser", 2024); err != nil {
+		t.Fatalf("FetchContributions() error = %v", err)
+	}
+	// The batched query fails over to single years, so both are sent
+	if _, err := client.FetchContributionsBatch(context.Background(), "testuser", []int{2023, 2024}); err != nil {
+		t.Fatalf("FetchContributionsBatch() error = %v", err)
+	}
+	if len(api.queries) != 4 {
+		t.Fatalf("sent %d contribution queries, want 4", len(api.queries))
+	}
+	for i, query := range api.queries {
+		if !strings.Contains(query, "organizationID: $organizationID") || api.variables[i]["organizationID"] != "O_acme" {
+			t.Errorf("query %d is not limited to the organization:\n%s", i, query)
+		}
+	}
+}
@@ -3,18 +3,44 @@ package github
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/auth"
 )
 
-// ClientInitializer is a function type for initializing GitHub clients
-type ClientInitializer func() (*Client, error)
+// ClientInitializer is a function type for initializing GitHub clients for a host.
+// An empty hostname means the default host of the gh CLI.
+type ClientInitializer func(hostname string) (*Client, error)
 
-// InitializeGitHubClient is the default client initializer
-var InitializeGitHubClient ClientInitializer = func() (*Client, error) {
-	apiClient, err := api.DefaultGraphQLClient()
+// InitializeGitHubClient is the default client initializer. It uses the token
+// gh has for the host, such as a GitHub Enterprise Server instance.
+var InitializeGitHubClient ClientInitializer = func(hostname string) (*Client, error) {
+	if hostname == "" {
+		apiClient, err := api.DefaultGraphQLClient()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GraphQL client: %w", err)
+		}
+		return NewClient(apiClient), nil
+	}
+
+	token, _ := auth.TokenForHost(hostname)
+	if token == "" {
+		return nil, fmt.Errorf("no token found for %s; run 'gh auth login --hostname %s'", hostname, hostname)
+	}
+	apiClient, err := api.NewGraphQLClient(api.ClientOptions{Host: hostname, AuthToken: token})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create GraphQL client: %w", err)
+		return nil, fmt.Errorf("failed to create GraphQL client for %s: %w", hostname, err)
 	}
 	return NewClient(apiClient), nil
 }
+
+// ResolveHost returns the normalized hostname, or the default host of the gh CLI
+// when it is empty. A scheme or trailing slash pasted with the host is removed.
+func ResolveHost(hostname string) string {
+	hostname = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(hostname, "https://"), "http://"), "/")
+	if hostname == "" {
+		hostname, _ = auth.DefaultHost()
+	}
+	return auth.NormalizeHostname(hostname)
+}
@@ -0,0 +1,148 @@
+package qrcode
+
+// eccCodewordsPerBlock is the number of error correction codewords in each block,
+// indexed [level][version]. Index 0 is unused.
+var eccCodewordsPerBlock = [4][MaxVersion + 1]int{
+	{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// errorCorrectionBlocks is the number of blocks the codewords are split into,
+// indexed [level][version]. Index 0 is unused.
+var errorCorrectionBlocks = [4][MaxVersion + 1]int{
+	{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// Pad codewords that fill the unused data capacity, alternately.
+var padCodewords = [2]byte{0xEC, 0x11}
+
+// rawDataModules returns the number of modules available for codewords in a
+// symbol: everything except the function patterns and format/version information.
+func rawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		result -= (25*align-10)*align - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+// dataCodewordCount returns the number of data codewords a symbol holds.
+func dataCodewordCount(version int, level Level) int {
+	return rawDataModules(version)/8 - eccCodewordsPerBlock[level][version]*errorCorrectionBlocks[level][version]
+}
+
+// characterCountBits returns the length of the byte mode character count field.
+func characterCountBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// alignmentPatternPositions returns the row and column coordinates of the
+// alignment pattern centres. They are spaced evenly from the last one at
+// Size-7 down to the second, and the first is always 6.
+func alignmentPatternPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	count := version/7 + 2
+	step := 26
+	if version != 32 {
+		step = (version*4 + count*2 + 1) / (count*2 - 2) * 2
+	}
+
+	positions := make([]int, count)
+	positions[0] = 6
+	for i, pos := count-1, version*4+17-7; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// bitBuffer accumulates a bit stream, most significant bit first.
+type bitBuffer struct {
+	bytes []byte
+	n     int // Number of bits written
+}
+
+// append writes the low n bits of value.
+func (b *bitBuffer) append(value, n int) {
+	for i := n - 1; i >= 0; i-- {
+		if b.n%8 == 0 {
+			b.bytes = append(b.bytes, 0)
+		}
+		if value>>i&1 == 1 {
+			b.bytes[b.n/8] |= 0x80 >> (b.n % 8)
+		}
+		b.n++
+	}
+}
+
+// dataCodewords encodes data as a byte mode segment and pads it to the data
+// capacity of the symbol. The data must fit the symbol.
+func dataCodewords(data []byte, version int, level Level) []byte {
+	capacity := dataCodewordCount(version, level)
+
+	var buf bitBuffer
+	buf.append(0b0100, 4) // Byte mode indicator
+	buf.append(len(data), characterCountBits(version))
+	for _, b := range data {
+		buf.append(int(b), 8)
+	}
+
+	buf.append(0, min(4, capacity*8-buf.n)) // Terminator
+	buf.append(0, (8-buf.n%8)%8)
+	for i := 0; len(buf.bytes) < capacity; i++ {
+		buf.bytes = append(buf.bytes, padCodewords[i%2])
+	}
+	return buf.bytes
+}
+
+// addErrorCorrection splits the data codewords into blocks, appends each block's
+// Reed-Solomon codewords and interleaves the blocks into the final sequence.
+// Short blocks come first and hold one data codeword less than long blocks.
+func addErrorCorrection(data []byte, version int, level Level) []byte {
+	blockCount := errorCorrectionBlocks[level][version]
+	eccLen := eccCodewordsPerBlock[level][version]
+	rawCodewords := rawDataModules(version) / 8
+	shortBlocks := blockCount - rawCodewords%blockCount
+	shortDataLen := rawCodewords/blockCount - eccLen
+
+	generator := reedSolomonGenerator(eccLen)
+	dataBlocks := make([][]byte, blockCount)
+	eccBlocks := make([][]byte, blockCount)
+	for i, k := 0, 0; i < blockCount; i++ {
+		n := shortDataLen
+		if i >= shortBlocks {
+			n++
+		}
+		dataBlocks[i] = data[k : k+n]
+		eccBlocks[i] = reedSolomonRemainder(dataBlocks[i], generator)
+		k += n
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := 0; i <= shortDataLen; i++ {
+		for _, block := range dataBlocks {
+			if i < len(block) {
+				result = append(result, block[i])
+			}
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for _, block := range eccBlocks {
+			result = append(result, block[i])
+		}
+	}
+	return result
+}
@@ -0,0 +1,89 @@
+package qrcode
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestCapacity checks byte mode capacities against the standard's tables.
+func TestCapacity(t *testing.T) {
+	tests := []struct {
+		version int
+		level   Level
+		want    int
+	}{
+		{1, Low, 17},
+		{1, Medium, 14},
+		{1, Quartile, 11},
+		{1, High, 7},
+		{10, Low, 271},
+		{10, Medium, 213},
+		{10, Quartile, 151},
+		{10, High, 119},
+		{40, Low, 2953},
+		{40, Medium, 2331},
+		{40, Quartile, 1663},
+		{40, High, 1273},
+	}
+
+	for _, tt := range tests {
+		if got := Capacity(tt.version, tt.level); got != tt.want {
+			t.Errorf("Capacity(%d, %s) = %d, want %d", tt.version, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestAlignmentPatternPositions(t *testing.T) {
+	tests := []struct {
+		version int
+		want    []int
+	}{
+		{1, nil},
+		{2, []int{6, 18}},
+		{7, []int{6, 22, 38}},
+		{32, []int{6, 34, 60, 86, 112, 138}},
+		{40, []int{6, 30, 58, 86, 114, 142, 170}},
+	}
+
+	for _, tt := range tests {
+		if got := alignmentPatternPositions(tt.version); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("alignmentPatternPositions(%d) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestDataCodewords(t *testing.T) {
+	got := dataCodewords([]byte("Hi"), 1, High)
+	// Mode 0100, count 00000010, 'H' 01001000, 'i' 01101001, terminator 0000, then padding
+	want := []byte{0x40, 0x24, 0x86, 0x90, 0xEC, 0x11, 0xEC, 0x11, 0xEC}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dataCodewords() = %#v, want %#v", got, want)
+	}
+}
+
+// TestAddErrorCorrection verifies blocks of unequal length are interleaved with
+// the short blocks first.
+func TestAddErrorCorrection(t *testing.T) {
+	// Version 5-Q has two blocks of 15 and two of 16 data codewords, with 18 ECC codewords each
+	data := make([]byte, dataCodewordCount(5, Quartile))
+	for i := range data {
+		data[i] = byte(i)
+	}
+	got := addErrorCorrection(data, 5, Quartile)
+
+	if len(got) != rawDataModules(5)/8 {
+		t.Fatalf("len = %d, want %d", len(got), rawDataModules(5)/8)
+	}
+	wantStart := []byte{0, 15, 30, 46, 1, 16, 31, 47}
+	if !reflect.DeepEqual(got[:8], wantStart) {
+		t.Errorf("interleaved start = %v, want %v", got[:8], wantStart)
+	}
+	// Only the long blocks have a 16th data codeword
+	if got[60:62][0] != 45 || got[61] != 61 {
+		t.Errorf("last data codewords = %v, want [45 61]", got[60:62])
+	}
+	generator := reedSolomonGenerator(18)
+	if want := reedSolomonRemainder(data[:15], generator)[0]; got[62] != want {
+		t.Errorf("first ECC codeword = %d, want %d", got[62], want)
+	}
+}
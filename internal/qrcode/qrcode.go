@@ -0,0 +1,434 @@
+// Package qrcode encodes data as QR Code symbols (ISO/IEC 18004).
+// Data is always encoded in byte mode, using the smallest version that fits.
+package qrcode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// Level is the error correction level of a QR code. Higher levels survive more
+// damage at the cost of a larger symbol.
+type Level int
+
+// Error correction levels, with the share of the symbol they can restore.
+const (
+	Low      Level = iota // About 7%
+	Medium                // About 15%
+	Quartile              // About 25%
+	High                  // About 30%
+)
+
+// Symbol size limits.
+const (
+	MinVersion = 1
+	MaxVersion = 40
+
+	// QuietZone is the number of light modules the standard requires around a symbol.
+	QuietZone = 4
+)
+
+// ParseLevel parses an error correction level name: L, M, Q or H, or the full
+// names low, medium, quartile and high.
+func ParseLevel(s string) (Level, error) {
+	switch strings.ToLower(s) {
+	case "l", "low":
+		return Low, nil
+	case "m", "medium":
+		return Medium, nil
+	case "q", "quartile":
+		return Quartile, nil
+	case "h", "high":
+		return High, nil
+	}
+	return 0, errors.New(errors.ValidationError, fmt.Sprintf("unknown error correction level %q (use L, M, Q or H)", s), nil)
+}
+
+// String returns the level's one-letter name.
+func (l Level) String() string {
+	if l < Low || l > High {
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+	return [...]string{"L", "M", "Q", "H"}[l]
+}
+
+// Code is an encoded QR code symbol.
+type Code struct {
+	Version int   // Symbol version, 1 to 40
+	Level   Level // Error correction level
+	Mask    int   // Data mask pattern, 0 to 7
+	Size    int   // Width and height in modules, excluding the quiet zone
+
+	modules    [][]bool // Dark modules, indexed [y][x]
+	isFunction [][]bool // Modules reserved for function patterns
+}
+
+// Encode returns the smallest QR code that holds data at the given error correction level.
+func Encode(data []byte, level Level) (*Code, error) {
+	if level < Low || level > High {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("invalid error correction level %d", level), nil)
+	}
+
+	version := MinVersion
+	for ; version <= MaxVersion; version++ {
+		if len(data) <= Capacity(version, level) {
+			break
+		}
+	}
+	if version > MaxVersion {
+		return nil, errors.New(errors.ValidationError,
+			fmt.Sprintf("data is too long for a QR code: %d bytes, at most %d at level %s", len(data), Capacity(MaxVersion, level), level), nil)
+	}
+
+	c := newCode(version, level)
+	c.drawCodewords(addErrorCorrection(dataCodewords(data, version, level), version, level))
+	c.applyBestMask()
+	return c, nil
+}
+
+// Capacity returns the number of bytes a symbol of the given version and level holds.
+func Capacity(version int, level Level) int {
+	bits := dataCodewordCount(version, level)*8 - 4 - characterCountBits(version)
+	return bits / 8
+}
+
+// Dark reports whether the module at column x and row y is dark. Coordinates
+// outside the symbol, such as the quiet zone, are light.
+func (c *Code) Dark(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.Size && y < c.Size && c.modules[y][x]
+}
+
+// Bitmap returns the symbol surrounded by quietZone light modules on each side,
+// indexed [y][x] with true for dark modules.
+func (c *Code) Bitmap(quietZone int) [][]bool {
+	size := c.Size + 2*quietZone
+	bitmap := make([][]bool, size)
+	for y := range bitmap {
+		bitmap[y] = make([]bool, size)
+		for x := range bitmap[y] {
+			bitmap[y][x] = c.Dark(x-quietZone, y-quietZone)
+		}
+	}
+	return bitmap
+}
+
+// String renders the symbol with its quiet zone as text, two characters per module.
+func (c *Code) String() string {
+	var b strings.Builder
+	for _, row := range c.Bitmap(QuietZone) {
+		for _, dark := range row {
+			if dark {
+				b.WriteString("██")
+			} else {
+				b.WriteString("  ")
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// newCode returns a symbol with its function patterns drawn and no data.
+func newCode(version int, level Level) *Code {
+	size := version*4 + 17
+	c := &Code{Version: version, Level: level, Size: size}
+	c.modules = make([][]bool, size)
+	c.isFunction = make([][]bool, size)
+	for y := 0; y < size; y++ {
+		c.modules[y] = make([]bool, size)
+		c.isFunction[y] = make([]bool, size)
+	}
+	c.drawFunctionPatterns()
+	return c
+}
+
+// setFunction sets a function module, which data and masks never touch.
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.isFunction[y][x] = true
+}
+
+// drawFunctionPatterns draws the finder, timing and alignment patterns and the
+// version information, and reserves the format information area.
+func (c *Code) drawFunctionPatterns() {
+	for i := 0; i < c.Size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinderPattern(3, 3)
+	c.drawFinderPattern(c.Size-4, 3)
+	c.drawFinderPattern(3, c.Size-4)
+
+	positions := alignmentPatternPositions(c.Version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			// Skip the three corners occupied by finder patterns
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			c.drawAlignmentPattern(x, y)
+		}
+	}
+
+	c.drawFormatBits(0) // Reserve the area; the real bits are drawn once the mask is chosen
+	c.drawVersionBits()
+}
+
+// drawFinderPattern draws a finder pattern and its separator centred on (x, y).
+func (c *Code) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= c.Size || yy >= c.Size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(xx, yy, dist != 2 && dist != 4)
+		}
+	}
+}
+
+// drawAlignmentPattern draws a 5x5 alignment pattern centred on (x, y).
+func (c *Code) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormatBits draws both copies of the format information for mask, plus the
+// dark module next to the bottom-left finder pattern.
+func (c *Code) drawFormatBits(mask int) {
+	bits := formatBits(c.Level, mask)
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(bits, i))
+	}
+	c.setFunction(8, 7, bit(bits, 6))
+	c.setFunction(8, 8, bit(bits, 7))
+	c.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(bits, i))
+	}
+	c.setFunction(8, c.Size-8, true)
+}
+
+// drawVersionBits draws both copies of the version information (versions 7 and up).
+func (c *Code) drawVersionBits() {
+	if c.Version < 7 {
+		return
+	}
+	bits := versionBits(c.Version)
+	for i := 0; i < 18; i++ {
+		a, b := c.Size-11+i%3, i/3
+		c.setFunction(a, b, bit(bits, i))
+		c.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// drawCodewords places the codewords in the zigzag order of the standard: two
+// columns at a time from the bottom-right corner, alternating up and down and
+// skipping the vertical timing pattern.
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if c.isFunction[y][x] || i >= len(codewords)*8 {
+					continue // Remainder bits stay light
+				}
+				c.modules[y][x] = codewords[i/8]>>(7-i%8)&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+// applyBestMask applies the data mask with the lowest penalty score and draws the
+// matching format information.
+func (c *Code) applyBestMask() {
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormatBits(mask)
+		if p := c.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		c.applyMask(mask) // Masks are their own inverse
+	}
+	c.setMask(best)
+}
+
+// setMask applies mask to the unmasked data and draws its format information.
+func (c *Code) setMask(mask int) {
+	c.applyMask(mask)
+	c.drawFormatBits(mask)
+	c.Mask = mask
+}
+
+// applyMask inverts the data modules selected by mask.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.isFunction[y][x] && maskBit(mask, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+// maskBit reports whether mask inverts the module at (x, y).
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// Penalty weights of the mask evaluation rules.
+const (
+	penaltyRun     = 3  // Run of five same-coloured modules, plus one per extra module
+	penaltyBlock   = 3  // 2x2 block of one colour
+	penaltyFinder  = 40 // Pattern that looks like a finder pattern
+	penaltyBalance = 10 // Each 5% the dark share deviates from 50%, beyond the first
+)
+
+// penalty scores how hard the symbol is to read; lower is better.
+func (c *Code) penalty() int {
+	total := 0
+	row, column := make([]bool, c.Size), make([]bool, c.Size)
+	for i := 0; i < c.Size; i++ {
+		for j := 0; j < c.Size; j++ {
+			row[j], column[j] = c.modules[i][j], c.modules[j][i]
+		}
+		total += linePenalty(row) + linePenalty(column)
+	}
+
+	dark := 0
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.modules[y][x] {
+				dark++
+			}
+			if x+1 < c.Size && y+1 < c.Size {
+				color := c.modules[y][x]
+				if c.modules[y][x+1] == color && c.modules[y+1][x] == color && c.modules[y+1][x+1] == color {
+					total += penaltyBlock
+				}
+			}
+		}
+	}
+
+	modules := c.Size * c.Size
+	deviation := (abs(dark*20-modules*10) + modules - 1) / modules // ceil(|dark% - 50%| / 5%)
+	return total + (deviation-1)*penaltyBalance
+}
+
+// finderLike is the 1:1:3:1:1 dark-light ratio of a finder pattern.
+var finderLike = []bool{true, false, true, true, true, false, true}
+
+// linePenalty scores the runs and finder-like patterns in one row or column.
+// Modules beyond the ends of the line belong to the quiet zone and are light.
+func linePenalty(line []bool) int {
+	total := 0
+	for start := 0; start < len(line); {
+		end := start
+		for end < len(line) && line[end] == line[start] {
+			end++
+		}
+		if run := end - start; run >= 5 {
+			total += penaltyRun + run - 5
+		}
+		start = end
+	}
+
+	light := func(from, to int) bool {
+		for i := from; i < to; i++ {
+			if i >= 0 && i < len(line) && line[i] {
+				return false
+			}
+		}
+		return true
+	}
+	for i := 0; i+len(finderLike) <= len(line); i++ {
+		match := true
+		for j, dark := range finderLike {
+			if line[i+j] != dark {
+				match = false
+				break
+			}
+		}
+		if match && (light(i-4, i) || light(i+len(finderLike), i+len(finderLike)+4)) {
+			total += penaltyFinder
+		}
+	}
+	return total
+}
+
+// formatBits returns the 15-bit format information for a level and mask: five
+// data bits protected by a BCH(15,5) code and XORed with a fixed pattern.
+func formatBits(level Level, mask int) int {
+	data := [...]int{1, 0, 3, 2}[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// versionBits returns the 18-bit version information: the version protected by a
+// BCH(18,6) code.
+func versionBits(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return version<<12 | rem
+}
+
+// bit reports whether bit i of x is set.
+func bit(x, i int) bool {
+	return x>>i&1 == 1
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
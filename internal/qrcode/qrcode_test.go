@@ -0,0 +1,216 @@
+package qrcode
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Level
+		wantErr bool
+	}{
+		{"L", Low, false},
+		{"m", Medium, false},
+		{"quartile", Quartile, false},
+		{"H", High, false},
+		{"X", 0, true},
+		{"", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseLevel(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("ParseLevel(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFormatBits(t *testing.T) {
+	tests := []struct {
+		level Level
+		mask  int
+		want  int
+	}{
+		{Medium, 0, 0b101010000010010},
+		{Low, 4, 0b110011000101111},
+		{High, 7, 0b000100000111011},
+	}
+	for _, tt := range tests {
+		if got := formatBits(tt.level, tt.mask); got != tt.want {
+			t.Errorf("formatBits(%s, %d) = %015b, want %015b", tt.level, tt.mask, got, tt.want)
+		}
+	}
+}
+
+func TestVersionBits(t *testing.T) {
+	tests := []struct{ version, want int }{
+		{7, 0x07C94},
+		{21, 0x15683},
+		{40, 0x28C69},
+	}
+	for _, tt := range tests {
+		if got := versionBits(tt.version); got != tt.want {
+			t.Errorf("versionBits(%d) = %#x, want %#x", tt.version, got, tt.want)
+		}
+	}
+}
+
+// TestEncode verifies version selection, function patterns and that the data
+// can be read back from the symbol.
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		level       Level
+		wantVersion int
+	}{
+		{"short", "Hi", High, 1},
+		{"fills version 1", strings.Repeat("a", 17), Low, 1},
+		{"one byte over", strings.Repeat("a", 18), Low, 2},
+		{"profile URL", "https://github.com/octocat", Medium, 2},
+		{"version information", strings.Repeat("skyline ", 20), Quartile, 11},
+		{"unequal blocks", strings.Repeat("x", 1000), High, 36},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Encode([]byte(tt.data), tt.level)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if code.Version != tt.wantVersion || code.Size != tt.wantVersion*4+17 {
+				t.Errorf("version %d size %d, want version %d", code.Version, code.Size, tt.wantVersion)
+			}
+
+			// Finder pattern centres are dark and surrounded by a light ring
+			for _, corner := range [][2]int{{3, 3}, {code.Size - 4, 3}, {3, code.Size - 4}} {
+				x, y := corner[0], corner[1]
+				if !code.Dark(x, y) || code.Dark(x+2, y) || !code.Dark(x+3, y) {
+					t.Errorf("missing finder pattern at (%d, %d)", x, y)
+				}
+			}
+			if got := decodeFormat(code); got != formatBits(tt.level, code.Mask) {
+				t.Errorf("format bits %015b do not match level %s mask %d", got, tt.level, code.Mask)
+			}
+
+			if got := readData(code); !bytes.Equal(got, []byte(tt.data)) {
+				t.Errorf("read back %q, want %q", got, tt.data)
+			}
+		})
+	}
+}
+
+func TestEncodeErrors(t *testing.T) {
+	if _, err := Encode(make([]byte, 1274), High); err == nil {
+		t.Error("Encode() expected error for data over the version 40-H capacity")
+	}
+	if _, err := Encode([]byte("x"), Level(7)); err == nil {
+		t.Error("Encode() expected error for an invalid level")
+	}
+}
+
+func TestBitmap(t *testing.T) {
+	code, err := Encode([]byte("skyline"), Medium)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	bitmap := code.Bitmap(QuietZone)
+	if len(bitmap) != code.Size+2*QuietZone || len(bitmap[0]) != len(bitmap) {
+		t.Fatalf("bitmap is %dx%d, want %d square", len(bitmap[0]), len(bitmap), code.Size+2*QuietZone)
+	}
+	for i := 0; i < len(bitmap); i++ {
+		if bitmap[0][i] || bitmap[i][0] || bitmap[QuietZone-1][i] {
+			t.Fatal("quiet zone contains dark modules")
+		}
+	}
+	if !bitmap[QuietZone][QuietZone] {
+		t.Error("top-left finder pattern should start right after the quiet zone")
+	}
+}
+
+// decodeFormat reads the first copy of the format information.
+func decodeFormat(c *Code) int {
+	bits := 0
+	set := func(i int, dark bool) {
+		if dark {
+			bits |= 1 << i
+		}
+	}
+	for i := 0; i <= 5; i++ {
+		set(i, c.Dark(8, i))
+	}
+	set(6, c.Dark(8, 7))
+	set(7, c.Dark(8, 8))
+	set(8, c.Dark(7, 8))
+	for i := 9; i < 15; i++ {
+		set(i, c.Dark(14-i, 8))
+	}
+	return bits
+}
+
+// readData reverses the encoding: it unmasks the symbol, reads the codewords in
+// placement order, de-interleaves the data blocks and decodes the byte segment.
+func readData(c *Code) []byte {
+	layout := newCode(c.Version, c.Level)
+	var buf bitBuffer
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		upward := (right+1)&2 == 0
+		for vert := 0; vert < c.Size; vert++ {
+			y := vert
+			if upward {
+				y = c.Size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if layout.isFunction[y][x] {
+					continue
+				}
+				dark := c.Dark(x, y) != maskBit(c.Mask, x, y)
+				if dark {
+					buf.append(1, 1)
+				} else {
+					buf.append(0, 1)
+				}
+			}
+		}
+	}
+
+	blockCount := errorCorrectionBlocks[c.Level][c.Version]
+	rawCodewords := rawDataModules(c.Version) / 8
+	shortBlocks := blockCount - rawCodewords%blockCount
+	shortDataLen := rawCodewords/blockCount - eccCodewordsPerBlock[c.Level][c.Version]
+	blocks := make([][]byte, blockCount)
+	k := 0
+	for i := 0; i <= shortDataLen; i++ {
+		for b := range blocks {
+			if i < shortDataLen || b >= shortBlocks {
+				blocks[b] = append(blocks[b], buf.bytes[k])
+				k++
+			}
+		}
+	}
+	data := bytes.Join(blocks, nil)
+
+	count := 0
+	for i := 0; i < characterCountBits(c.Version); i++ {
+		count = count<<1 | int(data[(4+i)/8]>>(7-(4+i)%8)&1)
+	}
+	start := 4 + characterCountBits(c.Version)
+	result := make([]byte, count)
+	for i := range result {
+		for j := 0; j < 8; j++ {
+			pos := start + i*8 + j
+			result[i] = result[i]<<1 | data[pos/8]>>(7-pos%8)&1
+		}
+	}
+	return result
+}
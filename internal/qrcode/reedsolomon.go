@@ -0,0 +1,51 @@
+package qrcode
+
+// gfMultiply multiplies two elements of GF(2^8) modulo the QR code polynomial
+// x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	var z byte
+	for i := 7; i >= 0; i-- {
+		carry := z >> 7
+		z = z<<1 ^ carry*0x1D
+		if y>>i&1 == 1 {
+			z ^= x
+		}
+	}
+	return z
+}
+
+// reedSolomonGenerator returns the coefficients, highest power first and without
+// the leading 1, of the generator polynomial (x - a^0)(x - a^1)...(x - a^(degree-1))
+// where a = 2.
+func reedSolomonGenerator(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1 // Start with the polynomial 1
+
+	var root byte = 1
+	for i := 0; i < degree; i++ {
+		// Multiply the current product by (x - root)
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 2)
+	}
+	return result
+}
+
+// reedSolomonRemainder returns the error correction codewords of data: the
+// remainder of data divided by the generator polynomial.
+func reedSolomonRemainder(data, generator []byte) []byte {
+	result := make([]byte, len(generator))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coefficient := range generator {
+			result[i] ^= gfMultiply(coefficient, factor)
+		}
+	}
+	return result
+}
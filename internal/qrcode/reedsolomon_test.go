@@ -0,0 +1,53 @@
+package qrcode
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestReedSolomonRemainder checks error correction codewords against the worked
+// examples for version 1-M symbols.
+func TestReedSolomonRemainder(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want []byte
+	}{
+		{
+			name: "numeric 01234567",
+			data: []byte{16, 32, 12, 86, 97, 128, 236, 17, 236, 17, 236, 17, 236, 17, 236, 17},
+			want: []byte{165, 36, 212, 193, 237, 54, 199, 135, 44, 85},
+		},
+		{
+			name: "alphanumeric HELLO WORLD",
+			data: []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17},
+			want: []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := reedSolomonRemainder(tt.data, reedSolomonGenerator(len(tt.want)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("reedSolomonRemainder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGFMultiply(t *testing.T) {
+	tests := []struct{ x, y, want byte }{
+		{0, 0x53, 0},
+		{1, 0x53, 0x53},
+		{2, 0x80, 0x1D},    // x * x^7 wraps around the field polynomial
+		{0x80, 0x80, 0x13}, // x^14 = x^10 + x^9 + x^8 + x^6
+	}
+	for _, tt := range tests {
+		if got := gfMultiply(tt.x, tt.y); got != tt.want {
+			t.Errorf("gfMultiply(%#x, %#x) = %#x, want %#x", tt.x, tt.y, got, tt.want)
+		}
+		if got := gfMultiply(tt.y, tt.x); got != tt.want {
+			t.Errorf("gfMultiply(%#x, %#x) = %#x, want %#x", tt.y, tt.x, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package stl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Anchor names a position on the top face of the base where a character model is placed.
+type Anchor string
+
+// Supported anchor positions. "Back" is the side furthest from the front face text.
+const (
+	AnchorBackRight   Anchor = "back-right"
+	AnchorBackLeft    Anchor = "back-left"
+	AnchorBackCenter  Anchor = "back-center"
+	AnchorFrontRight  Anchor = "front-right"
+	AnchorFrontLeft   Anchor = "front-left"
+	AnchorFrontCenter Anchor = "front-center"
+	AnchorCenter      Anchor = "center"
+)
+
+// Default character placement, matching the original character.stl merge.
+const (
+	defaultCharacterScale   = 0.7
+	defaultCharacterZOffset = -0.5 // Sink slightly into the base so the model fuses with it
+	characterMarginX        = 10.0 // Distance from the left/right edge of the base (mm)
+	characterMarginY        = 3.0  // Distance from the front/back edge of the base (mm)
+)
+
+// CharacterOptions describes an external model (e.g. a mascot) merged onto the top of the base.
+type CharacterOptions struct {
+	Path     string  // STL file to merge (binary or ASCII)
+	Scale    float64 // Uniform scale factor, ignored when Height is set
+	Height   float64 // Target height in mm after scaling (optional)
+	Rotation float64 // Rotation around the Z axis in degrees
+	Anchor   Anchor  // Position on the top face of the base
+	ZOffset  float64 // Vertical offset from the top face in mm
+}
+
+// ParseCharacterSpec parses a character specification of the form
+//
+//	path[,scale=0.7][,height=20][,rotate=90][,anchor=back-right][,z=-0.5]
+//
+// Unspecified settings fall back to the defaults of the original character merge.
+func ParseCharacterSpec(spec string) (CharacterOptions, error) {
+	parts := strings.Split(spec, ",")
+	opts := CharacterOptions{
+		Path:    strings.TrimSpace(parts[0]),
+		Scale:   defaultCharacterScale,
+		Anchor:  AnchorBackRight,
+		ZOffset: defaultCharacterZOffset,
+	}
+	if opts.Path == "" {
+		return CharacterOptions{}, errors.New(errors.ValidationError, "character path cannot be empty", nil)
+	}
+
+	for _, part := range parts[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return CharacterOptions{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid character setting %q (expected key=value)", part), nil)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "anchor" {
+			opts.Anchor = Anchor(strings.ToLower(value))
+			continue
+		}
+
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return CharacterOptions{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid value for character setting %q", key), err)
+		}
+		switch key {
+		case "scale":
+			opts.Scale = number
+		case "height":
+			opts.Height = number
+		case "rotate", "rotation":
+			opts.Rotation = number
+		case "z", "zoffset":
+			opts.ZOffset = number
+		default:
+			return CharacterOptions{}, errors.New(errors.ValidationError, fmt.Sprintf("unknown character setting %q", key), nil)
+		}
+	}
+
+	return opts, opts.Validate()
+}
+
+// Validate checks that the character options can be applied.
+func (o CharacterOptions) Validate() error {
+	if o.Path == "" {
+		return errors.New(errors.ValidationError, "character path cannot be empty", nil)
+	}
+	if o.Scale <= 0 && o.Height <= 0 {
+		return errors.New(errors.ValidationError, "character scale or height must be positive", nil)
+	}
+	if o.Height < 0 {
+		return errors.New(errors.ValidationError, "character height cannot be negative", nil)
+	}
+	if _, _, err := anchorFractions(o.Anchor); err != nil {
+		return err
+	}
+	return nil
+}
+
+// anchorFractions returns where an anchor sits along the width and depth of the base,
+// from 0 (left/front) to 1 (right/back).
+func anchorFractions(anchor Anchor) (float64, float64, error) {
+	switch anchor {
+	case AnchorBackRight, "":
+		return 1, 1, nil
+	case AnchorBackLeft:
+		return 0, 1, nil
+	case AnchorBackCenter:
+		return 0.5, 1, nil
+	case AnchorFrontRight:
+		return 1, 0, nil
+	case AnchorFrontLeft:
+		return 0, 0, nil
+	case AnchorFrontCenter:
+		return 0.5, 0, nil
+	case AnchorCenter:
+		return 0.5, 0.5, nil
+	}
+	return 0, 0, errors.New(errors.ValidationError, fmt.Sprintf("unknown character anchor %q", anchor), nil)
+}
+
+// loadCharacter reads a character model and places it on the top face of the base.
+func loadCharacter(opts CharacterOptions, dims modelDimensions) ([]types.Triangle, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	triangles, err := ReadSTL(opts.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to read character model %s", opts.Path))
+	}
+	if len(triangles) == 0 {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("character model %s contains no triangles", opts.Path), nil)
+	}
+
+	scale := opts.Scale
+	if opts.Height > 0 {
+		height := geometry.CalculateBoundingBox(triangles).Size().Z
+		if height <= 0 {
+			return nil, errors.New(errors.ValidationError, fmt.Sprintf("character model %s is flat and cannot be scaled to a height", opts.Path), nil)
+		}
+		scale = opts.Height / height
+	}
+	transform := geometry.Scaling(scale, scale, scale).Then(geometry.RotationZ(opts.Rotation))
+	triangles = geometry.TransformTriangles(triangles, transform)
+
+	box := geometry.CalculateBoundingBox(triangles)
+	size := box.Size()
+	fx, fy, _ := anchorFractions(opts.Anchor)
+
+	// Interpolate between the left/front and right/back positions, keeping the margins
+	left := characterMarginX
+	right := dims.innerWidth - characterMarginX - size.X
+	front := characterMarginY
+	back := dims.innerDepth - characterMarginY - size.Y
+
+	dx := left + fx*(right-left) - box.Min.X
+	dy := front + fy*(back-front) - box.Min.Y
+	dz := opts.ZOffset - box.Min.Z
+
+	return geometry.TranslateTriangles(triangles, dx, dy, dz), nil
+}
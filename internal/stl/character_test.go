@@ -0,0 +1,125 @@
+package stl
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+)
+
+func TestParseCharacterSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    CharacterOptions
+		wantErr bool
+	}{
+		{
+			name: "path only uses defaults",
+			spec: "mascot.stl",
+			want: CharacterOptions{Path: "mascot.stl", Scale: 0.7, Anchor: AnchorBackRight, ZOffset: -0.5},
+		},
+		{
+			name: "all settings",
+			spec: "mascot.stl, height=20, rotate=90, anchor=front-left, z=0, scale=2",
+			want: CharacterOptions{Path: "mascot.stl", Scale: 2, Height: 20, Rotation: 90, Anchor: AnchorFrontLeft},
+		},
+		{name: "empty path", spec: ",scale=1", wantErr: true},
+		{name: "missing value", spec: "mascot.stl,scale", wantErr: true},
+		{name: "invalid number", spec: "mascot.stl,scale=big", wantErr: true},
+		{name: "unknown setting", spec: "mascot.stl,color=red", wantErr: true},
+		{name: "unknown anchor", spec: "mascot.stl,anchor=roof", wantErr: true},
+		{name: "non-positive scale", spec: "mascot.stl,scale=0", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCharacterSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCharacterSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("ParseCharacterSpec() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCharacter(t *testing.T) {
+	// A 10x20x5 box stands in for a character model
+	cube, err := geometry.CreateCube(0, 0, 0, 10, 20, 5)
+	if err != nil {
+		t.Fatalf("CreateCube() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "character.stl")
+	if err := WriteSTLBinary(path, cube); err != nil {
+		t.Fatalf("WriteSTLBinary() error = %v", err)
+	}
+
+	dims, err := calculateDimensions(1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+
+	t.Run("default placement at back right", func(t *testing.T) {
+		opts, err := ParseCharacterSpec(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		triangles, err := loadCharacter(opts, dims)
+		if err != nil {
+			t.Fatalf("loadCharacter() error = %v", err)
+		}
+		box := geometry.CalculateBoundingBox(triangles)
+		assertClose(t, "maxX", box.Max.X, dims.innerWidth-characterMarginX)
+		assertClose(t, "maxY", box.Max.Y, dims.innerDepth-characterMarginY)
+		assertClose(t, "minZ", box.Min.Z, -0.5)
+		assertClose(t, "width", box.Size().X, 7)
+		assertClose(t, "depth", box.Size().Y, 14)
+		assertClose(t, "height", box.Size().Z, 3.5)
+	})
+
+	t.Run("target height and rotation", func(t *testing.T) {
+		opts, err := ParseCharacterSpec(path + ",height=10,rotate=90,anchor=front-left,z=0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		triangles, err := loadCharacter(opts, dims)
+		if err != nil {
+			t.Fatalf("loadCharacter() error = %v", err)
+		}
+		box := geometry.CalculateBoundingBox(triangles)
+		assertClose(t, "minX", box.Min.X, characterMarginX)
+		assertClose(t, "minY", box.Min.Y, characterMarginY)
+		assertClose(t, "minZ", box.Min.Z, 0)
+		assertClose(t, "height", box.Size().Z, 10)
+		// Rotated by 90 degrees, the long side now runs along X
+		assertClose(t, "width", box.Size().X, 40)
+		assertClose(t, "depth", box.Size().Y, 20)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		opts := CharacterOptions{Path: filepath.Join(t.TempDir(), "missing.stl"), Scale: 1}
+		if _, err := loadCharacter(opts, dims); err == nil {
+			t.Error("loadCharacter() expected error for missing file")
+		}
+	})
+}
+
+func TestGenerateSTLRangeWithMissingCharacter(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "test.stl")
+	opts := ModelOptions{Characters: []CharacterOptions{{Path: "does-not-exist.stl", Scale: 1}}}
+	err := GenerateSTL(context.Background(), createTestContributions(), outputPath, "testuser", 2023, opts)
+	if err == nil {
+		t.Error("GenerateSTL(context.Background(), ) expected error for unreadable character model")
+	}
+}
+
+func assertClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-4 {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
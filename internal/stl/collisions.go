@@ -0,0 +1,70 @@
+package stl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+)
+
+// obstacle is a named region of the model that text should not overlap.
+type obstacle struct {
+	name   string
+	bounds geometry.BoundingBox
+}
+
+// findTextCollisions returns a description of every text block that overlaps an
+// obstacle or another text block. Obstacles with the same name are reported once
+// per text block with a count.
+func findTextCollisions(texts []geometry.PlacedText, obstacles []obstacle) []string {
+	var collisions []string
+	for i, text := range texts {
+		if text.Bounds.IsEmpty() {
+			continue
+		}
+
+		counts := make(map[string]int)
+		for _, o := range obstacles {
+			if !o.bounds.IsEmpty() && text.Bounds.Intersects(o.bounds) {
+				counts[o.name]++
+			}
+		}
+		for _, other := range texts[i+1:] {
+			if !other.Bounds.IsEmpty() && text.Bounds.Intersects(other.Bounds) {
+				counts["text in slot "+other.Slot.Name]++
+			}
+		}
+
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			description := name
+			if counts[name] > 1 {
+				description = fmt.Sprintf("%d %s", counts[name], name)
+			}
+			collisions = append(collisions, fmt.Sprintf("text %q in slot %s overlaps %s", firstLine(text.Block.Text), text.Slot.Name, description))
+		}
+	}
+	return collisions
+}
+
+// logTextCollisions warns about text that overlaps other parts of the model.
+func logTextCollisions(texts []geometry.PlacedText, obstacles []obstacle) error {
+	for _, collision := range findTextCollisions(texts, obstacles) {
+		if err := logger.GetLogger().Warning("Layout: %s. Choose another slot or a smaller size.", collision); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// firstLine returns the first line of multi-line text for messages.
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return line
+}
@@ -0,0 +1,151 @@
+package stl
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestFindTextCollisions(t *testing.T) {
+	box := func(minX, maxX float64) geometry.BoundingBox {
+		return geometry.BoundingBox{Min: types.Point3D{X: minX, Y: 0, Z: 0}, Max: types.Point3D{X: maxX, Y: 1, Z: 1}}
+	}
+	text := func(slot string, minX, maxX float64) geometry.PlacedText {
+		return geometry.PlacedText{
+			Block:  geometry.TextBlock{Slot: slot, Text: "hello\nworld"},
+			Slot:   geometry.Slot{Name: slot},
+			Bounds: box(minX, maxX),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		texts     []geometry.PlacedText
+		obstacles []obstacle
+		want      []string
+	}{
+		{
+			name:  "clear",
+			texts: []geometry.PlacedText{text("back", 0, 10)},
+			obstacles: []obstacle{
+				{name: "logo", bounds: box(10, 20)}, // Touching only
+			},
+		},
+		{
+			name:  "columns are counted",
+			texts: []geometry.PlacedText{text("top-back", 0, 10)},
+			obstacles: []obstacle{
+				{name: "contribution columns", bounds: box(1, 2)},
+				{name: "contribution columns", bounds: box(3, 4)},
+				{name: "logo", bounds: box(9, 12)},
+				{name: "character mascot.stl", bounds: geometry.BoundingBox{Min: types.Point3D{X: 1}, Max: types.Point3D{X: -1}}}, // Empty
+			},
+			want: []string{
+				`text "hello" in slot top-back overlaps 2 contribution columns`,
+				`text "hello" in slot top-back overlaps logo`,
+			},
+		},
+		{
+			name:  "text blocks overlap each other",
+			texts: []geometry.PlacedText{text("front-left", 0, 10), text("front-center", 5, 15)},
+			want:  []string{`text "hello" in slot front-left overlaps text in slot front-center`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findTextCollisions(tt.texts, tt.obstacles); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findTextCollisions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDefaultLayoutAvoidsColumns verifies the top text no longer collides with a full contribution grid.
+func TestDefaultLayoutAvoidsColumns(t *testing.T) {
+	contributions := createTestContributions()
+	for i := range contributions {
+		for j := range contributions[i] {
+			contributions[i][j].ContributionCount = 1
+		}
+	}
+	dims, err := calculateDimensions(1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+
+	blocks := textBlocks(2024, 2024, ModelOptions{TopText: "A fairly long top text for the band"})
+	placed, err := geometry.LayoutText(blocks, dims.innerWidth, dims.innerDepth, geometry.BaseHeight, geometry.TextFonts{})
+	if err != nil {
+		t.Fatalf("LayoutText() error = %v", err)
+	}
+
+	var obstacles []obstacle
+	for _, bounds := range geometry.ContributionColumnBounds(contributions, 0, 1) {
+		obstacles = append(obstacles, obstacle{name: "contribution columns", bounds: bounds})
+	}
+	if collisions := findTextCollisions(placed, obstacles); len(collisions) != 0 {
+		t.Errorf("default layout collides: %s", strings.Join(collisions, "; "))
+	}
+
+	// Moving the top text into the contribution grid area is detected
+	placed = append(placed, geometry.PlacedText{
+		Block:  geometry.TextBlock{Slot: "top-center", Text: "x"},
+		Slot:   geometry.Slot{Name: "top-center"},
+		Bounds: geometry.BoundingBox{Min: types.Point3D{X: 50, Y: 8, Z: 0}, Max: types.Point3D{X: 60, Y: 12, Z: 1}},
+	})
+	if collisions := findTextCollisions(placed, obstacles); len(collisions) != 1 {
+		t.Errorf("expected one collision, got %q", collisions)
+	}
+}
+
+func TestTextBlocks(t *testing.T) {
+	tests := []struct {
+		name      string
+		startYear int
+		endYear   int
+		opts      ModelOptions
+		want      []geometry.TextBlock
+	}{
+		{
+			name:      "year range",
+			startYear: 2021,
+			endYear:   2024,
+			want:      []geometry.TextBlock{{Slot: geometry.SlotFrontRight, Text: "2021-24"}},
+		},
+		{
+			name:      "right and top text",
+			startYear: 2024,
+			endYear:   2024,
+			opts:      ModelOptions{RightText: "right", TopText: "top"},
+			want: []geometry.TextBlock{
+				{Slot: geometry.SlotFrontRight, Text: "right"},
+				{Slot: geometry.SlotTopBand, Text: "top"},
+			},
+		},
+		{
+			name:      "text blocks replace and extend defaults",
+			startYear: 2024,
+			endYear:   2024,
+			opts: ModelOptions{Texts: []geometry.TextBlock{
+				{Slot: geometry.SlotFrontRight, Text: "mine", Align: geometry.AlignCenter},
+				{Slot: geometry.SlotBack, Text: "back"},
+			}},
+			want: []geometry.TextBlock{
+				{Slot: geometry.SlotFrontRight, Text: "mine", Align: geometry.AlignCenter},
+				{Slot: geometry.SlotBack, Text: "back"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := textBlocks(tt.startYear, tt.endYear, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("textBlocks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
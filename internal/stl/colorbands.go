@@ -0,0 +1,86 @@
+package stl
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+)
+
+// MaxColorBands is the largest number of intensity bands a plan can have.
+const MaxColorBands = 10
+
+// ColorChange is a filament change at the height where an intensity band begins.
+type ColorChange struct {
+	Band             int     // Band that starts here, from 1 (the lowest column band)
+	MinContributions int     // Fewest contributions of a day whose column reaches this band
+	ModelZ           float64 // Height above the top of the base (mm)
+	PrintZ           float64 // Height above the print bed (mm)
+	Layer            int     // First layer whose top is above PrintZ, counted from 1 with equal layers
+}
+
+// PlanColorChanges returns the filament changes that colour the model by height:
+// the first at the top of the base, where the columns begin, and one where each
+// further band of contribution counts begins. Bands split the range up to
+// maxContrib into equal parts, like the levels of the GitHub contribution graph;
+// band k+1 begins at the column height of the fewest contributions above k parts.
+// Bands that would begin at the same height are merged.
+func PlanColorChanges(maxContrib, bands int, profile PrintProfile) ([]ColorChange, error) {
+	if bands < 1 || bands > MaxColorBands {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("color band count must be between 1 and %d", MaxColorBands), nil)
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	layerHeight := profile.withDefaults().LayerHeight
+
+	changes := []ColorChange{{Band: 1, MinContributions: 1}}
+	for k := 1; k < bands; k++ {
+		previous := changes[len(changes)-1]
+		minimum := maxContrib*k/bands + 1
+		if minimum <= previous.MinContributions || minimum > maxContrib {
+			continue
+		}
+		z := geometry.NormalizeContribution(minimum, maxContrib)
+		if z <= previous.ModelZ+layerHeight/2 {
+			continue
+		}
+		changes = append(changes, ColorChange{Band: len(changes) + 1, MinContributions: minimum, ModelZ: z})
+	}
+
+	for i := range changes {
+		changes[i].PrintZ = changes[i].ModelZ + geometry.BaseHeight
+		// Layers are numbered by their top, so the first layer above PrintZ is the next one
+		changes[i].Layer = int(math.Floor(changes[i].PrintZ/layerHeight+1e-6)) + 1
+	}
+	return changes, nil
+}
+
+// ColorChangeHeights returns the print heights of the changes, formatted for
+// the --heights flag of the filament-swap command.
+func ColorChangeHeights(changes []ColorChange) string {
+	heights := make([]string, len(changes))
+	for i, change := range changes {
+		heights[i] = strconv.FormatFloat(change.PrintZ, 'f', 2, 64)
+	}
+	return strings.Join(heights, ",")
+}
+
+// FormatColorChangePlan returns a human-readable filament-change plan with the
+// post-processing command that inserts the changes into sliced G-code.
+func FormatColorChangePlan(changes []ColorChange, profile PrintProfile) string {
+	p := profile.withDefaults()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Filament changes (%.2f mm layers):\n", p.LayerHeight)
+	for _, change := range changes {
+		fmt.Fprintf(&b, "  Band %d: layer %d, Z %.2f mm (%.2f mm above the base, %d+ contributions)\n",
+			change.Band, change.Layer, change.PrintZ, change.ModelZ, change.MinContributions)
+	}
+	fmt.Fprintf(&b, "Post-processing script for the slicer, or run on the sliced file:\n")
+	fmt.Fprintf(&b, "  gh skyline filament-swap --heights %s", ColorChangeHeights(changes))
+	return b.String()
+}
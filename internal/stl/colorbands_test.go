@@ -0,0 +1,77 @@
+package stl
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+)
+
+func TestPlanColorChanges(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxContrib  int
+		bands       int
+		profile     PrintProfile
+		wantMinimum []int
+		wantErr     bool
+	}{
+		{name: "GitHub levels", maxContrib: 20, bands: 4, wantMinimum: []int{1, 6, 11, 16}},
+		{name: "single band", maxContrib: 20, bands: 1, wantMinimum: []int{1}},
+		{name: "fewer contributions than bands", maxContrib: 2, bands: 4, wantMinimum: []int{1, 2}},
+		{name: "no contributions", maxContrib: 0, bands: 4, wantMinimum: []int{1}},
+		{name: "too many bands", maxContrib: 20, bands: MaxColorBands + 1, wantErr: true},
+		{name: "no bands", maxContrib: 20, bands: 0, wantErr: true},
+		{name: "invalid profile", maxContrib: 20, bands: 4, profile: PrintProfile{LayerHeight: -1, FilamentDiameter: 1.75, Density: 1, LineWidth: 0.4, Speed: 1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, err := PlanColorChanges(tt.maxContrib, tt.bands, tt.profile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("PlanColorChanges() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(changes) != len(tt.wantMinimum) {
+				t.Fatalf("got %d changes %+v, want %d", len(changes), changes, len(tt.wantMinimum))
+			}
+			for i, change := range changes {
+				if change.Band != i+1 || change.MinContributions != tt.wantMinimum[i] {
+					t.Errorf("change %d = %+v, want band %d from %d contributions", i, change, i+1, tt.wantMinimum[i])
+				}
+				wantZ := 0.0
+				if i > 0 {
+					wantZ = geometry.NormalizeContribution(change.MinContributions, tt.maxContrib)
+				}
+				if math.Abs(change.ModelZ-wantZ) > 1e-9 || math.Abs(change.PrintZ-wantZ-geometry.BaseHeight) > 1e-9 {
+					t.Errorf("change %d at model Z %.3f, print Z %.3f, want %.3f above the base", i, change.ModelZ, change.PrintZ, wantZ)
+				}
+				// The change's layer is the first one whose top is above PrintZ
+				layerHeight := DefaultPrintProfile().LayerHeight
+				if top := float64(change.Layer) * layerHeight; top <= change.PrintZ || top > change.PrintZ+layerHeight+1e-6 {
+					t.Errorf("change %d in layer %d (top %.2f), want the first layer above %.2f", i, change.Layer, top, change.PrintZ)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatColorChangePlan(t *testing.T) {
+	changes, err := PlanColorChanges(20, 4, PrintProfile{})
+	if err != nil {
+		t.Fatalf("PlanColorChanges() error = %v", err)
+	}
+	if changes[0].Layer != 51 {
+		t.Errorf("first change in layer %d, want 51 for a 10 mm base and 0.2 mm layers", changes[0].Layer)
+	}
+
+	plan := FormatColorChangePlan(changes, PrintProfile{})
+	for _, want := range []string{"Band 1: layer 51, Z 10.00 mm", "Band 4:", "filament-swap --heights 10.00," + ColorChangeHeights(changes)[6:]} {
+		if !strings.Contains(plan, want) {
+			t.Errorf("plan does not contain %q:\n%s", want, plan)
+		}
+	}
+}
@@ -2,12 +2,14 @@ package stl
 
 import (
 	"bufio"
+	"context"
 	"fmt"
-	"math"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/github/gh-skyline/internal/errors"
 	"github.com/github/gh-skyline/internal/logger"
@@ -15,23 +17,52 @@ import (
 	"github.com/github/gh-skyline/internal/types"
 )
 
+// ModelOptions holds the optional settings that customise the generated model.
+// The zero value produces a model with the default year text and no logo.
+type ModelOptions struct {
+	TopText    string                 // Text embossed on the top face of the base
+	RightText  string                 // Text embossed on the right of the front face (defaults to the year range)
+	LogoPath   string                 // Image embossed on the left of the front face (optional)
+	LogoRelief geometry.ReliefOptions // How the logo image is converted into relief
+	Fonts      geometry.TextFonts     // Fonts for the front, right and top texts
+	Texts      []geometry.TextBlock   // Extra text blocks; each replaces the default text in its slot
+	RowLabels  []string               // Text embossed beside each row of the grid, from the back row (optional)
+	Characters []CharacterOptions     // External models merged onto the top of the base
+	QRCode     geometry.QRCodeOptions // QR code embossed behind the contribution grid (optional)
+	Markers    []geometry.DateMarker  // Dates whose columns are highlighted
+	Streak     geometry.StreakOptions // Contribution streak highlighted with a rail (optional)
+	Mold       geometry.MoldOptions   // Write a negative mold of the model for casting instead
+	Base       geometry.BaseOptions   // Shape of the base plate and its optional frame
+	Breakdown  bool                   // Stack each column by contribution type, one object per type
+	SkipCheck  bool                   // Skip the mesh validation run after generation
+	Profile    PrintProfile           // Material and printer settings for the printing estimate
+	// ColorBands is the number of intensity bands in the filament-change plan that
+	// colours the model by height on a single extruder. Zero means no plan.
+	ColorBands int
+	// MaxTriangles caps the model's triangle count by decimating text, logo and characters.
+	// Zero means no limit. The base, contribution columns, markers, streak rail and QR code are never decimated.
+	MaxTriangles int
+}
+
 // GenerateSTL creates a 3D model from GitHub contribution data and writes it to an STL file.
 // It's a convenience wrapper around GenerateSTLRange for single year processing.
-func GenerateSTL(contributions [][]types.ContributionDay, outputPath, username string, year int, topText, rightText string) error {
+func GenerateSTL(ctx context.Context, contributions [][]types.ContributionDay, outputPath, username string, year int, opts ModelOptions) error {
 	// Wrap single year data in the format expected by GenerateSTLRange
 	contributionsRange := [][][]types.ContributionDay{contributions}
-	return GenerateSTLRange(contributionsRange, outputPath, username, year, year, topText, rightText)
+	return GenerateSTLRange(ctx, contributionsRange, outputPath, username, year, year, opts)
 }
 
 // GenerateSTLRange creates a 3D model from multiple years of GitHub contribution data.
 // It handles the complete process from data validation through geometry generation to file output.
 // Parameters:
+//   - ctx: cancels generation between stages; nothing is written once it is done
 //   - contributions: 3D slice of contribution data ([year][week][day])
 //   - outputPath: destination path for the STL file
 //   - username: GitHub username for the contribution data
 //   - startYear: first year in the range
 //   - endYear: last year in the range
-func GenerateSTLRange(contributions [][][]types.ContributionDay, outputPath, username string, startYear, endYear int, topText, rightText string) error {
+//   - opts: optional text and logo settings
+func GenerateSTLRange(ctx context.Context, contributions [][][]types.ContributionDay, outputPath, username string, startYear, endYear int, opts ModelOptions) error {
 	log := logger.GetLogger()
 	if err := log.Debug("Starting STL generation for user %s, years %d-%d", username, startYear, endYear); err != nil {
 		return errors.Wrap(err, "failed to log debug message")
@@ -40,6 +71,63 @@ func GenerateSTLRange(contributions [][][]types.ContributionDay, outputPath, use
 	if err := validateInput(contributions[0], outputPath, username); err != nil {
 		return errors.Wrap(err, "input validation failed")
 	}
+	if err := opts.LogoRelief.Validate(); err != nil {
+		return errors.Wrap(err, "invalid logo relief options")
+	}
+	if err := opts.Profile.Validate(); err != nil {
+		return errors.Wrap(err, "invalid print profile")
+	}
+	if err := opts.Fonts.Validate(); err != nil {
+		return errors.Wrap(err, "invalid font options")
+	}
+	for _, character := range opts.Characters {
+		if err := character.Validate(); err != nil {
+			return errors.Wrap(err, "invalid character options")
+		}
+	}
+	for _, text := range opts.Texts {
+		if err := text.Validate(); err != nil {
+			return errors.Wrap(err, "invalid text options")
+		}
+	}
+	if err := opts.QRCode.Validate(); err != nil {
+		return errors.Wrap(err, "invalid QR code options")
+	}
+	for _, marker := range opts.Markers {
+		if err := marker.Validate(); err != nil {
+			return errors.Wrap(err, "invalid date marker")
+		}
+	}
+	if err := opts.Streak.Validate(); err != nil {
+		return errors.Wrap(err, "invalid streak options")
+	}
+	if err := opts.Base.Validate(); err != nil {
+		return errors.Wrap(err, "invalid base options")
+	}
+	if err := opts.Mold.Validate(); err != nil {
+		return errors.Wrap(err, "invalid mold options")
+	}
+	if opts.Mold.Enabled && !opts.Base.IsBox() {
+		return errors.New(errors.ValidationError, "a mold can only be made of the plain box base without a frame", nil)
+	}
+	if opts.QRCode.Enabled && !opts.Base.HasSideFaces() {
+		return errors.New(errors.ValidationError, "the QR code needs the flat back edge of the base; it cannot be used with an oval base", nil)
+	}
+	if opts.Breakdown && opts.ColorBands > 0 {
+		return errors.New(errors.ValidationError, "a filament-change plan colours by height; it cannot be used with the contribution type breakdown", nil)
+	}
+	if opts.Mold.Enabled && opts.ColorBands > 0 {
+		return errors.New(errors.ValidationError, "a filament-change plan cannot be made for a mold", nil)
+	}
+	if opts.ColorBands < 0 || opts.ColorBands > MaxColorBands {
+		return errors.New(errors.ValidationError, fmt.Sprintf("color band count must be between 0 (no plan) and %d", MaxColorBands), nil)
+	}
+	if opts.MaxTriangles < 0 {
+		return errors.New(errors.ValidationError, "maximum triangle count cannot be negative", nil)
+	}
+	if len(opts.RowLabels) > len(contributions) {
+		return errors.New(errors.ValidationError, fmt.Sprintf("%d row labels given for %d rows", len(opts.RowLabels), len(contributions)), nil)
+	}
 
 	dimensions, err := calculateDimensions(len(contributions))
 	if err != nil {
@@ -49,69 +137,129 @@ func GenerateSTLRange(contributions [][][]types.ContributionDay, outputPath, use
 	// Find global max contribution across all years
 	maxContribution := findMaxContributionsAcrossYears(contributions)
 
-	modelTriangles, err := generateModelGeometry(contributions, dimensions, maxContribution, username, startYear, endYear, topText, rightText)
+	generateOpts := opts
+	if opts.Mold.Enabled && opts.MaxTriangles > 0 {
+		// Decimated text no longer consists of boxes the mold can subtract
+		if err := log.Warning("Ignoring the triangle budget for the mold"); err != nil {
+			return errors.Wrap(err, "failed to log warning message")
+		}
+		generateOpts.MaxTriangles = 0
+	}
+	objects, err := generateModelObjects(ctx, contributions, dimensions, maxContribution, username, startYear, endYear, generateOpts)
 	if err != nil {
 		return errors.Wrap(err, "failed to generate geometry")
 	}
+	if opts.Mold.Enabled {
+		if objects, err = generateMold(objects, opts.Mold); err != nil {
+			return errors.Wrap(err, "failed to generate mold")
+		}
+	}
+	if err := checkCancelled(ctx); err != nil {
+		return err
+	}
+	modelTriangles := mergeObjects(objects)
 
-	// Read and merge character.stl if it exists
-	var characterTriangles []types.Triangle
-	var readErr error
-	isBinary := true
-	// 1. 80바이트 읽어서 solid로 시작하는지 확인
-	if f, err := os.Open("character.stl"); err == nil {
-		header := make([]byte, 80)
-		_, herr := f.Read(header)
-		f.Close()
-		if herr == nil && strings.HasPrefix(string(header), "solid") {
-			isBinary = false
+	if err := log.Info("Model generation complete: %d total triangles", len(modelTriangles)); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+	if err := writeModel(outputPath, objects, modelTriangles); err != nil {
+		return err
+	}
+
+	if !opts.SkipCheck {
+		if err := checkCancelled(ctx); err != nil {
+			return err
+		}
+		if err := logMeshReport(geometry.ValidateMesh(modelTriangles)); err != nil {
+			return errors.Wrap(err, "failed to log mesh report")
 		}
 	}
-	if isBinary {
-		characterTriangles, readErr = ReadSTLBinary("character.stl")
-	} else {
-		characterTriangles, readErr = ReadASCIISTL("character.stl")
-	}
-	if readErr == nil && len(characterTriangles) > 0 {
-		// 1. 70%로 스케일
-		characterTriangles = scaleTriangles(characterTriangles, 0.7)
-		// 3. bounding box 계산
-		minX, minY, minZ, maxX, maxY, _ := calcBoundingBox(characterTriangles)
-		charWidth := maxX - minX
-		charDepth := maxY - minY
-		// STL 바닥의 오른쪽 위에 올리기
-		dx := dimensions.innerWidth - charWidth - minX - 10
-		offset := 3.0
-		dy := dimensions.innerDepth - charDepth - minY - offset
-		dz := 0 - minZ - 0.5
-		characterTriangles = translateTriangles(characterTriangles, dx, dy, dz)
-		modelTriangles = append(modelTriangles, characterTriangles...)
-		if err := log.Info("Merged character.stl with %d triangles (right-top, scaled)", len(characterTriangles)); err != nil {
+
+	if err := log.Info("Model summary:\n%s", ComputeMeshStats(modelTriangles).Summary(opts.Profile)); err != nil {
+		return errors.Wrap(err, "failed to log info message")
+	}
+
+	if opts.ColorBands > 0 {
+		changes, err := PlanColorChanges(maxContribution, opts.ColorBands, opts.Profile)
+		if err != nil {
+			return errors.Wrap(err, "failed to plan filament changes")
+		}
+		if err := log.Info("%s", FormatColorChangePlan(changes, opts.Profile)); err != nil {
 			return errors.Wrap(err, "failed to log info message")
 		}
-	} else {
-		if err := log.Debug("No character.stl found or error reading it: %v", readErr); err != nil {
+	}
+	return nil
+}
+
+// checkCancelled returns an error wrapping the context's error once it is done.
+func checkCancelled(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return errors.New(errors.STLError, "model generation cancelled", err)
+	}
+	return nil
+}
+
+// writeModel writes the model as 3MF when outputPath ends in .3mf, keeping each
+// object separate, and as a single STL mesh otherwise.
+func writeModel(outputPath string, objects []Object, triangles []types.Triangle) error {
+	log := logger.GetLogger()
+	if strings.EqualFold(filepath.Ext(outputPath), ".3mf") {
+		if err := log.Debug("Writing 3MF file with %d objects to: %s", len(objects), outputPath); err != nil {
 			return errors.Wrap(err, "failed to log debug message")
 		}
+		if err := Write3MF(outputPath, objects); err != nil {
+			return errors.Wrap(err, "failed to write 3MF file")
+		}
+		if err := log.Info("3MF file written successfully to: %s", outputPath); err != nil {
+			return errors.Wrap(err, "failed to log info message")
+		}
+		return nil
 	}
 
-	if err := log.Info("Model generation complete: %d total triangles", len(modelTriangles)); err != nil {
-		return errors.Wrap(err, "failed to log info message")
-	}
 	if err := log.Debug("Writing STL file to: %s", outputPath); err != nil {
 		return errors.Wrap(err, "failed to log debug message")
 	}
-
-	if err := WriteSTLBinary(outputPath, modelTriangles); err != nil {
+	if err := WriteSTLBinary(outputPath, triangles); err != nil {
 		return errors.Wrap(err, "failed to write STL file")
 	}
-
 	if err := log.Info("STL file written successfully to: %s", outputPath); err != nil {
 		return errors.Wrap(err, "failed to log info message")
 	}
+	if len(objects) > 1 {
+		return log.Info("Merged %d objects into one mesh; write a .3mf file to keep them separate for multi-colour printing", len(objects))
+	}
 	return nil
 }
 
+// generateMold replaces the model objects with a single negative mold of all of them.
+func generateMold(objects []Object, opts geometry.MoldOptions) ([]Object, error) {
+	log := logger.GetLogger()
+	mold, err := geometry.CreateMold(mergeObjects(objects), opts)
+	if err != nil {
+		return nil, err
+	}
+	if mold.Skipped > 0 {
+		if err := log.Warning("Left %d triangles of characters and cap markers out of the mold; only box shapes can be subtracted", mold.Skipped); err != nil {
+			return nil, err
+		}
+	}
+	if err := log.Info("Created mold with %d triangles", len(mold.Triangles)); err != nil {
+		return nil, err
+	}
+	return []Object{{Name: "mold", Triangles: mold.Triangles}}, nil
+}
+
+// logMeshReport logs the outcome of the post-generation mesh check.
+// Defects are reported as warnings; they do not fail generation.
+func logMeshReport(report geometry.MeshReport) error {
+	log := logger.GetLogger()
+	defects := report.Defects()
+	if len(defects) == 0 {
+		return log.Info("Mesh check passed: %d triangles in %d shells", report.Triangles, report.Shells)
+	}
+	return log.Warning("Mesh check found %s (run 'skyline validate' for details)", strings.Join(defects, ", "))
+}
+
 // ASCII STL 파서
 func ReadASCIISTL(filename string) ([]types.Triangle, error) {
 	file, err := os.Open(filename)
@@ -230,37 +378,98 @@ func findMaxContributionsAcrossYears(contributionsPerYear [][][]types.Contributi
 type geometryResult struct {
 	triangles []types.Triangle
 	err       error
+	texts     []geometry.PlacedText // Laid out text blocks, checked for collisions
+	obstacles []obstacle            // Regions text must not overlap
+	objects   []Object              // Parts kept apart from the main model, such as date markers
+}
+
+// generateModelGeometry returns the triangles of all model components as one mesh.
+func generateModelGeometry(ctx context.Context, contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, maxContrib int, username string, startYear, endYear int, opts ModelOptions) ([]types.Triangle, error) {
+	objects, err := generateModelObjects(ctx, contributionsPerYear, dims, maxContrib, username, startYear, endYear, opts)
+	if err != nil {
+		return nil, err
+	}
+	return mergeObjects(objects), nil
 }
 
-// generateModelGeometry orchestrates the concurrent generation of all model components.
-// It manages four parallel processes for generating the base, columns, text, and logo.
-func generateModelGeometry(contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, maxContrib int, username string, startYear, endYear int, topText, rightText string) ([]types.Triangle, error) {
+// generateModelObjects orchestrates the concurrent generation of all model components.
+// It manages parallel processes for generating the base, columns, text, logo and characters,
+// and decimates the detailed components when the model exceeds the triangle budget.
+// The first object is the skyline itself, followed by one object per date marker
+// and the streak rail. Generation stops with an error as soon as ctx is done.
+func generateModelObjects(ctx context.Context, contributionsPerYear [][][]types.ContributionDay, dims modelDimensions, maxContrib int, username string, startYear, endYear int, opts ModelOptions) ([]Object, error) {
 	if len(contributionsPerYear) == 0 {
 		return nil, errors.New(errors.ValidationError, "contributions data cannot be empty", nil)
 	}
 
+	// Buffered so the goroutines can finish when generation is cancelled and their results are not read
 	channels := map[string]chan geometryResult{
-		"base":    make(chan geometryResult),
-		"columns": make(chan geometryResult),
-		"text":    make(chan geometryResult),
-		"image":   make(chan geometryResult),
+		"base":       make(chan geometryResult, 1),
+		"columns":    make(chan geometryResult, 1),
+		"text":       make(chan geometryResult, 1),
+		"image":      make(chan geometryResult, 1),
+		"characters": make(chan geometryResult, 1),
+		"qrcode":     make(chan geometryResult, 1),
+		"streak":     make(chan geometryResult, 1),
+	}
+
+	streak, err := findStreak(contributionsPerYear, opts.Streak)
+	if err != nil {
+		return nil, err
+	}
+	if streak.Length > 0 {
+		// The streak length goes first so a --text block in the same slot replaces it
+		opts.Texts = append([]geometry.TextBlock{opts.Streak.TextBlock(streak)}, opts.Texts...)
+	}
+
+	blocks := textBlocks(startYear, endYear, opts)
+	logoPath := opts.LogoPath
+	if !opts.Base.HasSideFaces() {
+		if blocks, err = topFaceBlocks(blocks, logoPath != ""); err != nil {
+			return nil, err
+		}
+		logoPath = ""
 	}
 
 	var wg sync.WaitGroup
 	wg.Add(len(channels))
 
-	go generateBase(dims, channels["base"], &wg)
-	go generateColumnsForYearRange(contributionsPerYear, maxContrib, channels["columns"], &wg)
-	go generateText("", startYear, endYear, dims, channels["text"], &wg, topText, rightText)
-	go generateLogoWithCustomPath(dims, channels["image"], &wg, "logo.png")
-
-	modelTriangles := make([]types.Triangle, 0, estimateTriangleCount(contributionsPerYear[0])*len(contributionsPerYear))
-	for _, name := range []string{"base", "image", "columns", "text"} {
-		result := <-channels[name]
+	go generateBase(dims, opts.Base, channels["base"], &wg)
+	go generateColumnsForYearRange(ctx, contributionsPerYear, maxContrib, opts.Markers, opts.Breakdown, channels["columns"], &wg)
+	go generateText(blocks, dims, channels["text"], &wg, opts.Fonts)
+	go generateLogoWithCustomPath(dims, channels["image"], &wg, logoPath, opts.LogoRelief)
+	go generateCharacters(opts.Characters, dims, channels["characters"], &wg)
+	go generateQRCode(opts.QRCode, dims, channels["qrcode"], &wg)
+	go generateStreakRail(contributionsPerYear, maxContrib, streak, opts.Streak, channels["streak"], &wg)
+
+	// Base, columns, markers, the streak rail and the QR code must stay exact (a decimated QR code
+	// no longer scans); everything else is detail
+	var structure, detail []types.Triangle
+	var texts []geometry.PlacedText
+	var obstacles []obstacle
+	var extra []Object
+	exact := 0
+	for _, name := range []string{"base", "image", "columns", "text", "characters", "qrcode", "streak"} {
+		var result geometryResult
+		select {
+		case result = <-channels[name]:
+		case <-ctx.Done():
+			return nil, checkCancelled(ctx)
+		}
 		if result.err != nil {
 			return nil, errors.Wrap(result.err, fmt.Sprintf("failed to generate %s geometry", name))
 		}
-		modelTriangles = append(modelTriangles, result.triangles...)
+		texts = append(texts, result.texts...)
+		obstacles = append(obstacles, result.obstacles...)
+		extra = append(extra, result.objects...)
+		for _, object := range result.objects {
+			exact += len(object.Triangles)
+		}
+		if name == "base" || name == "columns" || name == "qrcode" {
+			structure = append(structure, result.triangles...)
+		} else {
+			detail = append(detail, result.triangles...)
+		}
 	}
 
 	wg.Wait()
@@ -268,12 +477,132 @@ func generateModelGeometry(contributionsPerYear [][][]types.ContributionDay, dim
 		close(ch)
 	}
 
-	return modelTriangles, nil
+	if err := logTextCollisions(texts, obstacles); err != nil {
+		return nil, err
+	}
+
+	exact += len(structure)
+	if opts.MaxTriangles > 0 && exact+len(detail) > opts.MaxTriangles {
+		var err error
+		if detail, err = decimateDetail(detail, opts.MaxTriangles-exact); err != nil {
+			return nil, err
+		}
+	}
+
+	modelTriangles := make([]types.Triangle, 0, len(structure)+len(detail))
+	modelTriangles = append(modelTriangles, structure...)
+	modelTriangles = append(modelTriangles, detail...)
+	return append([]Object{{Name: "skyline", Triangles: modelTriangles}}, extra...), nil
+}
+
+// decimateDetail reduces the detail geometry to fit the remaining triangle budget.
+func decimateDetail(detail []types.Triangle, budget int) ([]types.Triangle, error) {
+	log := logger.GetLogger()
+	if budget <= 0 {
+		if err := log.Warning("Base, columns, markers and QR code alone exceed the triangle budget. Dropping text, logo and characters."); err != nil {
+			return nil, err
+		}
+		return []types.Triangle{}, nil
+	}
+
+	decimated := geometry.Decimate(detail, budget)
+	if err := log.Info("Decimated text, logo and characters from %d to %d triangles", len(detail), len(decimated)); err != nil {
+		return nil, err
+	}
+	if len(decimated) > budget {
+		if err := log.Warning("Could not reduce detail geometry below %d triangles (got %d)", budget, len(decimated)); err != nil {
+			return nil, err
+		}
+	}
+	return decimated, nil
+}
+
+// generateCharacters loads and places the external character models.
+// Unlike the other components, a character that cannot be read fails generation,
+// since it was explicitly requested.
+func generateCharacters(characters []CharacterOptions, dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitGroup) {
+	defer wg.Done()
+	triangles := []types.Triangle{}
+	var obstacles []obstacle
+	for _, character := range characters {
+		characterTriangles, err := loadCharacter(character, dims)
+		if err != nil {
+			ch <- geometryResult{err: err}
+			return
+		}
+		if err := logger.GetLogger().Info("Merged %s with %d triangles (%s)", character.Path, len(characterTriangles), character.Anchor); err != nil {
+			ch <- geometryResult{err: err}
+			return
+		}
+		triangles = append(triangles, characterTriangles...)
+		obstacles = append(obstacles, obstacle{name: "character " + character.Path, bounds: geometry.CalculateBoundingBox(characterTriangles)})
+	}
+	ch <- geometryResult{triangles: triangles, obstacles: obstacles}
+}
+
+// findStreak returns the streak selected by opts across all years, or an empty
+// streak when the highlight is disabled or no such streak exists.
+func findStreak(contributionsPerYear [][][]types.ContributionDay, opts geometry.StreakOptions) (types.Streak, error) {
+	if opts.Kind == "" {
+		return types.Streak{}, nil
+	}
+	var weeks [][]types.ContributionDay
+	for _, year := range contributionsPerYear {
+		weeks = append(weeks, year...)
+	}
+
+	log := logger.GetLogger()
+	streak := opts.Find(weeks, time.Now())
+	if streak.Length == 0 {
+		return streak, log.Warning("No %s contribution streak found; skipping the streak highlight", opts.Kind)
+	}
+	return streak, log.Info("Highlighting %s streak: %d days from %s to %s", opts.Kind, streak.Length, streak.Start, streak.End)
+}
+
+// generateStreakRail creates the rail along the streak's columns as a separate
+// object, so multi-object formats can print it in its own colour.
+func generateStreakRail(contributionsPerYear [][][]types.ContributionDay, maxContrib int, streak types.Streak, opts geometry.StreakOptions, ch chan<- geometryResult, wg *sync.WaitGroup) {
+	defer wg.Done()
+	if streak.Length == 0 {
+		ch <- geometryResult{triangles: []types.Triangle{}}
+		return
+	}
+
+	var triangles []types.Triangle
+	for i := len(contributionsPerYear) - 1; i >= 0; i-- {
+		rail, err := geometry.CreateStreakRail(contributionsPerYear[i], len(contributionsPerYear)-1-i, maxContrib, streak)
+		if err != nil {
+			ch <- geometryResult{err: err}
+			return
+		}
+		triangles = append(triangles, rail...)
+	}
+	ch <- geometryResult{triangles: []types.Triangle{}, objects: []Object{{Name: geometry.StreakLabel(streak), Color: opts.RailColor(), Triangles: triangles}}}
+}
+
+// generateQRCode creates the embossed QR code. Like characters, a QR code that
+// cannot be created fails generation, since it was explicitly requested.
+func generateQRCode(opts geometry.QRCodeOptions, dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitGroup) {
+	defer wg.Done()
+	if !opts.Enabled {
+		ch <- geometryResult{triangles: []types.Triangle{}}
+		return
+	}
+	triangles, err := geometry.CreateQRCode(dims.innerWidth, dims.innerDepth, opts)
+	if err != nil {
+		ch <- geometryResult{err: err}
+		return
+	}
+	if err := logger.GetLogger().Info("Embossed QR code for %s", opts.Content); err != nil {
+		ch <- geometryResult{err: err}
+		return
+	}
+	ch <- geometryResult{triangles: triangles, obstacles: []obstacle{{name: "QR code", bounds: geometry.CalculateBoundingBox(triangles)}}}
 }
 
-func generateBase(dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitGroup) {
+func generateBase(dims modelDimensions, opts geometry.BaseOptions, ch chan<- geometryResult, wg *sync.WaitGroup) {
 	defer wg.Done()
-	baseTriangles, err := geometry.CreateCuboidBase(dims.innerWidth, dims.innerDepth)
+	base, err := geometry.CreateBase(dims.innerWidth, dims.innerDepth, opts)
 
 	if err != nil {
 		if logErr := logger.GetLogger().Warning("Failed to generate base geometry: %v. Continuing without base.", err); logErr != nil {
@@ -284,31 +613,86 @@ func generateBase(dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitG
 		return
 	}
 
-	ch <- geometryResult{triangles: baseTriangles}
+	var obstacles []obstacle
+	for _, bounds := range base.Frame {
+		obstacles = append(obstacles, obstacle{name: "frame", bounds: bounds})
+	}
+	for _, bounds := range base.Chamfer {
+		obstacles = append(obstacles, obstacle{name: "chamfered edge", bounds: bounds})
+	}
+	ch <- geometryResult{triangles: base.Triangles, obstacles: obstacles}
 }
 
-// generateText creates 3D text geometry for the model
-func generateText(username string, startYear int, endYear int, dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitGroup, topText, rightText string) {
-	defer wg.Done()
+// topFaceBlocks returns the text blocks on the top face, for a base without flat
+// sides, and warns about the text and logo left out.
+func topFaceBlocks(blocks []geometry.TextBlock, hasLogo bool) ([]geometry.TextBlock, error) {
+	var kept []geometry.TextBlock
+	var dropped []string
+	for _, block := range blocks {
+		face, err := geometry.SlotFace(block.Slot)
+		if err != nil {
+			return nil, err
+		}
+		if face == geometry.FaceTop {
+			kept = append(kept, block)
+		} else {
+			dropped = append(dropped, fmt.Sprintf("%q", firstLine(block.Text)))
+		}
+	}
+	if hasLogo {
+		dropped = append(dropped, "the logo")
+	}
+	if len(dropped) > 0 {
+		if err := logger.GetLogger().Warning("The oval base has no flat sides; leaving out %s. Use the top-band or top-back slot for text.", strings.Join(dropped, ", ")); err != nil {
+			return nil, err
+		}
+	}
+	return kept, nil
+}
+
+// textBlocks returns the text layout of the model: the default blocks for the year
+// range, right text, top text and row labels, with each of opts.Texts replacing the
+// block in the same slot or adding a new one.
+func textBlocks(startYear, endYear int, opts ModelOptions) []geometry.TextBlock {
 	var embossedRight string
-	if rightText != "" {
-		embossedRight = rightText
+	if opts.RightText != "" {
+		embossedRight = opts.RightText
 	} else if startYear == endYear {
 		embossedRight = fmt.Sprintf("%d", endYear)
 	} else {
 		embossedRight = fmt.Sprintf("%04d-%02d", startYear, endYear%100)
 	}
 
-	textTriangles, err := geometry.Create3DText(username, embossedRight, dims.innerWidth, geometry.BaseHeight, dims.innerDepth, topText)
-	if err != nil {
-		if logErr := logger.GetLogger().Warning("Failed to generate text geometry: %v. Continuing without text.", err); logErr != nil {
-			ch <- geometryResult{triangles: []types.Triangle{}, err: logErr}
-			return
+	blocks := geometry.DefaultTextBlocks("", embossedRight, opts.TopText)
+	blocks = append(blocks, geometry.RowLabelBlocks(opts.RowLabels)...)
+	for _, text := range opts.Texts {
+		replaced := false
+		for i := range blocks {
+			if blocks[i].Slot == text.Slot {
+				blocks[i], replaced = text, true
+			}
 		}
-		ch <- geometryResult{triangles: []types.Triangle{}}
+		if !replaced {
+			blocks = append(blocks, text)
+		}
+	}
+	return blocks
+}
+
+// generateText creates 3D text geometry for the model
+func generateText(blocks []geometry.TextBlock, dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitGroup, fonts geometry.TextFonts) {
+	defer wg.Done()
+	placed, err := geometry.LayoutText(blocks, dims.innerWidth, dims.innerDepth, geometry.BaseHeight, fonts)
+	if err != nil {
+		ch <- geometryResult{triangles: []types.Triangle{}, err: err}
 		return
 	}
-	ch <- geometryResult{triangles: textTriangles}
+
+	textTriangles := []types.Triangle{}
+	for _, text := range placed {
+		textTriangles = append(textTriangles, text.Triangles...)
+	}
+	ch <- geometryResult{triangles: textTriangles, texts: placed}
 }
 
 // generateLogo handles the generation of the GitHub logo geometry
@@ -324,7 +708,7 @@ func generateLogo(dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitG
 		ch <- geometryResult{triangles: []types.Triangle{}}
 		return
 	}
-	ch <- geometryResult{triangles: logoTriangles}
+	ch <- geometryResult{triangles: logoTriangles, obstacles: []obstacle{{name: "logo", bounds: geometry.CalculateBoundingBox(logoTriangles)}}}
 }
 
 func estimateTriangleCount(contributions [][]types.ContributionDay) int {
@@ -343,25 +727,66 @@ func estimateTriangleCount(contributions [][]types.ContributionDay) int {
 	return baseTrianglesCount + columnsTrianglesCount + textTrianglesEstimate
 }
 
-// generateColumnsForYearRange generates contribution columns for multiple years
-func generateColumnsForYearRange(contributionsPerYear [][][]types.ContributionDay, maxContrib int, ch chan<- geometryResult, wg *sync.WaitGroup) {
+// generateColumnsForYearRange generates contribution columns for multiple years.
+// Each date marker becomes a separate object holding its geometry from all years,
+// as does each contribution type when the columns are stacked.
+func generateColumnsForYearRange(ctx context.Context, contributionsPerYear [][][]types.ContributionDay, maxContrib int, markers []geometry.DateMarker, stacked bool, ch chan<- geometryResult, wg *sync.WaitGroup) {
 	defer wg.Done()
 	var yearTriangles []types.Triangle
+	var obstacles []obstacle
+	markerTriangles := make([][]types.Triangle, len(markers))
+	matched := make([]int, len(markers))
+	typeTriangles := make([][]types.Triangle, len(geometry.ContributionTypes))
+	createGeometry := geometry.CreateMarkedContributionGeometry
+	if stacked {
+		createGeometry = geometry.CreateStackedContributionGeometry
+	}
 
 	// Process years in reverse order so most recent year is at the front
 	for i := len(contributionsPerYear) - 1; i >= 0; i-- {
+		if err := checkCancelled(ctx); err != nil {
+			ch <- geometryResult{err: err}
+			return
+		}
 		yearOffset := len(contributionsPerYear) - 1 - i
-		triangles, err := geometry.CreateContributionGeometry(contributionsPerYear[i], yearOffset, maxContrib)
+		marked, err := createGeometry(contributionsPerYear[i], yearOffset, maxContrib, markers)
 		if err != nil {
 			if logErr := logger.GetLogger().Warning("Failed to generate column geometry for year %d: %v. Skipping year.", i, err); logErr != nil {
 				return
 			}
 			continue
 		}
-		yearTriangles = append(yearTriangles, triangles...)
+		yearTriangles = append(yearTriangles, marked.Columns...)
+		for m := range markers {
+			markerTriangles[m] = append(markerTriangles[m], marked.Markers[m]...)
+			matched[m] += marked.Matched[m]
+		}
+		for t := range marked.Types {
+			typeTriangles[t] = append(typeTriangles[t], marked.Types[t]...)
+		}
+		for _, bounds := range geometry.ContributionColumnBounds(contributionsPerYear[i], yearOffset, maxContrib) {
+			obstacles = append(obstacles, obstacle{name: "contribution columns", bounds: bounds})
+		}
+	}
+
+	var objects []Object
+	for t, contributionType := range geometry.ContributionTypes {
+		if len(typeTriangles[t]) > 0 {
+			objects = append(objects, Object{Name: contributionType.Name, Color: contributionType.Color, Triangles: typeTriangles[t]})
+		}
+	}
+	for m, marker := range markers {
+		if matched[m] == 0 {
+			if err := logger.GetLogger().Warning("Date marker %s matches no day in the model", marker.Name()); err != nil {
+				ch <- geometryResult{err: err}
+				return
+			}
+			continue
+		}
+		objects = append(objects, Object{Name: marker.Name(), Color: marker.Color, Triangles: markerTriangles[m]})
 	}
 
-	ch <- geometryResult{triangles: yearTriangles}
+	ch <- geometryResult{triangles: yearTriangles, obstacles: obstacles, objects: objects}
 }
 
 // CreateContributionGeometry generates geometry for a single year's worth of contributions
@@ -396,9 +821,13 @@ func CreateContributionGeometry(contributions [][]types.ContributionDay, yearInd
 }
 
 // 로고 이미지를 지정 경로로부터 relief로 생성하는 함수
-func generateLogoWithCustomPath(dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitGroup, logoPath string) {
+func generateLogoWithCustomPath(dims modelDimensions, ch chan<- geometryResult, wg *sync.WaitGroup, logoPath string, relief geometry.ReliefOptions) {
 	defer wg.Done()
-	logoTriangles, err := geometry.GenerateImageGeometryWithPath(logoPath, dims.innerWidth, geometry.BaseHeight)
+	if logoPath == "" {
+		ch <- geometryResult{triangles: []types.Triangle{}}
+		return
+	}
+	logoTriangles, err := geometry.GenerateImageGeometryWithPath(logoPath, dims.innerWidth, geometry.BaseHeight, relief)
 	if err != nil {
 		if logErr := logger.GetLogger().Warning("Failed to generate logo geometry: %v. Continuing without logo.", err); logErr != nil {
 			ch <- geometryResult{triangles: []types.Triangle{}, err: logErr}
@@ -407,62 +836,5 @@ func generateLogoWithCustomPath(dims modelDimensions, ch chan<- geometryResult,
 		ch <- geometryResult{triangles: []types.Triangle{}}
 		return
 	}
-	ch <- geometryResult{triangles: logoTriangles}
-}
-
-// bounding box 계산 함수
-func calcBoundingBox(triangles []types.Triangle) (minX, minY, minZ, maxX, maxY, maxZ float64) {
-	minX, minY, minZ = math.MaxFloat64, math.MaxFloat64, math.MaxFloat64
-	maxX, maxY, maxZ = -math.MaxFloat64, -math.MaxFloat64, -math.MaxFloat64
-	for _, t := range triangles {
-		for _, v := range []types.Point3D{t.V1, t.V2, t.V3} {
-			if v.X < minX {
-				minX = v.X
-			}
-			if v.Y < minY {
-				minY = v.Y
-			}
-			if v.Z < minZ {
-				minZ = v.Z
-			}
-			if v.X > maxX {
-				maxX = v.X
-			}
-			if v.Y > maxY {
-				maxY = v.Y
-			}
-			if v.Z > maxZ {
-				maxZ = v.Z
-			}
-		}
-	}
-	return
-}
-
-// translate 함수
-func translateTriangles(triangles []types.Triangle, dx, dy, dz float64) []types.Triangle {
-	moved := make([]types.Triangle, len(triangles))
-	for i, t := range triangles {
-		moved[i] = types.Triangle{
-			Normal: t.Normal,
-			V1:     types.Point3D{X: t.V1.X + dx, Y: t.V1.Y + dy, Z: t.V1.Z + dz},
-			V2:     types.Point3D{X: t.V2.X + dx, Y: t.V2.Y + dy, Z: t.V2.Z + dz},
-			V3:     types.Point3D{X: t.V3.X + dx, Y: t.V3.Y + dy, Z: t.V3.Z + dz},
-		}
-	}
-	return moved
-}
-
-// 스케일 함수
-func scaleTriangles(triangles []types.Triangle, scale float64) []types.Triangle {
-	scaled := make([]types.Triangle, len(triangles))
-	for i, t := range triangles {
-		scaled[i] = types.Triangle{
-			Normal: t.Normal,
-			V1:     types.Point3D{X: t.V1.X * scale, Y: t.V1.Y * scale, Z: t.V1.Z * scale},
-			V2:     types.Point3D{X: t.V2.X * scale, Y: t.V2.Y * scale, Z: t.V2.Z * scale},
-			V3:     types.Point3D{X: t.V3.X * scale, Y: t.V3.Y * scale, Z: t.V3.Z * scale},
-		}
-	}
-	return scaled
+	ch <- geometryResult{triangles: logoTriangles, obstacles: []obstacle{{name: "logo", bounds: geometry.CalculateBoundingBox(logoTriangles)}}}
 }
@@ -1,12 +1,18 @@
 package stl
 
 import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/github/gh-skyline/internal/stl/geometry"
 	"github.com/github/gh-skyline/internal/types"
 )
 
@@ -27,7 +33,7 @@ func TestGenerateSTL(t *testing.T) {
 	tempDir := t.TempDir()
 	outputPath := filepath.Join(tempDir, "test.stl")
 
-	err := GenerateSTL(contributions, outputPath, "testuser", 2023)
+	err := GenerateSTL(context.Background(), contributions, outputPath, "testuser", 2023, ModelOptions{})
 	if err != nil {
 		// Check if error is due to missing resources
 		if strings.Contains(err.Error(), "failed to open image") ||
@@ -58,14 +64,29 @@ func TestGenerateSTL(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := GenerateSTL(tt.contributions, tt.outputPath, tt.username, tt.year)
+			err := GenerateSTL(context.Background(), tt.contributions, tt.outputPath, tt.username, tt.year, ModelOptions{})
 			if (err != nil) != tt.wantErr {
-				t.Errorf("GenerateSTL() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("GenerateSTL(context.Background(), ) error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
 
+// TestGenerateSTLValidates verifies a model generated with the default options has no mesh defects.
+func TestGenerateSTLValidates(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "default.stl")
+	if err := GenerateSTL(context.Background(), createTestContributions(), outputPath, "testuser", 2023, ModelOptions{}); err != nil {
+		t.Fatalf("GenerateSTL() error = %v", err)
+	}
+	triangles, err := ReadSTL(outputPath)
+	if err != nil {
+		t.Fatalf("ReadSTL() error = %v", err)
+	}
+	if report := geometry.ValidateMesh(triangles); report.HasDefects() {
+		t.Errorf("generated model has defects: %v", report.Defects())
+	}
+}
+
 func TestGenerateSTLRange(t *testing.T) {
 	// Create test data for multiple years
 	contributionsRange := make([][][]types.ContributionDay, 3)
@@ -164,15 +185,15 @@ func TestGenerateSTLRange(t *testing.T) {
 			// To prevent panic in test execution, wrap the function call
 			defer func() {
 				if r := recover(); r != nil && !tt.wantErr {
-					t.Errorf("GenerateSTLRange() panicked: %v", r)
+					t.Errorf("GenerateSTLRange(context.Background(), ) panicked: %v", r)
 				}
 			}()
 
-			err := GenerateSTLRange(tt.contributions, tt.outputPath, tt.username, tt.startYear, tt.endYear)
+			err := GenerateSTLRange(context.Background(), tt.contributions, tt.outputPath, tt.username, tt.startYear, tt.endYear, ModelOptions{})
 			if (err != nil) != tt.wantErr {
 				// Only fail if the error is not related to missing resources
 				if !strings.Contains(err.Error(), "failed to open image") {
-					t.Errorf("GenerateSTLRange() error = %v, wantErr %v", err, tt.wantErr)
+					t.Errorf("GenerateSTLRange(context.Background(), ) error = %v, wantErr %v", err, tt.wantErr)
 				}
 			}
 
@@ -327,7 +348,7 @@ func TestGenerateBase(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 
-	go generateBase(dims, ch, &wg)
+	go generateBase(dims, geometry.BaseOptions{}, ch, &wg)
 
 	result := <-ch
 	if result.err != nil {
@@ -347,7 +368,7 @@ func TestGenerateText(t *testing.T) {
 	var wg sync.WaitGroup
 	wg.Add(1)
 
-	go generateText("testuser", 2023, 2023, dims, ch, &wg)
+	go generateText(textBlocks(2023, 2023, ModelOptions{}), dims, ch, &wg, geometry.TextFonts{})
 
 	result := <-ch
 	if result.err != nil {
@@ -379,7 +400,7 @@ func TestGenerateColumnsForYearRange(t *testing.T) {
 	maxContrib := 10 // Set a known max contribution value
 
 	// Test the goroutine
-	go generateColumnsForYearRange(contributionsPerYear, maxContrib, ch, &wg)
+	go generateColumnsForYearRange(context.Background(), contributionsPerYear, maxContrib, nil, false, ch, &wg)
 
 	// Collect the result
 	result := <-ch
@@ -429,24 +450,24 @@ func TestGenerateModelGeometry(t *testing.T) {
 	startYear := 2022
 	endYear := 2023
 
-	triangles, err := generateModelGeometry(contributionsPerYear, dims, maxContrib, username, startYear, endYear)
+	triangles, err := generateModelGeometry(context.Background(), contributionsPerYear, dims, maxContrib, username, startYear, endYear, ModelOptions{})
 	if err != nil {
-		t.Errorf("generateModelGeometry() error = %v", err)
+		t.Errorf("generateModelGeometry(context.Background(), ) error = %v", err)
 	}
 	if len(triangles) == 0 {
-		t.Error("generateModelGeometry() returned no triangles")
+		t.Error("generateModelGeometry(context.Background(), ) returned no triangles")
 	}
 
 	// Test error case with nil contributions
-	_, err = generateModelGeometry(nil, dims, maxContrib, username, startYear, endYear)
+	_, err = generateModelGeometry(context.Background(), nil, dims, maxContrib, username, startYear, endYear, ModelOptions{})
 	if err == nil {
-		t.Error("generateModelGeometry() should return error for nil contributions")
+		t.Error("generateModelGeometry(context.Background(), ) should return error for nil contributions")
 	}
 
 	// Test with empty username
-	_, err = generateModelGeometry(contributionsPerYear, dims, maxContrib, "", startYear, endYear)
+	_, err = generateModelGeometry(context.Background(), contributionsPerYear, dims, maxContrib, "", startYear, endYear, ModelOptions{})
 	if err != nil {
-		t.Error("generateModelGeometry() should handle empty username")
+		t.Error("generateModelGeometry(context.Background(), ) should handle empty username")
 	}
 }
 
@@ -521,7 +542,7 @@ func TestGenerateText_WithYearRange(t *testing.T) {
 			var wg sync.WaitGroup
 			wg.Add(1)
 
-			go generateText(tt.username, tt.startYear, tt.endYear, dims, ch, &wg)
+			go generateText(textBlocks(tt.startYear, tt.endYear, ModelOptions{}), dims, ch, &wg, geometry.TextFonts{})
 
 			result := <-ch
 			// Even if font generation fails, result should not be nil
@@ -559,7 +580,7 @@ func TestGenerateColumnsForYearRange_Extended(t *testing.T) {
 			var wg sync.WaitGroup
 			wg.Add(1)
 
-			go generateColumnsForYearRange(contributionsPerYear, tt.maxContrib, ch, &wg)
+			go generateColumnsForYearRange(context.Background(), contributionsPerYear, tt.maxContrib, nil, false, ch, &wg)
 
 			result := <-ch
 			if tt.expectTriangles && len(result.triangles) == 0 {
@@ -584,7 +605,8 @@ func TestResourceHandling(t *testing.T) {
 		wg.Add(1)
 
 		// This should log a warning but continue
-		go generateText("testuser", 2023, 2023, dims, ch, &wg)
+		fonts := geometry.TextFonts{Default: []string{"does-not-exist.ttf"}}
+		go generateText(textBlocks(2023, 2023, ModelOptions{}), dims, ch, &wg, fonts)
 
 		result := <-ch
 		// Even with missing fonts, we should get a valid (possibly empty) result
@@ -629,14 +651,14 @@ func TestResourceHandling(t *testing.T) {
 		maxContrib := findMaxContributionsAcrossYears(contributionsPerYear)
 
 		// This should complete successfully even with missing resources
-		triangles, err := generateModelGeometry(contributionsPerYear, dims, maxContrib, "testuser", 2022, 2023)
+		triangles, err := generateModelGeometry(context.Background(), contributionsPerYear, dims, maxContrib, "testuser", 2022, 2023, ModelOptions{})
 		if err != nil {
-			t.Errorf("generateModelGeometry() failed with missing resources: %v", err)
+			t.Errorf("generateModelGeometry(context.Background(), ) failed with missing resources: %v", err)
 		}
 
 		// Should still generate base geometry and contribution columns
 		if len(triangles) == 0 {
-			t.Error("generateModelGeometry() returned no triangles with missing resources")
+			t.Error("generateModelGeometry(context.Background(), ) returned no triangles with missing resources")
 		}
 	})
 }
@@ -668,3 +690,393 @@ func TestCalculateDimensionsEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateModelGeometryTriangleBudget(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions()}
+	dims, err := calculateDimensions(1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	maxContrib := findMaxContributionsAcrossYears(contributionsPerYear)
+	opts := ModelOptions{TopText: "budget test", RightText: "skyline"}
+
+	full, err := generateModelGeometry(context.Background(), contributionsPerYear, dims, maxContrib, "testuser", 2023, 2023, opts)
+	if err != nil {
+		t.Fatalf("generateModelGeometry(context.Background(), ) error = %v", err)
+	}
+
+	opts.MaxTriangles = len(full) * 3 / 4
+	limited, err := generateModelGeometry(context.Background(), contributionsPerYear, dims, maxContrib, "testuser", 2023, 2023, opts)
+	if err != nil {
+		t.Fatalf("generateModelGeometry(context.Background(), ) error = %v", err)
+	}
+	if len(limited) > opts.MaxTriangles || len(limited) < opts.MaxTriangles*9/10 {
+		t.Errorf("generateModelGeometry(context.Background(), ) returned %d triangles, want close to budget %d", len(limited), opts.MaxTriangles)
+	}
+	if report := geometry.ValidateMesh(limited); report.HasDefects() {
+		t.Errorf("decimated model has defects: %v", report.Defects())
+	}
+
+	// A budget smaller than the base and columns keeps them and drops the details
+	opts.MaxTriangles = 1
+	structural, err := generateModelGeometry(context.Background(), contributionsPerYear, dims, maxContrib, "testuser", 2023, 2023, opts)
+	if err != nil {
+		t.Fatalf("generateModelGeometry(context.Background(), ) error = %v", err)
+	}
+	if len(structural) == 0 || len(structural) >= len(limited) {
+		t.Errorf("expected only base and column triangles, got %d", len(structural))
+	}
+
+	if err := GenerateSTL(context.Background(), createTestContributions(), filepath.Join(t.TempDir(), "test.stl"), "testuser", 2023, ModelOptions{MaxTriangles: -1}); err == nil {
+		t.Error("GenerateSTL(context.Background(), ) expected error for negative triangle budget")
+	}
+}
+
+// TestGenerateModelGeometryQRCode verifies the QR code is added exactly and is
+// never decimated, and that a QR code that cannot be created fails generation.
+func TestGenerateModelGeometryQRCode(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions()}
+	dims, err := calculateDimensions(1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	maxContrib := findMaxContributionsAcrossYears(contributionsPerYear)
+	qr := geometry.QRCodeOptions{Enabled: true, Content: "https://github.com/testuser"}
+	qrTriangles, err := geometry.CreateQRCode(dims.innerWidth, dims.innerDepth, qr)
+	if err != nil {
+		t.Fatalf("CreateQRCode() error = %v", err)
+	}
+
+	without, err := generateModelGeometry(context.Background(), contributionsPerYear, dims, maxContrib, "testuser", 2023, 2023, ModelOptions{MaxTriangles: 1})
+	if err != nil {
+		t.Fatalf("generateModelGeometry(context.Background(), ) error = %v", err)
+	}
+	with, err := generateModelGeometry(context.Background(), contributionsPerYear, dims, maxContrib, "testuser", 2023, 2023, ModelOptions{MaxTriangles: 1, QRCode: qr})
+	if err != nil {
+		t.Fatalf("generateModelGeometry(context.Background(), ) error = %v", err)
+	}
+	if len(with)-len(without) != len(qrTriangles) {
+		t.Errorf("QR code added %d triangles, want all %d", len(with)-len(without), len(qrTriangles))
+	}
+
+	qr.Size = 5
+	if _, err := generateModelGeometry(context.Background(), contributionsPerYear, dims, maxContrib, "testuser", 2023, 2023, ModelOptions{QRCode: qr}); err == nil {
+		t.Error("generateModelGeometry(context.Background(), ) expected error for a QR code too small to print")
+	}
+}
+
+// TestGenerateModelObjectsMarkers verifies each matching date marker becomes its
+// own object and that .3mf outputs keep the objects separate.
+func TestGenerateModelObjectsMarkers(t *testing.T) {
+	contributions := createTestContributions()
+	contributions[10][3].Date = "2023-03-15"
+	contributions[0][0].Date = "2023-01-01" // No contributions
+	contributionsPerYear := [][][]types.ContributionDay{contributions}
+	dims, err := calculateDimensions(1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+
+	markers := []geometry.DateMarker{
+		{Date: "03-15", Style: geometry.MarkerBody, Color: "#FF0000", Label: "Launch"},
+		{Date: "2023-01-01", Style: geometry.MarkerPin, Color: geometry.DefaultMarkerColor},
+		{Date: "2023-12-25", Style: geometry.MarkerCap}, // Not in the model
+	}
+	opts := ModelOptions{Markers: markers}
+	objects, err := generateModelObjects(context.Background(), contributionsPerYear, dims, findMaxContributions(contributions), "testuser", 2023, 2023, opts)
+	if err != nil {
+		t.Fatalf("generateModelObjects(context.Background(), ) error = %v", err)
+	}
+	if len(objects) != 3 || objects[0].Name != "skyline" || objects[1].Name != "Launch" || objects[2].Name != "2023-01-01" {
+		t.Fatalf("unexpected objects %v", objectNames(objects))
+	}
+	if len(objects[1].Triangles) != 12 || objects[1].Color != "#FF0000" {
+		t.Errorf("body marker should hold the marked column, got %d triangles", len(objects[1].Triangles))
+	}
+
+	path := filepath.Join(t.TempDir(), "marked.3mf")
+	if err := GenerateSTL(context.Background(), contributions, path, "testuser", 2023, ModelOptions{Markers: markers, SkipCheck: true}); err != nil {
+		t.Fatalf("GenerateSTL(context.Background(), ) error = %v", err)
+	}
+	if model := read3MF(t, path); len(model.Objects) != 3 {
+		t.Errorf("3MF output has %d objects, want 3", len(model.Objects))
+	}
+
+	invalid := ModelOptions{Markers: []geometry.DateMarker{{Date: "someday", Style: geometry.MarkerCap}}}
+	if err := GenerateSTL(context.Background(), contributions, filepath.Join(t.TempDir(), "invalid.stl"), "testuser", 2023, invalid); err == nil {
+		t.Error("GenerateSTL(context.Background(), ) expected error for an invalid marker")
+	}
+}
+
+func objectNames(objects []Object) []string {
+	names := make([]string, len(objects))
+	for i, object := range objects {
+		names[i] = object.Name
+	}
+	return names
+}
+
+func TestGenerateModelObjectsStreak(t *testing.T) {
+	contributions := createTestContributions()
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range contributions {
+		for j := range contributions[i] {
+			contributions[i][j].Date = start.AddDate(0, 0, 7*i+j).Format("2006-01-02")
+		}
+	}
+	contributionsPerYear := [][][]types.ContributionDay{contributions}
+	dims, err := calculateDimensions(1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	streak := types.LongestStreak(contributions)
+	if streak.Length == 0 {
+		t.Fatal("test data should contain a streak")
+	}
+
+	opts := ModelOptions{Streak: geometry.StreakOptions{Kind: geometry.StreakLongest}}
+	objects, err := generateModelObjects(context.Background(), contributionsPerYear, dims, findMaxContributions(contributions), "testuser", 2023, 2023, opts)
+	if err != nil {
+		t.Fatalf("generateModelObjects(context.Background(), ) error = %v", err)
+	}
+	if len(objects) != 2 || objects[1].Name != geometry.StreakLabel(streak) {
+		t.Fatalf("unexpected objects %v", objectNames(objects))
+	}
+	if len(objects[1].Triangles) != 12*streak.Length || objects[1].Color != geometry.DefaultStreakColor {
+		t.Errorf("streak rail has %d triangles in %s, want %d in %s", len(objects[1].Triangles), objects[1].Color, 12*streak.Length, geometry.DefaultStreakColor)
+	}
+
+	// A past year has no current streak, so nothing is highlighted
+	opts.Streak.Kind = geometry.StreakCurrent
+	if objects, err = generateModelObjects(context.Background(), contributionsPerYear, dims, findMaxContributions(contributions), "testuser", 2023, 2023, opts); err != nil {
+		t.Fatalf("generateModelObjects(context.Background(), ) error = %v", err)
+	}
+	if len(objects) != 1 {
+		t.Errorf("unexpected objects %v", objectNames(objects))
+	}
+
+	invalid := ModelOptions{Streak: geometry.StreakOptions{Kind: "best"}}
+	if err := GenerateSTL(context.Background(), contributions, filepath.Join(t.TempDir(), "invalid.stl"), "testuser", 2023, invalid); err == nil {
+		t.Error("GenerateSTL(context.Background(), ) expected error for an invalid streak")
+	}
+}
+
+func TestGenerateModelObjectsBreakdown(t *testing.T) {
+	contributions := createTestContributions()
+	for i := range contributions {
+		for j := range contributions[i] {
+			day := &contributions[i][j]
+			if day.ContributionCount > 1 {
+				day.Breakdown = &types.ContributionBreakdown{Commits: 1, Reviews: day.ContributionCount - 1}
+			}
+		}
+	}
+	contributionsPerYear := [][][]types.ContributionDay{contributions}
+	dims, err := calculateDimensions(1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+
+	plain, err := generateModelObjects(context.Background(), contributionsPerYear, dims, findMaxContributions(contributions), "testuser", 2023, 2023, ModelOptions{})
+	if err != nil {
+		t.Fatalf("generateModelObjects() error = %v", err)
+	}
+	objects, err := generateModelObjects(context.Background(), contributionsPerYear, dims, findMaxContributions(contributions), "testuser", 2023, 2023, ModelOptions{Breakdown: true})
+	if err != nil {
+		t.Fatalf("generateModelObjects() error = %v", err)
+	}
+	if names := objectNames(objects); fmt.Sprint(names) != "[skyline commits reviews other contributions]" {
+		t.Fatalf("unexpected objects %v", names)
+	}
+	if objects[1].Color != geometry.ContributionTypes[0].Color {
+		t.Errorf("commits have colour %s, want %s", objects[1].Color, geometry.ContributionTypes[0].Color)
+	}
+
+	// The columns move out of the skyline into one box per segment; days with a
+	// single contribution have no breakdown and count as other
+	columns, segments := len(plain[0].Triangles)-len(objects[0].Triangles), 0
+	for _, object := range objects[1:] {
+		segments += len(object.Triangles)
+	}
+	if columns != 12*countDays(contributions, 1) || segments != columns+12*countDays(contributions, 2) {
+		t.Errorf("stacked objects have %d triangles for %d column triangles", segments, columns)
+	}
+
+	path := filepath.Join(t.TempDir(), "breakdown.stl")
+	err = GenerateSTL(context.Background(), contributions, path, "testuser", 2023, ModelOptions{Breakdown: true, ColorBands: 3})
+	if err == nil {
+		t.Error("GenerateSTL() expected error for color bands with the breakdown")
+	}
+}
+
+// countDays returns the number of days with at least least contributions.
+func countDays(contributions [][]types.ContributionDay, least int) int {
+	count := 0
+	for _, week := range contributions {
+		for _, day := range week {
+			if day.ContributionCount >= least {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+func TestGenerateSTLColorBands(t *testing.T) {
+	contributions := createTestContributions()
+	dir := t.TempDir()
+
+	if err := GenerateSTL(context.Background(), contributions, filepath.Join(dir, "bands.stl"), "testuser", 2023, ModelOptions{ColorBands: 4, SkipCheck: true}); err != nil {
+		t.Errorf("GenerateSTL(context.Background(), ) error = %v", err)
+	}
+	for _, bands := range []int{-1, MaxColorBands + 1} {
+		if err := GenerateSTL(context.Background(), contributions, filepath.Join(dir, "invalid.stl"), "testuser", 2023, ModelOptions{ColorBands: bands}); err == nil {
+			t.Errorf("GenerateSTL(context.Background(), ) expected error for %d color bands", bands)
+		}
+	}
+}
+
+func TestGenerateSTLTextSlots(t *testing.T) {
+	contributions := createTestContributions()
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		slot string
+	}{
+		{name: "unknown slot", slot: "frnt-left"},
+		{name: "row beyond the grid", slot: geometry.RowSlot(1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := ModelOptions{Texts: []geometry.TextBlock{{Slot: tt.slot, Text: "hello"}}, SkipCheck: true}
+			if err := GenerateSTL(context.Background(), contributions, filepath.Join(dir, "text.stl"), "testuser", 2023, opts); err == nil {
+				t.Error("GenerateSTL() dropped a text block it could not place instead of failing")
+			}
+		})
+	}
+}
+
+func TestTextBlocksRowLabels(t *testing.T) {
+	opts := ModelOptions{
+		RowLabels: []string{"mona", "hubot"},
+		Texts:     []geometry.TextBlock{{Slot: geometry.RowSlot(1), Text: "octocat"}},
+	}
+	labels := map[string]string{}
+	for _, block := range textBlocks(2024, 2024, opts) {
+		labels[block.Slot] = block.Text
+	}
+	if labels["row-1"] != "mona" || labels["row-2"] != "octocat" {
+		t.Errorf("row labels = %v, want mona and the --text block in row-2", labels)
+	}
+
+	contributions := [][][]types.ContributionDay{createTestContributions()}
+	err := GenerateSTLRange(context.Background(), contributions, filepath.Join(t.TempDir(), "rows.stl"), "team", 2024, 2024, ModelOptions{RowLabels: opts.RowLabels})
+	if err == nil {
+		t.Error("GenerateSTLRange() accepted more row labels than rows")
+	}
+}
+
+func TestGenerateSTLMold(t *testing.T) {
+	contributions := createTestContributions()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mold.stl")
+
+	opts := ModelOptions{Mold: geometry.MoldOptions{Enabled: true}, SkipCheck: true, MaxTriangles: 100}
+	if err := GenerateSTL(context.Background(), contributions, path, "testuser", 2023, opts); err != nil {
+		t.Fatalf("GenerateSTL(context.Background(), ) error = %v", err)
+	}
+	triangles, err := ReadSTL(path)
+	if err != nil {
+		t.Fatalf("ReadSTL() error = %v", err)
+	}
+	boxes, rest := geometry.ExtractBoxes(triangles)
+	if len(boxes) == 0 || len(rest) != 0 {
+		t.Errorf("mold has %d boxes and %d other triangles", len(boxes), len(rest))
+	}
+
+	// The mold encloses the base with walls on every side but the opening
+	bounds := geometry.CalculateBoundingBox(triangles)
+	width, depth := geometry.CalculateMultiYearDimensions(1)
+	if size := bounds.Size(); size.X < width+2*geometry.DefaultMoldWall-1e-3 || size.Y < depth+2*geometry.DefaultMoldWall-1e-3 {
+		t.Errorf("mold size %.2f x %.2f is smaller than the base with walls", size.X, size.Y)
+	}
+
+	invalid := []ModelOptions{
+		{Mold: geometry.MoldOptions{Enabled: true, Wall: -2}},
+		{Mold: geometry.MoldOptions{Enabled: true}, ColorBands: 4},
+	}
+	for _, opts := range invalid {
+		if err := GenerateSTL(context.Background(), contributions, filepath.Join(dir, "invalid.stl"), "testuser", 2023, opts); err == nil {
+			t.Errorf("GenerateSTL(context.Background(), %+v) expected error", opts)
+		}
+	}
+}
+
+// TestGenerateModelGeometryBaseShapes verifies every base shape with a frame is a
+// clean mesh and that the oval base leaves out text on the side faces.
+func TestGenerateModelGeometryBaseShapes(t *testing.T) {
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions()}
+	dims, err := calculateDimensions(1)
+	if err != nil {
+		t.Fatalf("calculateDimensions() error = %v", err)
+	}
+	maxContrib := findMaxContributionsAcrossYears(contributionsPerYear)
+
+	for _, shape := range geometry.BaseShapes() {
+		t.Run(shape, func(t *testing.T) {
+			opts := ModelOptions{Base: geometry.BaseOptions{Shape: geometry.BaseShape(shape), Frame: true}}
+			base, err := geometry.CreateBase(dims.innerWidth, dims.innerDepth, opts.Base)
+			if err != nil {
+				t.Fatalf("CreateBase() error = %v", err)
+			}
+			if report := geometry.ValidateMesh(base.Triangles); report.HasDefects() {
+				t.Errorf("base mesh has defects:\n%s", report)
+			}
+
+			triangles, err := generateModelGeometry(context.Background(), contributionsPerYear, dims, maxContrib, "testuser", 2023, 2023, opts)
+			if err != nil {
+				t.Fatalf("generateModelGeometry(context.Background(), ) error = %v", err)
+			}
+			bounds := geometry.CalculateBoundingBox(triangles)
+			textInFront := bounds.Min.Y < 0
+			if shape == string(geometry.BaseOval) {
+				textInFront = bounds.Min.Y < dims.innerDepth/2*(1-math.Sqrt2)-1e-6
+			}
+			if textInFront != (shape != string(geometry.BaseOval)) {
+				t.Errorf("front text present = %v for the %s base", textInFront, shape)
+			}
+		})
+	}
+
+	invalid := []ModelOptions{
+		{Base: geometry.BaseOptions{Shape: "hexagon"}},
+		{Base: geometry.BaseOptions{Shape: geometry.BaseRounded}, Mold: geometry.MoldOptions{Enabled: true}},
+		{Base: geometry.BaseOptions{Shape: geometry.BaseOval}, QRCode: geometry.QRCodeOptions{Enabled: true}},
+	}
+	for _, opts := range invalid {
+		if err := GenerateSTL(context.Background(), createTestContributions(), filepath.Join(t.TempDir(), "invalid.stl"), "testuser", 2023, opts); err == nil {
+			t.Errorf("GenerateSTL(context.Background(), %+v) expected error", opts.Base)
+		}
+	}
+}
+
+// TestGenerateSTLCancelled verifies a done context stops generation before anything is written.
+func TestGenerateSTLCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	path := filepath.Join(t.TempDir(), "cancelled.stl")
+	err := GenerateSTL(ctx, createTestContributions(), path, "testuser", 2023, ModelOptions{})
+	if !stderrors.Is(err, context.Canceled) {
+		t.Fatalf("GenerateSTL() error = %v, want context.Canceled", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("GenerateSTL() wrote %s after cancellation", path)
+	}
+
+	dims, _ := calculateDimensions(1)
+	contributionsPerYear := [][][]types.ContributionDay{createTestContributions()}
+	if _, err := generateModelObjects(ctx, contributionsPerYear, dims, 10, "testuser", 2023, 2023, ModelOptions{}); !stderrors.Is(err, context.Canceled) {
+		t.Errorf("generateModelObjects() error = %v, want context.Canceled", err)
+	}
+}
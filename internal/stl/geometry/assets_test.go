@@ -6,45 +6,6 @@ import (
 	"testing"
 )
 
-// TestWriteTempFont verifies temporary font file creation and cleanup
-func TestWriteTempFont(t *testing.T) {
-	t.Run("verify valid font extraction", func(t *testing.T) {
-		fontPath, cleanup, err := writeTempFont("monasans-medium.ttf")
-		if err != nil {
-			t.Fatalf("writeTempFont failed: %v", err)
-		}
-		defer cleanup()
-
-		// Verify file exists and has content
-		content, err := os.ReadFile(fontPath)
-		if err != nil {
-			t.Errorf("Failed to read temp font file: %v", err)
-		}
-		if len(content) == 0 {
-			t.Error("Temp font file is empty")
-		}
-
-		// Verify file extension
-		if filepath.Ext(fontPath) != ".ttf" {
-			t.Errorf("Expected .ttf extension, got %s", filepath.Ext(fontPath))
-		}
-
-		// Verify cleanup works
-		cleanup()
-		if _, err := os.Stat(fontPath); !os.IsNotExist(err) {
-			t.Error("Temp font file not cleaned up properly")
-		}
-	})
-
-	t.Run("verify nonexistent font handling", func(t *testing.T) {
-		_, cleanup, err := writeTempFont("nonexistent.ttf")
-		if err == nil {
-			defer cleanup()
-			t.Error("Expected error for nonexistent font")
-		}
-	})
-}
-
 // TestGetEmbeddedImage verifies temporary image file creation and cleanup
 func TestGetEmbeddedImage(t *testing.T) {
 	t.Run("verify valid image extraction", func(t *testing.T) {
@@ -0,0 +1,289 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// BaseShape selects the outline and edges of the base plate.
+type BaseShape string
+
+// Supported base shapes.
+const (
+	BaseBox       BaseShape = "box"       // Plain cuboid
+	BaseRounded   BaseShape = "rounded"   // Cuboid with rounded vertical corners
+	BaseChamfered BaseShape = "chamfered" // Cuboid with bevelled top edges
+	BaseOval      BaseShape = "oval"      // Elliptical plate enclosing the cuboid's footprint
+)
+
+// Defaults and limits of the base shape options (mm).
+const (
+	DefaultBaseRadius  = 2 * CellSize
+	DefaultBaseChamfer = 1.5
+	DefaultFrameWidth  = 1.5
+	DefaultFrameHeight = 1.0
+	// MaxBaseEdge bounds the corner radius, the chamfer and the frame to the margin
+	// between the base edge and the contribution grid.
+	MaxBaseEdge = 2 * CellSize
+)
+
+const (
+	cornerSegments = 8  // Segments of each rounded corner
+	ovalSegments   = 96 // Segments of the oval outline
+)
+
+// BaseShapes returns the names of the supported base shapes.
+func BaseShapes() []string {
+	return []string{string(BaseBox), string(BaseRounded), string(BaseChamfered), string(BaseOval)}
+}
+
+// BaseOptions controls the shape of the base plate. The zero value is the plain cuboid.
+type BaseOptions struct {
+	Shape       BaseShape
+	Radius      float64 // Corner radius of a rounded base
+	Chamfer     float64 // Width and height of the bevel along the top edges of a chamfered base
+	Frame       bool    // Raise a decorative frame along the edge of the top face
+	FrameWidth  float64
+	FrameHeight float64
+}
+
+// withDefaults fills unset fields with the defaults.
+func (o BaseOptions) withDefaults() BaseOptions {
+	if o.Shape == "" {
+		o.Shape = BaseBox
+	}
+	if o.Radius == 0 {
+		o.Radius = DefaultBaseRadius
+	}
+	if o.Chamfer == 0 {
+		o.Chamfer = DefaultBaseChamfer
+	}
+	if o.FrameWidth == 0 {
+		o.FrameWidth = DefaultFrameWidth
+	}
+	if o.FrameHeight == 0 {
+		o.FrameHeight = DefaultFrameHeight
+	}
+	return o
+}
+
+// Validate checks the shape name and the sizes used by the shape.
+func (o BaseOptions) Validate() error {
+	o = o.withDefaults()
+	inRange := func(v, max float64) bool { return v > 0 && v <= max }
+
+	switch o.Shape {
+	case BaseBox, BaseOval:
+	case BaseRounded:
+		if !inRange(o.Radius, MaxBaseEdge) {
+			return errors.New(errors.ValidationError, fmt.Sprintf("base corner radius must be between 0 and %.1f mm", MaxBaseEdge), nil)
+		}
+	case BaseChamfered:
+		if !inRange(o.Chamfer, MaxBaseEdge) {
+			return errors.New(errors.ValidationError, fmt.Sprintf("base chamfer must be between 0 and %.1f mm", MaxBaseEdge), nil)
+		}
+	default:
+		return errors.New(errors.ValidationError, fmt.Sprintf("unknown base shape %q (expected one of %s)", o.Shape, strings.Join(BaseShapes(), ", ")), nil)
+	}
+
+	if o.Frame {
+		maxWidth := MaxBaseEdge
+		if o.Shape == BaseChamfered {
+			maxWidth -= o.Chamfer
+		}
+		if !inRange(o.FrameWidth, maxWidth) {
+			return errors.New(errors.ValidationError, fmt.Sprintf("frame width must be between 0 and %.1f mm", maxWidth), nil)
+		}
+		if !inRange(o.FrameHeight, MaxHeight) {
+			return errors.New(errors.ValidationError, fmt.Sprintf("frame height must be between 0 and %.1f mm", MaxHeight), nil)
+		}
+	}
+	return nil
+}
+
+// IsBox reports whether the base is a plain cuboid without a frame, built from a single box.
+func (o BaseOptions) IsBox() bool {
+	return o.withDefaults().Shape == BaseBox && !o.Frame
+}
+
+// HasSideFaces reports whether the base has flat front, back and side faces for
+// text and the logo.
+func (o BaseOptions) HasSideFaces() bool {
+	return o.withDefaults().Shape != BaseOval
+}
+
+// BaseGeometry is a base plate and the parts of its edge that text should avoid.
+type BaseGeometry struct {
+	Triangles []types.Triangle
+	Frame     []BoundingBox // Pieces of the raised frame
+	Chamfer   []BoundingBox // Space around the bevelled edges, where text would float
+}
+
+// CreateBase generates the base plate for a model of the given width and depth,
+// with its top face at Z = 0 like CreateCuboidBase. The oval plate encloses the
+// whole width × depth footprint, so everything placed on the top face stays on it.
+func CreateBase(width, depth float64, opts BaseOptions) (BaseGeometry, error) {
+	if err := opts.Validate(); err != nil {
+		return BaseGeometry{}, err
+	}
+	opts = opts.withDefaults()
+
+	if opts.Shape == BaseBox && !opts.Frame {
+		triangles, err := CreateCuboidBase(width, depth)
+		return BaseGeometry{Triangles: triangles}, err
+	}
+
+	// The base is one solid lofted through its outlines, up the sides, over the
+	// frame and down to the top face, so the frame shares no edges with the plate
+	var base BaseGeometry
+	outline := func(inset, z float64) []types.Point3D { return baseOutline(opts, width, depth, inset, z) }
+	outlines := [][]types.Point3D{outline(0, -BaseHeight)}
+	inset := 0.0
+	if opts.Shape == BaseChamfered {
+		c := opts.Chamfer
+		inset = c
+		outlines = append(outlines, outline(0, -c))
+		base.Chamfer = []BoundingBox{
+			{Min: types.Point3D{X: -c, Y: -c, Z: -c}, Max: types.Point3D{X: width + c, Y: c, Z: c}},
+			{Min: types.Point3D{X: -c, Y: depth - c, Z: -c}, Max: types.Point3D{X: width + c, Y: depth + c, Z: c}},
+			{Min: types.Point3D{X: -c, Y: -c, Z: -c}, Max: types.Point3D{X: c, Y: depth + c, Z: c}},
+			{Min: types.Point3D{X: width - c, Y: -c, Z: -c}, Max: types.Point3D{X: width + c, Y: depth + c, Z: c}},
+		}
+	}
+	outlines = append(outlines, outline(inset, 0))
+	if opts.Frame {
+		w, h := opts.FrameWidth, opts.FrameHeight
+		outer, inner := outline(inset, h), outline(inset+w, 0)
+		outlines = append(outlines, outer, outline(inset+w, h), inner)
+		base.Frame = framePieces(outer, inner)
+	}
+
+	triangles, err := createLoft(outlines)
+	if err != nil {
+		return BaseGeometry{}, errors.New(errors.STLError, "failed to create base", err)
+	}
+	base.Triangles = triangles
+	return base, nil
+}
+
+// baseOutline returns the outline of the base shape at height z, shrunk by inset,
+// as points running counter-clockwise seen from above. Outlines of the same shape
+// always have the same number of points, so they can be joined into a solid.
+func baseOutline(opts BaseOptions, width, depth, inset, z float64) []types.Point3D {
+	switch opts.Shape {
+	case BaseOval:
+		// The smallest ellipse through the corners of the footprint
+		cx, cy := width/2, depth/2
+		a, b := cx*math.Sqrt2-inset, cy*math.Sqrt2-inset
+		points := make([]types.Point3D, ovalSegments)
+		for i := range points {
+			angle := 2 * math.Pi * float64(i) / ovalSegments
+			points[i] = types.Point3D{X: cx + a*math.Cos(angle), Y: cy + b*math.Sin(angle), Z: z}
+		}
+		return points
+	case BaseRounded:
+		radius := math.Max(opts.Radius-inset, 0)
+		x0, y0, x1, y1 := inset+radius, inset+radius, width-inset-radius, depth-inset-radius
+		centres := [4][2]float64{{x1, y0}, {x1, y1}, {x0, y1}, {x0, y0}}
+		points := make([]types.Point3D, 0, 4*(cornerSegments+1))
+		for corner, centre := range centres {
+			for i := 0; i <= cornerSegments; i++ {
+				angle := math.Pi/2*float64(corner-1) + math.Pi/2*float64(i)/cornerSegments
+				points = append(points, types.Point3D{X: centre[0] + radius*math.Cos(angle), Y: centre[1] + radius*math.Sin(angle), Z: z})
+			}
+		}
+		return points
+	default:
+		return []types.Point3D{
+			{X: inset, Y: inset, Z: z},
+			{X: width - inset, Y: inset, Z: z},
+			{X: width - inset, Y: depth - inset, Z: z},
+			{X: inset, Y: depth - inset, Z: z},
+		}
+	}
+}
+
+// framePieces returns the bounds of the frame between each pair of neighbouring
+// points of its outer outline at the top and inner outline at the bottom.
+func framePieces(outer, inner []types.Point3D) []BoundingBox {
+	pieces := make([]BoundingBox, len(outer))
+	for i := range outer {
+		j := (i + 1) % len(outer)
+		piece := BoundingBox{Min: inner[i], Max: inner[i]}
+		for _, p := range []types.Point3D{inner[j], outer[i], outer[j]} {
+			piece = piece.Union(BoundingBox{Min: p, Max: p})
+		}
+		pieces[i] = piece
+	}
+	return pieces
+}
+
+// createLoft creates a closed solid through a sequence of outlines, each with the
+// same number of points running counter-clockwise seen from above, with the
+// solid's surface running from the first to the last. The first and last
+// outlines must be convex and face down and up; they are closed by fans from their centres.
+func createLoft(outlines [][]types.Point3D) ([]types.Triangle, error) {
+	var triangles []types.Triangle
+	var err error
+	n := len(outlines[0])
+	for k := 0; k+1 < len(outlines); k++ {
+		lower, upper := outlines[k], outlines[k+1]
+		for i := 0; i < n; i++ {
+			j := (i + 1) % n
+			if triangles, err = appendQuad(triangles, [4]types.Point3D{lower[i], lower[j], upper[j], upper[i]}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	bottom, top := outlines[0], outlines[len(outlines)-1]
+	bottomCentre, topCentre := centroid(bottom), centroid(top)
+	for i := 0; i < n; i++ {
+		j := (i + 1) % n
+		if triangles, err = appendFacet(triangles, topCentre, top[i], top[j]); err != nil {
+			return nil, err
+		}
+		if triangles, err = appendFacet(triangles, bottomCentre, bottom[j], bottom[i]); err != nil {
+			return nil, err
+		}
+	}
+	return triangles, nil
+}
+
+// appendQuad appends the two triangles of a quad, leaving out degenerate ones
+// where corners of the quad coincide.
+func appendQuad(triangles []types.Triangle, q [4]types.Point3D) ([]types.Triangle, error) {
+	triangles, err := appendFacet(triangles, q[0], q[1], q[2])
+	if err != nil {
+		return nil, err
+	}
+	return appendFacet(triangles, q[0], q[2], q[3])
+}
+
+// appendFacet appends the triangle unless it has no area.
+func appendFacet(triangles []types.Triangle, a, b, c types.Point3D) ([]types.Triangle, error) {
+	if isZeroVector(vectorCross(vectorSubtract(b, a), vectorSubtract(c, a))) {
+		return triangles, nil
+	}
+	normal, err := calculateNormal(a, b, c)
+	if err != nil {
+		return nil, err
+	}
+	return append(triangles, types.Triangle{Normal: normal, V1: a, V2: b, V3: c}), nil
+}
+
+// centroid returns the average of the points.
+func centroid(points []types.Point3D) types.Point3D {
+	var c types.Point3D
+	for _, p := range points {
+		c.X += p.X
+		c.Y += p.Y
+		c.Z += p.Z
+	}
+	n := float64(len(points))
+	return types.Point3D{X: c.X / n, Y: c.Y / n, Z: c.Z / n}
+}
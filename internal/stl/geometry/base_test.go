@@ -0,0 +1,118 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// openEdges counts directed edges of the mesh that are not matched by the same
+// edge in the opposite direction; a closed, consistently wound mesh has none.
+func openEdges(triangles []types.Triangle) int {
+	type key struct{ ax, ay, az, bx, by, bz int64 }
+	quantize := func(v float64) int64 { return int64(math.Round(v * 1e6)) }
+	edges := make(map[key]int)
+	for _, t := range triangles {
+		vertices := []types.Point3D{t.V1, t.V2, t.V3}
+		for i := range vertices {
+			a, b := vertices[i], vertices[(i+1)%3]
+			edges[key{quantize(a.X), quantize(a.Y), quantize(a.Z), quantize(b.X), quantize(b.Y), quantize(b.Z)}]++
+			edges[key{quantize(b.X), quantize(b.Y), quantize(b.Z), quantize(a.X), quantize(a.Y), quantize(a.Z)}]--
+		}
+	}
+	open := 0
+	for _, count := range edges {
+		if count != 0 {
+			open++
+		}
+	}
+	return open
+}
+
+func TestCreateBase(t *testing.T) {
+	const width, depth = 40.0, 20.0
+	ovalMin := types.Point3D{X: width / 2 * (1 - math.Sqrt2), Y: depth / 2 * (1 - math.Sqrt2), Z: -BaseHeight}
+
+	tests := []struct {
+		name       string
+		opts       BaseOptions
+		wantMin    types.Point3D
+		wantTop    float64 // Highest Z of the base
+		wantFrame  int     // Frame pieces
+		wantBevels int
+	}{
+		{name: "default box", wantMin: types.Point3D{Z: -BaseHeight}},
+		{name: "box with frame", opts: BaseOptions{Frame: true}, wantMin: types.Point3D{Z: -BaseHeight}, wantTop: DefaultFrameHeight, wantFrame: 4},
+		{name: "rounded", opts: BaseOptions{Shape: BaseRounded, Radius: 3}, wantMin: types.Point3D{Z: -BaseHeight}},
+		{name: "rounded with frame wider than the radius", opts: BaseOptions{Shape: BaseRounded, Radius: 1, Frame: true, FrameWidth: 2, FrameHeight: 0.5}, wantMin: types.Point3D{Z: -BaseHeight}, wantTop: 0.5, wantFrame: 4 * (cornerSegments + 1)},
+		{name: "chamfered", opts: BaseOptions{Shape: BaseChamfered}, wantMin: types.Point3D{Z: -BaseHeight}, wantBevels: 4},
+		{name: "chamfered with frame", opts: BaseOptions{Shape: BaseChamfered, Frame: true}, wantMin: types.Point3D{Z: -BaseHeight}, wantTop: DefaultFrameHeight, wantFrame: 4, wantBevels: 4},
+		{name: "oval", opts: BaseOptions{Shape: BaseOval}, wantMin: ovalMin},
+		{name: "oval with frame", opts: BaseOptions{Shape: BaseOval, Frame: true}, wantMin: ovalMin, wantTop: DefaultFrameHeight, wantFrame: ovalSegments},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, err := CreateBase(width, depth, tt.opts)
+			if err != nil {
+				t.Fatalf("CreateBase() error = %v", err)
+			}
+			if open := openEdges(base.Triangles); open != 0 {
+				t.Errorf("base has %d open or inconsistently wound edges", open)
+			}
+			bounds := CalculateBoundingBox(base.Triangles)
+			if math.Abs(bounds.Min.X-tt.wantMin.X) > 1e-6 || math.Abs(bounds.Min.Y-tt.wantMin.Y) > 1e-6 || bounds.Min.Z != tt.wantMin.Z {
+				t.Errorf("base min = %+v, want %+v", bounds.Min, tt.wantMin)
+			}
+			if math.Abs(bounds.Max.Z-tt.wantTop) > 1e-9 {
+				t.Errorf("base top = %.2f, want %.2f", bounds.Max.Z, tt.wantTop)
+			}
+			if len(base.Frame) != tt.wantFrame || len(base.Chamfer) != tt.wantBevels {
+				t.Errorf("got %d frame pieces and %d bevels, want %d and %d", len(base.Frame), len(base.Chamfer), tt.wantFrame, tt.wantBevels)
+			}
+		})
+	}
+
+	// Only the plain cuboid is a single box, as a mold needs
+	for _, opts := range []BaseOptions{{}, {Frame: true}, {Shape: BaseRounded}} {
+		base, _ := CreateBase(width, depth, opts)
+		boxes, _ := ExtractBoxes(base.Triangles)
+		if got := len(boxes) == 1; got != opts.IsBox() {
+			t.Errorf("%+v: base is a box = %v, IsBox() = %v", opts, got, opts.IsBox())
+		}
+	}
+
+	// The footprint's corners lie on the oval
+	oval, _ := CreateBase(width, depth, BaseOptions{Shape: BaseOval})
+	bounds := CalculateBoundingBox(oval.Triangles)
+	if a, b := bounds.Size().X/2, bounds.Size().Y/2; math.Abs((width/2)*(width/2)/(a*a)+(depth/2)*(depth/2)/(b*b)-1) > 1e-6 {
+		t.Errorf("oval %.2f x %.2f does not pass through the footprint's corners", 2*a, 2*b)
+	}
+}
+
+func TestBaseOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    BaseOptions
+		wantErr bool
+	}{
+		{name: "zero value", opts: BaseOptions{}},
+		{name: "chamfered with frame", opts: BaseOptions{Shape: BaseChamfered, Chamfer: 2, Frame: true, FrameWidth: 3}},
+		{name: "unknown shape", opts: BaseOptions{Shape: "hexagon"}, wantErr: true},
+		{name: "negative radius", opts: BaseOptions{Shape: BaseRounded, Radius: -1}, wantErr: true},
+		{name: "radius beyond the margin", opts: BaseOptions{Shape: BaseRounded, Radius: MaxBaseEdge + 1}, wantErr: true},
+		{name: "chamfer beyond the margin", opts: BaseOptions{Shape: BaseChamfered, Chamfer: 6}, wantErr: true},
+		{name: "frame and chamfer beyond the margin", opts: BaseOptions{Shape: BaseChamfered, Chamfer: 3, Frame: true, FrameWidth: 2.5}, wantErr: true},
+		{name: "negative frame height", opts: BaseOptions{Frame: true, FrameHeight: -1}, wantErr: true},
+		{name: "frame sizes ignored without a frame", opts: BaseOptions{FrameWidth: -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,330 @@
+package geometry
+
+import (
+	"math"
+	"sort"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	// decimateGrowth is the factor by which the clustering cell grows while looking for
+	// a cell size whose result fits the target.
+	decimateGrowth = 1.5
+
+	// maxDecimatePasses bounds the number of passes that grow the cell before giving up on the target.
+	maxDecimatePasses = 24
+
+	// decimateSearchPasses is the number of bisection passes that refine the cell size
+	// once a fitting size is found.
+	decimateSearchPasses = 16
+
+	// maxShellRefinements bounds how often a shell is clustered again with a halved cell
+	// when its clustered mesh is not valid.
+	maxShellRefinements = 6
+)
+
+// Decimate reduces the number of triangles to at most target using vertex clustering.
+// Each shell (a connected group of triangles, such as a run of text voxels) is clustered
+// on its own grid: vertices are snapped to the mean position of the grid cell they fall
+// into, triangles that collapse are dropped, and coincident faces with opposite winding
+// (the shared walls of adjacent voxels) are removed. A clustered shell that fails
+// ValidateMesh is clustered again with finer cells, down to the original shell, so the
+// result stays a valid mesh.
+//
+// The cell size is bisected for the largest result that still fits the target. When even
+// the coarsest clustering does not fit, the smallest shells are dropped until it does.
+func Decimate(triangles []types.Triangle, target int) []types.Triangle {
+	if target <= 0 || len(triangles) <= target {
+		return triangles
+	}
+
+	// Start with a cell size that gives roughly one cell per target triangle over the surface
+	coarse := math.Sqrt(surfaceArea(triangles) / float64(target))
+	if coarse <= 0 {
+		return triangles
+	}
+
+	shells := splitShells(triangles)
+	// Beyond the largest shell's size, a coarser cell changes nothing
+	var largest float64
+	for _, shell := range shells {
+		size := CalculateBoundingBox(shell).Size()
+		largest = math.Max(largest, math.Max(size.X, math.Max(size.Y, size.Z)))
+	}
+	neighbours := neighbouringShells(shells)
+	cluster := func(cell float64) [][]types.Triangle {
+		parts := make([][]types.Triangle, len(shells))
+		for i, shell := range shells {
+			parts[i] = simplifyShell(shell, cell)
+		}
+		return separateShells(parts, shells, neighbours)
+	}
+
+	// Grow the cell until the result fits; fine is the largest cell known not to fit
+	var fine float64
+	best := cluster(coarse)
+	for pass := 0; countTriangles(best) > target && coarse < largest && pass < maxDecimatePasses; pass++ {
+		fine = coarse
+		coarse *= decimateGrowth
+		best = cluster(coarse)
+	}
+	if countTriangles(best) > target {
+		return dropSmallestShells(best, target)
+	}
+
+	// Bisect between the two sizes, keeping the largest result that fits
+	for pass := 0; pass < decimateSearchPasses; pass++ {
+		cell := (fine + coarse) / 2
+		parts := cluster(cell)
+		if count := countTriangles(parts); count > target {
+			fine = cell
+			continue
+		} else if count > countTriangles(best) {
+			best = parts
+		}
+		coarse = cell
+	}
+	return flattenShells(best)
+}
+
+// simplifyShell clusters one shell with cells no larger than the shell in each direction,
+// so a thin shell such as embossed text keeps its depth. If the clustered shell fails
+// ValidateMesh, the cell is halved until it passes, and the original shell is kept
+// if it never does. A shell that fits in a single cell becomes its bounding box.
+func simplifyShell(shell []types.Triangle, cell float64) []types.Triangle {
+	box := CalculateBoundingBox(shell)
+	size := box.Size()
+	if cell >= math.Max(size.X, math.Max(size.Y, size.Z)) {
+		// The whole shell fits in one cell, so it collapses into its bounding box
+		if cube, err := CreateCube(box.Min.X, box.Min.Y, box.Min.Z, size.X, size.Y, size.Z); err == nil {
+			return cube
+		}
+	}
+	for pass := 0; pass < maxShellRefinements; pass++ {
+		cells := types.Point3D{X: clampCell(cell, size.X), Y: clampCell(cell, size.Y), Z: clampCell(cell, size.Z)}
+		// Offset by half a cell so the coarsest grid splits the shell through its middle
+		origin := types.Point3D{X: box.Min.X - cells.X/2, Y: box.Min.Y - cells.Y/2, Z: box.Min.Z - cells.Z/2}
+		clustered := clusterVertices(shell, origin, cells)
+		if len(clustered) > 0 && !ValidateMesh(clustered).HasDefects() {
+			return clustered
+		}
+		cell /= 2
+	}
+	return shell
+}
+
+// neighbouringShells returns the pairs of shells whose bounding boxes overlap. A clustered
+// shell stays within its bounding box, so only these pairs can cut into each other.
+func neighbouringShells(shells [][]types.Triangle) [][2]int {
+	boxes := make([]BoundingBox, len(shells))
+	for i, shell := range shells {
+		boxes[i] = CalculateBoundingBox(shell)
+	}
+	var pairs [][2]int
+	for a := range shells {
+		for b := a + 1; b < len(shells); b++ {
+			if boxes[a].Intersects(boxes[b]) {
+				pairs = append(pairs, [2]int{a, b})
+			}
+		}
+	}
+	return pairs
+}
+
+// separateShells restores the original of both shells of a neighbouring pair whose
+// clustered meshes overlap, until no clustered shell overlaps a neighbour.
+func separateShells(parts, shells [][]types.Triangle, neighbours [][2]int) [][]types.Triangle {
+	restored := make([]bool, len(parts))
+	for changed := true; changed; {
+		changed = false
+		for _, pair := range neighbours {
+			a, b := pair[0], pair[1]
+			if restored[a] && restored[b] {
+				continue
+			}
+			aBox, bBox := CalculateBoundingBox(parts[a]), CalculateBoundingBox(parts[b])
+			if !shellPenetrates(parts[a], parts[b], bBox) && !shellPenetrates(parts[b], parts[a], aBox) {
+				continue
+			}
+			for _, i := range pair {
+				parts[i], restored[i] = shells[i], true
+			}
+			changed = true
+		}
+	}
+	return parts
+}
+
+// clampCell limits the cell to the shell's extent along an axis; a flat shell keeps the cell.
+func clampCell(cell, extent float64) float64 {
+	if extent <= 0 {
+		return cell
+	}
+	return math.Min(cell, extent)
+}
+
+// dropSmallestShells drops the shells with the smallest surface area until the rest fit the target.
+func dropSmallestShells(parts [][]types.Triangle, target int) []types.Triangle {
+	areas := make([]float64, len(parts))
+	order := make([]int, len(parts))
+	for i, part := range parts {
+		areas[i] = surfaceArea(part)
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return areas[order[a]] > areas[order[b]] })
+
+	var result []types.Triangle
+	for _, i := range order {
+		if len(result)+len(parts[i]) <= target {
+			result = append(result, parts[i]...)
+		}
+	}
+	return result
+}
+
+// splitShells groups triangles into the connected shells that share welded vertices.
+func splitShells(triangles []types.Triangle) [][]types.Triangle {
+	welder := vertexWelder{}
+	indexed := make([][3]int, len(triangles))
+	for i, t := range triangles {
+		indexed[i] = [3]int{welder.weld(t.V1), welder.weld(t.V2), welder.weld(t.V3)}
+	}
+	return findShells(indexed, triangles, len(welder))
+}
+
+// countTriangles returns the number of triangles in all shells.
+func countTriangles(parts [][]types.Triangle) int {
+	count := 0
+	for _, part := range parts {
+		count += len(part)
+	}
+	return count
+}
+
+// flattenShells joins the shells into one triangle list.
+func flattenShells(parts [][]types.Triangle) []types.Triangle {
+	result := make([]types.Triangle, 0, countTriangles(parts))
+	for _, part := range parts {
+		result = append(result, part...)
+	}
+	return result
+}
+
+// surfaceArea returns the total area of the triangles.
+func surfaceArea(triangles []types.Triangle) float64 {
+	var area float64
+	for _, t := range triangles {
+		area += 0.5 * math.Sqrt(lengthSquared(triangleCross(t)))
+	}
+	return area
+}
+
+// clusterKey identifies a clustering cell.
+type clusterKey [3]int64
+
+// faceKey identifies a triangle by its cluster indices, independent of vertex order.
+type faceKey [3]int
+
+// clusterVertices snaps every vertex to its cell's representative and rebuilds the triangles.
+// The cell holds the size of the grid cells along each axis.
+func clusterVertices(triangles []types.Triangle, origin types.Point3D, cell types.Point3D) []types.Triangle {
+	type cluster struct {
+		sum   types.Point3D
+		count int
+	}
+	clusterIDs := make(map[clusterKey]int)
+	var clusters []cluster
+
+	assign := func(p types.Point3D) int {
+		key := clusterKey{
+			int64(math.Floor((p.X - origin.X) / cell.X)),
+			int64(math.Floor((p.Y - origin.Y) / cell.Y)),
+			int64(math.Floor((p.Z - origin.Z) / cell.Z)),
+		}
+		id, ok := clusterIDs[key]
+		if !ok {
+			id = len(clusters)
+			clusterIDs[key] = id
+			clusters = append(clusters, cluster{})
+		}
+		c := &clusters[id]
+		c.sum = types.Point3D{X: c.sum.X + p.X, Y: c.sum.Y + p.Y, Z: c.sum.Z + p.Z}
+		c.count++
+		return id
+	}
+
+	indexed := make([][3]int, len(triangles))
+	for i, t := range triangles {
+		indexed[i] = [3]int{assign(t.V1), assign(t.V2), assign(t.V3)}
+	}
+
+	representatives := make([]types.Point3D, len(clusters))
+	for i, c := range clusters {
+		n := float64(c.count)
+		representatives[i] = types.Point3D{X: c.sum.X / n, Y: c.sum.Y / n, Z: c.sum.Z / n}
+	}
+
+	// Faces seen with both windings cancel out: they are the shared walls of adjacent voxels
+	// or a part folded flat. A face left with a winding is kept once, in that winding, as
+	// many coplanar triangles collapse onto the same face.
+	type face struct {
+		ids     [3]int // Vertex order of the forward winding
+		winding int    // Forward minus backward occurrences
+	}
+	faces := make(map[faceKey]*face)
+	var order []faceKey
+	for _, ids := range indexed {
+		if ids[0] == ids[1] || ids[1] == ids[2] || ids[0] == ids[2] {
+			continue
+		}
+		key, forward := canonicalFace(ids)
+		f, ok := faces[key]
+		if !ok {
+			f = &face{ids: [3]int(key)}
+			faces[key] = f
+			order = append(order, key)
+		}
+		if forward {
+			f.winding++
+		} else {
+			f.winding--
+		}
+	}
+
+	result := make([]types.Triangle, 0, len(order))
+	for _, key := range order {
+		f := faces[key]
+		if f.winding == 0 {
+			continue
+		}
+		ids := f.ids
+		if f.winding < 0 {
+			ids[1], ids[2] = ids[2], ids[1]
+		}
+		v1, v2, v3 := representatives[ids[0]], representatives[ids[1]], representatives[ids[2]]
+		normal, err := calculateNormal(v1, v2, v3)
+		if err != nil {
+			continue // Collapsed to a line or point
+		}
+		result = append(result, types.Triangle{Normal: normal, V1: v1, V2: v2, V3: v3})
+	}
+	return result
+}
+
+// canonicalFace returns a vertex-order independent key for a triangle and its winding parity.
+func canonicalFace(ids [3]int) (faceKey, bool) {
+	// Rotate so the smallest index comes first; the winding is then the order of the other two
+	for ids[0] > ids[1] || ids[0] > ids[2] {
+		ids = [3]int{ids[1], ids[2], ids[0]}
+	}
+	if ids[1] < ids[2] {
+		return faceKey{ids[0], ids[1], ids[2]}, true
+	}
+	return faceKey{ids[0], ids[2], ids[1]}, false
+}
+
+// lengthSquared returns the squared magnitude of a vector.
+func lengthSquared(v types.Point3D) float64 {
+	return v.X*v.X + v.Y*v.Y + v.Z*v.Z
+}
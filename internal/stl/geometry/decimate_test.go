@@ -0,0 +1,116 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// voxelGrid builds an n x n plate of unit voxel cubes, like rasterized text.
+func voxelGrid(t *testing.T, n int) []types.Triangle {
+	t.Helper()
+	var triangles []types.Triangle
+	for x := 0; x < n; x++ {
+		for y := 0; y < n; y++ {
+			cube, err := CreateCube(float64(x), float64(y), 0, 1, 1, 1)
+			if err != nil {
+				t.Fatalf("CreateCube() error = %v", err)
+			}
+			triangles = append(triangles, cube...)
+		}
+	}
+	return triangles
+}
+
+func TestDecimate(t *testing.T) {
+	grid := voxelGrid(t, 20)
+	original := CalculateBoundingBox(grid)
+
+	tests := []struct {
+		name   string
+		target int
+	}{
+		{"half", len(grid) / 2},
+		{"tenth", len(grid) / 10},
+		{"tiny", 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Decimate(grid, tt.target)
+			if len(got) == 0 || len(got) > tt.target {
+				t.Fatalf("Decimate() returned %d triangles, want 1..%d", len(got), tt.target)
+			}
+
+			box := CalculateBoundingBox(got)
+			size, want := box.Size(), original.Size()
+			if math.Abs(size.X-want.X) > want.X/2 || math.Abs(size.Y-want.Y) > want.Y/2 {
+				t.Errorf("bounding box %+v drifted too far from %+v", size, want)
+			}
+
+			if report := ValidateMesh(got); report.HasDefects() {
+				t.Errorf("decimated mesh has defects: %v", report.Defects())
+			}
+
+			for i, tri := range got {
+				computed, err := calculateNormal(tri.V1, tri.V2, tri.V3)
+				if err != nil {
+					t.Fatalf("triangle %d is degenerate: %v", i, err)
+				}
+				if !pointsClose(computed, tri.Normal) {
+					t.Errorf("triangle %d normal %+v disagrees with winding %+v", i, tri.Normal, computed)
+				}
+			}
+		})
+	}
+}
+
+// TestDecimateText verifies decimated text of many separate shells stays a valid mesh
+// and uses most of the budget rather than landing far below it.
+func TestDecimateText(t *testing.T) {
+	placed, err := LayoutText(DefaultTextBlocks("testuser", "2023", "Hello, skyline"), layoutWidth, layoutDepth, layoutHeight, TextFonts{})
+	if err != nil {
+		t.Fatalf("LayoutText() error = %v", err)
+	}
+	var text []types.Triangle
+	for _, block := range placed {
+		text = append(text, block.Triangles...)
+	}
+
+	for _, fraction := range []int{2, 4, 8} {
+		target := len(text) / fraction
+		got := Decimate(text, target)
+		if len(got) > target || len(got) < target*9/10 {
+			t.Errorf("Decimate() to 1/%d returned %d triangles, want %d..%d", fraction, len(got), target*9/10, target)
+		}
+		if report := ValidateMesh(got); report.HasDefects() {
+			t.Errorf("Decimate() to 1/%d has defects: %v", fraction, report.Defects())
+		}
+	}
+}
+
+func TestDecimateWithinTarget(t *testing.T) {
+	grid := voxelGrid(t, 3)
+
+	if got := Decimate(grid, len(grid)); len(got) != len(grid) {
+		t.Errorf("Decimate() at target changed triangle count to %d", len(got))
+	}
+	if got := Decimate(grid, 0); len(got) != len(grid) {
+		t.Errorf("Decimate() with no target changed triangle count to %d", len(got))
+	}
+	if got := Decimate(nil, 10); len(got) != 0 {
+		t.Errorf("Decimate(nil) = %d triangles, want 0", len(got))
+	}
+}
+
+func TestCanonicalFace(t *testing.T) {
+	forward, fw := canonicalFace([3]int{3, 1, 2})
+	backward, bw := canonicalFace([3]int{2, 1, 3})
+	if forward != backward {
+		t.Errorf("canonicalFace() keys differ: %v vs %v", forward, backward)
+	}
+	if fw == bw {
+		t.Error("canonicalFace() should report opposite windings")
+	}
+}
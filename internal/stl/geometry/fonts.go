@@ -0,0 +1,341 @@
+package geometry
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// CJKFallbackFonts lists system fonts, by file name, that are tried in order when
+// text contains glyphs missing from the requested and embedded fonts.
+var CJKFallbackFonts = []string{
+	"NotoSansKR-Medium.otf",
+	"NotoSansKR-Regular.otf",
+	"NotoSansCJKkr-Medium.otf",
+	"NotoSansCJKkr-Regular.otf",
+	"NotoSansCJK-Medium.ttc#1",
+	"NotoSansCJK-Regular.ttc#1",
+	"NanumGothicBold.ttf",
+	"NanumGothic.ttf",
+	"malgunbd.ttf",
+	"malgun.ttf",
+	"AppleSDGothicNeo.ttc",
+	"NotoSansCJKjp-Regular.otf",
+	"NotoSansCJKsc-Regular.otf",
+	"msyh.ttc",
+	"YuGothM.ttc",
+}
+
+// fontExtensions are the file extensions recognised when indexing font directories.
+var fontExtensions = map[string]bool{".ttf": true, ".otf": true, ".ttc": true, ".otc": true}
+
+// Font is a parsed TrueType or OpenType font.
+type Font struct {
+	Name string // Name or path the font was loaded from
+	font *sfnt.Font
+}
+
+// HasGlyph reports whether the font contains a glyph for r.
+func (f *Font) HasGlyph(r rune) bool {
+	var buf sfnt.Buffer
+	index, err := f.font.GlyphIndex(&buf, r)
+	return err == nil && index != 0
+}
+
+// FontChain is an ordered list of fonts. Each rune is drawn with the first font
+// that contains a glyph for it.
+type FontChain []*Font
+
+// Missing returns the distinct runes of text that no font in the chain can draw.
+// Whitespace is never reported.
+func (c FontChain) Missing(text string) []rune {
+	var missing []rune
+	seen := make(map[rune]bool)
+	for _, r := range text {
+		if seen[r] || strings.ContainsRune(" \t\n\r", r) {
+			continue
+		}
+		seen[r] = true
+		if c.fontFor(r) < 0 {
+			missing = append(missing, r)
+		}
+	}
+	return missing
+}
+
+// fontFor returns the index of the first font that can draw r, or -1.
+func (c FontChain) fontFor(r rune) int {
+	for i, f := range c {
+		if f.HasGlyph(r) {
+			return i
+		}
+	}
+	return -1
+}
+
+// NewFace returns a face of the given size (in points at 72 DPI, matching gg) that
+// falls back through the chain glyph by glyph.
+func (c FontChain) NewFace(size float64) (font.Face, error) {
+	if len(c) == 0 {
+		return nil, errors.New(errors.ValidationError, "font chain is empty", nil)
+	}
+	face := &chainFace{chain: c, faces: make([]font.Face, len(c)), lookup: make(map[rune]int)}
+	for i, f := range c {
+		fontFace, err := opentype.NewFace(f.font, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingNone})
+		if err != nil {
+			return nil, errors.New(errors.IOError, "failed to create font face for "+f.Name, err)
+		}
+		face.faces[i] = fontFace
+	}
+	return face, nil
+}
+
+// chainFace implements font.Face over a font chain. Like the faces it wraps, it is
+// not safe for concurrent use.
+type chainFace struct {
+	chain  FontChain
+	faces  []font.Face
+	lookup map[rune]int
+}
+
+// faceFor returns the face used to draw r. Runes no font can draw use the primary
+// face, which renders its .notdef glyph.
+func (f *chainFace) faceFor(r rune) font.Face {
+	i, ok := f.lookup[r]
+	if !ok {
+		i = f.chain.fontFor(r)
+		if i < 0 {
+			i = 0
+		}
+		f.lookup[r] = i
+	}
+	return f.faces[i]
+}
+
+func (f *chainFace) Close() error {
+	for _, face := range f.faces {
+		if err := face.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *chainFace) Glyph(dot fixed.Point26_6, r rune) (image.Rectangle, image.Image, image.Point, fixed.Int26_6, bool) {
+	return f.faceFor(r).Glyph(dot, r)
+}
+
+func (f *chainFace) GlyphBounds(r rune) (fixed.Rectangle26_6, fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphBounds(r)
+}
+
+func (f *chainFace) GlyphAdvance(r rune) (fixed.Int26_6, bool) {
+	return f.faceFor(r).GlyphAdvance(r)
+}
+
+// Kern only applies between runes drawn from the same font.
+func (f *chainFace) Kern(r0, r1 rune) fixed.Int26_6 {
+	if face := f.faceFor(r0); face == f.faceFor(r1) {
+		return face.Kern(r0, r1)
+	}
+	return 0
+}
+
+// Metrics returns the primary font's metrics so line layout does not depend on
+// which fallback fonts happen to be installed.
+func (f *chainFace) Metrics() font.Metrics {
+	return f.faces[0].Metrics()
+}
+
+// FontRegistry loads fonts from the embedded assets, user font directories and the
+// system font directories, caching parsed fonts. It is safe for concurrent use.
+type FontRegistry struct {
+	dirs []string
+
+	mu    sync.Mutex
+	fonts map[string]*Font
+	index map[string]string // Lower-case file name (with and without extension) to path
+}
+
+// NewFontRegistry returns a registry that searches dirs before the system font directories.
+func NewFontRegistry(dirs ...string) *FontRegistry {
+	return &FontRegistry{
+		dirs:  append(append([]string{}, dirs...), SystemFontDirs()...),
+		fonts: make(map[string]*Font),
+	}
+}
+
+var (
+	defaultRegistry     *FontRegistry
+	defaultRegistryOnce sync.Once
+)
+
+// DefaultFontRegistry returns the shared registry that searches the system font directories.
+func DefaultFontRegistry() *FontRegistry {
+	defaultRegistryOnce.Do(func() { defaultRegistry = NewFontRegistry() })
+	return defaultRegistry
+}
+
+// SystemFontDirs returns the platform's usual font directories.
+func SystemFontDirs() []string {
+	home, _ := os.UserHomeDir()
+	switch runtime.GOOS {
+	case "windows":
+		dirs := []string{filepath.Join(os.Getenv("WINDIR"), "Fonts")}
+		if local := os.Getenv("LOCALAPPDATA"); local != "" {
+			dirs = append(dirs, filepath.Join(local, "Microsoft", "Windows", "Fonts"))
+		}
+		return dirs
+	case "darwin":
+		return []string{"/System/Library/Fonts", "/Library/Fonts", filepath.Join(home, "Library", "Fonts")}
+	default:
+		return []string{"/usr/share/fonts", "/usr/local/share/fonts", filepath.Join(home, ".local", "share", "fonts"), filepath.Join(home, ".fonts")}
+	}
+}
+
+// Load returns the named font. The name is either a path to a TTF, OTF or TTC file,
+// the file name of an embedded font, or the file name (extension optional) of a font
+// in the registry's directories. A "#N" suffix selects the N-th font of a collection.
+func (r *FontRegistry) Load(name string) (*Font, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f, ok := r.fonts[name]; ok {
+		return f, nil
+	}
+
+	file, index := name, 0
+	if i := strings.LastIndex(name, "#"); i >= 0 {
+		if n, err := strconv.Atoi(name[i+1:]); err == nil && n >= 0 {
+			file, index = name[:i], n
+		}
+	}
+
+	data, err := r.readFont(file)
+	if err != nil {
+		return nil, err
+	}
+
+	collection, err := opentype.ParseCollection(data)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to parse font "+name, err)
+	}
+	if index >= collection.NumFonts() {
+		return nil, errors.New(errors.ValidationError, "font collection index out of range in "+name, nil)
+	}
+	parsed, err := collection.Font(index)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to parse font "+name, err)
+	}
+
+	f := &Font{Name: name, font: parsed}
+	r.fonts[name] = f
+	return f, nil
+}
+
+// readFont finds and reads the font file for name. The caller must hold r.mu.
+func (r *FontRegistry) readFont(name string) ([]byte, error) {
+	if _, err := os.Stat(name); err == nil {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, errors.New(errors.IOError, "failed to read font file", err)
+		}
+		return data, nil
+	}
+
+	if data, err := embeddedAssets.ReadFile("assets/" + name); err == nil {
+		return data, nil
+	}
+
+	if strings.ContainsAny(name, `/\`) {
+		return nil, errors.New(errors.IOError, "font file not found: "+name, nil)
+	}
+
+	if r.index == nil {
+		r.index = indexFontDirs(r.dirs)
+	}
+	path, ok := r.index[strings.ToLower(name)]
+	if !ok {
+		return nil, errors.New(errors.IOError, "font not found: "+name, nil)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to read font file", err)
+	}
+	return data, nil
+}
+
+// indexFontDirs maps the lower-case file names of all fonts under dirs to their paths.
+// Earlier directories take precedence.
+func indexFontDirs(dirs []string) map[string]string {
+	index := make(map[string]string)
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return nil // Skip unreadable entries and missing directories
+			}
+			ext := strings.ToLower(filepath.Ext(path))
+			if !fontExtensions[ext] {
+				return nil
+			}
+			base := strings.ToLower(d.Name())
+			for _, key := range []string{base, strings.TrimSuffix(base, ext)} {
+				if _, exists := index[key]; !exists {
+					index[key] = path
+				}
+			}
+			return nil
+		})
+	}
+	return index
+}
+
+// Chain builds the font chain for text: the requested fonts first, then the embedded
+// fonts, then as many CJKFallbackFonts as are needed to cover the text. Requested
+// fonts must exist; missing fallback fonts are skipped.
+func (r *FontRegistry) Chain(text string, names ...string) (FontChain, error) {
+	var chain FontChain
+	for _, name := range names {
+		f, err := r.Load(name)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, f)
+	}
+
+	for _, name := range []string{PrimaryFont, FallbackFont} {
+		if f, err := r.Load(name); err == nil {
+			chain = append(chain, f)
+		}
+	}
+	if len(chain) == 0 {
+		return nil, errors.New(errors.IOError, "failed to load any fonts", nil)
+	}
+
+	for _, name := range CJKFallbackFonts {
+		if len(chain.Missing(text)) == 0 {
+			break
+		}
+		f, err := r.Load(name)
+		if err != nil {
+			continue
+		}
+		for _, missing := range chain.Missing(text) {
+			if f.HasGlyph(missing) {
+				chain = append(chain, f)
+				break
+			}
+		}
+	}
+	return chain, nil
+}
@@ -0,0 +1,174 @@
+package geometry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// writeGoFont writes the Go Regular font, which covers Greek and Cyrillic unlike
+// Mona Sans, into a new font directory and returns the directory.
+func writeGoFont(t *testing.T, name string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, name), goregular.TTF, 0o600); err != nil {
+		t.Fatalf("failed to write font: %v", err)
+	}
+	return dir
+}
+
+// TestFontRegistryLoad verifies fonts are found by path, embedded name and directory name.
+func TestFontRegistryLoad(t *testing.T) {
+	dir := writeGoFont(t, "GoRegular.ttf")
+	registry := NewFontRegistry(dir)
+
+	tests := []struct {
+		name    string
+		font    string
+		wantErr bool
+	}{
+		{"embedded font", PrimaryFont, false},
+		{"path", filepath.Join(dir, "GoRegular.ttf"), false},
+		{"file name in directory", "GoRegular.ttf", false},
+		{"case-insensitive name without extension", "goregular", false},
+		{"collection index", "GoRegular.ttf#0", false},
+		{"collection index out of range", "GoRegular.ttf#3", true},
+		{"unknown font", "NoSuchFont.ttf", true},
+		{"missing path", filepath.Join(dir, "missing.ttf"), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := registry.Load(tt.font)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load(%q) error = %v, wantErr %v", tt.font, err, tt.wantErr)
+			}
+			if !tt.wantErr && !f.HasGlyph('A') {
+				t.Errorf("Load(%q) returned a font without Latin glyphs", tt.font)
+			}
+		})
+	}
+
+	t.Run("invalid font data", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "broken.ttf")
+		if err := os.WriteFile(path, []byte("not a font"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := registry.Load(path); err == nil {
+			t.Error("Load() expected error for invalid font data")
+		}
+	})
+}
+
+// TestFontChain verifies glyph-by-glyph fallback through the chain.
+func TestFontChain(t *testing.T) {
+	registry := NewFontRegistry(writeGoFont(t, "GoRegular.ttf"))
+
+	embedded, err := registry.Chain("Mona Ωλ")
+	if err != nil {
+		t.Fatalf("Chain() error = %v", err)
+	}
+	if missing := embedded.Missing("Mona Ωλ Ω"); string(missing) != "Ωλ" {
+		t.Errorf("Missing() = %q, want %q", string(missing), "Ωλ")
+	}
+
+	chain := append(embedded, mustLoadFont(t, registry, "GoRegular.ttf"))
+	if missing := chain.Missing("Mona Ωλ"); len(missing) != 0 {
+		t.Errorf("Missing() = %q, want none", string(missing))
+	}
+
+	face, err := chain.NewFace(20)
+	if err != nil {
+		t.Fatalf("NewFace() error = %v", err)
+	}
+	defer face.Close()
+
+	for _, r := range "MΩ" {
+		if advance, ok := face.GlyphAdvance(r); !ok || advance <= 0 {
+			t.Errorf("GlyphAdvance(%q) = %v, %v; want a positive advance", r, advance, ok)
+		}
+	}
+	if face.Metrics() != mustFace(t, embedded).Metrics() {
+		t.Error("Metrics() should come from the primary font")
+	}
+
+	if _, err := (FontChain{}).NewFace(20); err == nil {
+		t.Error("NewFace() expected error for empty chain")
+	}
+}
+
+// TestChainRequestedFonts verifies requested fonts come first and must exist.
+func TestChainRequestedFonts(t *testing.T) {
+	registry := NewFontRegistry(writeGoFont(t, "GoRegular.ttf"))
+
+	chain, err := registry.Chain("Жλ", "GoRegular")
+	if err != nil {
+		t.Fatalf("Chain() error = %v", err)
+	}
+	if chain[0].Name != "GoRegular" || len(chain.Missing("Жλ")) != 0 {
+		t.Errorf("Chain() should start with the requested font and cover the text, got %q", chain[0].Name)
+	}
+
+	if _, err := registry.Chain("Mona", "NoSuchFont"); err == nil {
+		t.Error("Chain() expected error for a missing requested font")
+	}
+}
+
+// TestRenderTextWithFallback verifies glyphs missing from Mona Sans are drawn from the fallback font.
+func TestRenderTextWithFallback(t *testing.T) {
+	registry := NewFontRegistry(writeGoFont(t, "GoRegular.ttf"))
+
+	embedded, err := registry.Chain("λλλ")
+	if err != nil {
+		t.Fatalf("Chain() error = %v", err)
+	}
+	withFallback := append(append(FontChain{}, embedded...), mustLoadFont(t, registry, "GoRegular.ttf"))
+
+	slot := TextSlots(200, 20, 10)[0]
+	block := TextBlock{Slot: slot.Name, Text: "λλλ"}
+	fallback, err := renderBlock(block, slot, withFallback, 10, 200, 20)
+	if err != nil {
+		t.Fatalf("renderBlock() error = %v", err)
+	}
+	// Without a covering font the primary font's .notdef box is drawn instead
+	notdef, err := renderBlock(block, slot, embedded, 10, 200, 20)
+	if err != nil {
+		t.Fatalf("renderBlock() error = %v", err)
+	}
+	if len(fallback.Triangles) == 0 || len(fallback.Triangles) == len(notdef.Triangles) {
+		t.Errorf("expected fallback glyphs to differ from .notdef boxes: %d vs %d triangles", len(fallback.Triangles), len(notdef.Triangles))
+	}
+}
+
+// TestTextFontsValidate verifies named fonts are checked up front.
+func TestTextFontsValidate(t *testing.T) {
+	registry := NewFontRegistry(writeGoFont(t, "GoRegular.ttf"))
+
+	if err := (TextFonts{Registry: registry, Top: []string{"GoRegular"}}).Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := (TextFonts{Registry: registry, Right: []string{"Missing.ttf"}}).Validate(); err == nil {
+		t.Error("Validate() expected error for missing font")
+	}
+}
+
+func mustLoadFont(t *testing.T, registry *FontRegistry, name string) *Font {
+	t.Helper()
+	f, err := registry.Load(name)
+	if err != nil {
+		t.Fatalf("Load(%q) error = %v", name, err)
+	}
+	return f
+}
+
+func mustFace(t *testing.T, chain FontChain) font.Face {
+	t.Helper()
+	face, err := chain.NewFace(20)
+	if err != nil {
+		t.Fatalf("NewFace() error = %v", err)
+	}
+	return face
+}
@@ -64,28 +64,36 @@ func NormalizeContribution(count, maxCount int) float64 {
 
 // CreateContributionGeometry generates geometry for a single year's contributions
 func CreateContributionGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) ([]types.Triangle, error) {
-	var triangles []types.Triangle
-
-	// Base Y offset includes padding and positions each year accordingly
-	baseYOffset := 2*CellSize + float64(yearIndex)*7*CellSize
+	geometry, err := CreateMarkedContributionGeometry(contributions, yearIndex, maxContrib, nil)
+	if err != nil {
+		return nil, err
+	}
+	return geometry.Columns, nil
+}
 
+// ContributionColumnBounds returns the bounding box of every column that
+// CreateContributionGeometry generates for the same arguments.
+func ContributionColumnBounds(contributions [][]types.ContributionDay, yearIndex int, maxContrib int) []BoundingBox {
+	var bounds []BoundingBox
 	for weekIdx, week := range contributions {
 		for dayIdx, day := range week {
 			if day.ContributionCount > 0 {
-				height := NormalizeContribution(day.ContributionCount, maxContrib)
-				x := 2*CellSize + float64(weekIdx)*CellSize
-				y := baseYOffset + float64(dayIdx)*CellSize
-
-				columnTriangles, err := CreateColumn(x, y, height, CellSize)
-				if err != nil {
-					return nil, err
-				}
-				triangles = append(triangles, columnTriangles...)
+				x, y := columnPosition(weekIdx, dayIdx, yearIndex)
+				bounds = append(bounds, BoundingBox{
+					Min: types.Point3D{X: x, Y: y, Z: 0},
+					Max: types.Point3D{X: x + CellSize, Y: y + CellSize, Z: NormalizeContribution(day.ContributionCount, maxContrib)},
+				})
 			}
 		}
 	}
+	return bounds
+}
 
-	return triangles, nil
+// columnPosition returns the front left corner of a contribution column.
+// The base Y offset includes padding and positions each year accordingly.
+func columnPosition(weekIdx, dayIdx, yearIndex int) (float64, float64) {
+	baseYOffset := 2*CellSize + float64(yearIndex)*7*CellSize
+	return 2*CellSize + float64(weekIdx)*CellSize, baseYOffset + float64(dayIdx)*CellSize
 }
 
 // CalculateMultiYearDimensions calculates dimensions for multiple years
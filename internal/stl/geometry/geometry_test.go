@@ -37,6 +37,30 @@ func TestNormalizeContribution(t *testing.T) {
 }
 
 // TestCreateContributionGeometry verifies contribution geometry generation
+// TestContributionColumnBounds verifies the column bounds match the generated columns.
+func TestContributionColumnBounds(t *testing.T) {
+	contribs := [][]types.ContributionDay{
+		{{ContributionCount: 5}, {ContributionCount: 0}},
+		{{ContributionCount: 0}, {ContributionCount: 10}},
+	}
+
+	bounds := ContributionColumnBounds(contribs, 1, 10)
+	triangles, err := CreateContributionGeometry(contribs, 1, 10)
+	if err != nil {
+		t.Fatalf("CreateContributionGeometry() error = %v", err)
+	}
+	if len(bounds) != 2 || len(triangles) != 24 {
+		t.Fatalf("got %d bounds for %d triangles, want 2 bounds for 24 triangles", len(bounds), len(triangles))
+	}
+
+	for i, b := range bounds {
+		column := CalculateBoundingBox(triangles[i*12 : (i+1)*12])
+		if !pointsClose(b.Min, column.Min) || !pointsClose(b.Max, column.Max) {
+			t.Errorf("bounds %d = %+v, want %+v", i, b, column)
+		}
+	}
+}
+
 func TestCreateContributionGeometry(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,462 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/fogleman/gg"
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/logger"
+	"github.com/github/gh-skyline/internal/types"
+	"golang.org/x/image/font"
+)
+
+// Face identifies an outer face of the base.
+type Face string
+
+// Faces of the base. Left and right are as seen from the front.
+const (
+	FaceFront Face = "front"
+	FaceBack  Face = "back"
+	FaceLeft  Face = "left"
+	FaceRight Face = "right"
+	FaceTop   Face = "top"
+)
+
+// Align is the horizontal alignment of text within its slot.
+type Align string
+
+// Supported text alignments.
+const (
+	AlignLeft   Align = "left"
+	AlignCenter Align = "center"
+	AlignRight  Align = "right"
+)
+
+// Names of the text slots on the base.
+const (
+	SlotFrontLeft   = "front-left"
+	SlotFrontCenter = "front-center"
+	SlotFrontRight  = "front-right"
+	SlotTopBand     = "top-band" // Margin strip along the front edge of the top face
+	SlotTopBack     = "top-back" // Margin strip along the back edge of the top face
+	SlotBack        = "back"
+	SlotLeft        = "left"
+	SlotRight       = "right"
+
+	// SlotRowPrefix starts the names of the row slots "row-1", "row-2", ...: one per
+	// row of the contribution grid, from the back, in the margin along the left edge
+	// of the top face.
+	SlotRowPrefix = "row-"
+)
+
+const (
+	slotPadding       = 0.5   // Space kept free around text inside a slot (mm)
+	lineSpacing       = 1.0   // Multiple of the font's line height between lines
+	referenceFontSize = 100.0 // Font size (px) used to measure text before fitting it
+
+	// Horizontal extent of the front slots as fractions of the base width, leaving
+	// room for the logo at the left edge.
+	frontSlotStart = 0.1
+	frontSlotEnd   = 0.97
+)
+
+// Slot is a named rectangular area of a base face that text is laid out in.
+// X and Y are measured in mm from the face's top-left corner as seen from outside
+// the model; Y points down (on the top face, towards the front edge).
+type Slot struct {
+	Name   string
+	Face   Face
+	X, Y   float64
+	Width  float64
+	Height float64
+	Align  Align // Alignment used when a text block does not choose one
+}
+
+// TextSlots returns the text slots of a base with the given dimensions.
+func TextSlots(baseWidth, baseDepth, baseHeight float64) []Slot {
+	margin := 2 * CellSize // Space between the base edge and the contribution grid
+	frontStart, frontEnd := frontSlotStart*baseWidth, frontSlotEnd*baseWidth
+	frontMiddle := baseWidth / 2
+
+	slots := []Slot{
+		{Name: SlotFrontLeft, Face: FaceFront, X: frontStart, Width: frontMiddle - frontStart, Height: baseHeight, Align: AlignLeft},
+		{Name: SlotFrontCenter, Face: FaceFront, X: baseWidth * 0.3, Width: baseWidth * 0.4, Height: baseHeight, Align: AlignCenter},
+		{Name: SlotFrontRight, Face: FaceFront, X: frontMiddle, Width: frontEnd - frontMiddle, Height: baseHeight, Align: AlignRight},
+		{Name: SlotTopBand, Face: FaceTop, X: margin, Y: baseDepth - margin, Width: baseWidth - 2*margin, Height: margin, Align: AlignCenter},
+		{Name: SlotTopBack, Face: FaceTop, X: margin, Width: baseWidth - 2*margin, Height: margin, Align: AlignCenter},
+		{Name: SlotBack, Face: FaceBack, Width: baseWidth, Height: baseHeight, Align: AlignCenter},
+		{Name: SlotLeft, Face: FaceLeft, Width: baseDepth, Height: baseHeight, Align: AlignCenter},
+		{Name: SlotRight, Face: FaceRight, Width: baseDepth, Height: baseHeight, Align: AlignCenter},
+	}
+	rows := int(math.Round((baseDepth - 2*margin) / YearOffset))
+	for row := 0; row < rows; row++ {
+		slots = append(slots, Slot{Name: RowSlot(row), Face: FaceTop, Y: margin + float64(row)*YearOffset, Width: margin, Height: YearOffset, Align: AlignCenter})
+	}
+	return slots
+}
+
+// RowSlot returns the name of the slot beside the row of the contribution grid
+// with the given index, counted from the back.
+func RowSlot(row int) string {
+	return SlotRowPrefix + strconv.Itoa(row+1)
+}
+
+// SlotFace returns the face of the base the named slot lies on.
+func SlotFace(name string) (Face, error) {
+	if row, ok := strings.CutPrefix(name, SlotRowPrefix); ok {
+		if n, err := strconv.Atoi(row); err == nil && n > 0 {
+			return FaceTop, nil
+		}
+	}
+	slot, err := findSlot(TextSlots(0, 0, 0), name)
+	return slot.Face, err
+}
+
+// findSlot returns the slot with the given name.
+func findSlot(slots []Slot, name string) (Slot, error) {
+	names := make([]string, 0, len(slots))
+	for _, slot := range slots {
+		if slot.Name == name {
+			return slot, nil
+		}
+		names = append(names, slot.Name)
+	}
+	return Slot{}, errors.New(errors.ValidationError, fmt.Sprintf("unknown text slot %q (expected one of %s)", name, strings.Join(names, ", ")), nil)
+}
+
+// TextBlock is a piece of text placed in a slot.
+type TextBlock struct {
+	Slot     string   // Name of the slot, e.g. "top-band"
+	Text     string   // Text to emboss; "\n" starts a new line
+	Align    Align    // Alignment within the slot (default: the slot's alignment)
+	Rotation float64  // Counter-clockwise rotation in degrees, as seen from outside
+	Size     float64  // Font size (em) in mm; zero fits the text to the slot
+	Depth    float64  // Emboss depth in mm (default 1)
+	Fonts    []string // Fonts tried before the model's fonts for this slot
+}
+
+// ParseTextSpec parses a text block specification of the form
+//
+//	slot[,align=center][,rotate=0][,size=mm][,depth=1][,font=name]:text
+//
+// A literal "\n" in the text starts a new line.
+func ParseTextSpec(spec string) (TextBlock, error) {
+	settings, text, ok := strings.Cut(spec, ":")
+	if !ok {
+		return TextBlock{}, errors.New(errors.ValidationError, "invalid text specification (expected slot:text)", nil)
+	}
+
+	parts := strings.Split(settings, ",")
+	block := TextBlock{
+		Slot: strings.ToLower(strings.TrimSpace(parts[0])),
+		Text: strings.ReplaceAll(text, `\n`, "\n"),
+	}
+
+	for _, part := range parts[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return TextBlock{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid text setting %q (expected key=value)", part), nil)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "align":
+			block.Align = Align(strings.ToLower(value))
+			continue
+		case "font":
+			block.Fonts = append(block.Fonts, value)
+			continue
+		}
+
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return TextBlock{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid value for text setting %q", key), err)
+		}
+		switch key {
+		case "rotate", "rotation":
+			block.Rotation = number
+		case "size":
+			block.Size = number
+		case "depth":
+			block.Depth = number
+		default:
+			return TextBlock{}, errors.New(errors.ValidationError, fmt.Sprintf("unknown text setting %q", key), nil)
+		}
+	}
+
+	return block, block.Validate()
+}
+
+// Validate checks the block's settings and slot name. Whether a row slot exists
+// depends on the model, so that is checked when the block is laid out.
+func (b TextBlock) Validate() error {
+	if b.Slot == "" {
+		return errors.New(errors.ValidationError, "text slot cannot be empty", nil)
+	}
+	if _, err := SlotFace(b.Slot); err != nil {
+		return err
+	}
+	switch b.Align {
+	case "", AlignLeft, AlignCenter, AlignRight:
+	default:
+		return errors.New(errors.ValidationError, fmt.Sprintf("unknown text alignment %q", b.Align), nil)
+	}
+	if b.Size < 0 || b.Depth < 0 {
+		return errors.New(errors.ValidationError, "text size and depth cannot be negative", nil)
+	}
+	if math.IsNaN(b.Rotation) || math.IsInf(b.Rotation, 0) {
+		return errors.New(errors.ValidationError, "text rotation must be a finite number", nil)
+	}
+	return nil
+}
+
+// DefaultTextBlocks returns the standard text layout: the username on the front left,
+// the year on the front right and the optional top text in the band along the front
+// edge of the top face, clear of the contribution columns. Empty texts are left out.
+func DefaultTextBlocks(username, year, topText string) []TextBlock {
+	var blocks []TextBlock
+	for _, block := range []TextBlock{
+		{Slot: SlotFrontLeft, Text: username},
+		{Slot: SlotFrontRight, Text: year},
+		{Slot: SlotTopBand, Text: topText},
+	} {
+		if block.Text != "" {
+			blocks = append(blocks, block)
+		}
+	}
+	return blocks
+}
+
+// RowLabelBlocks returns a block for each label in the slot beside the grid row
+// with the same index, counted from the back like RowSlot. Empty labels are left out.
+func RowLabelBlocks(labels []string) []TextBlock {
+	var blocks []TextBlock
+	for row, label := range labels {
+		if label != "" {
+			blocks = append(blocks, TextBlock{Slot: RowSlot(row), Text: label, Rotation: 90})
+		}
+	}
+	return blocks
+}
+
+// PlacedText is the geometry of a text block after layout.
+type PlacedText struct {
+	Block     TextBlock
+	Slot      Slot
+	FontSize  float64     // Font size (em) in mm
+	Bounds    BoundingBox // Bounds of the embossed text
+	Triangles []types.Triangle
+}
+
+// LayoutText lays out text blocks on a base of the given dimensions and returns the
+// geometry of each block. Blocks with empty text are skipped.
+func LayoutText(blocks []TextBlock, baseWidth, baseDepth, baseHeight float64, fonts TextFonts) ([]PlacedText, error) {
+	slots := TextSlots(baseWidth, baseDepth, baseHeight)
+	// Match the voxel density of the logo and front face text
+	pixelsPerMM := float64(baseWidthVoxelResolution) / baseWidth
+
+	var placed []PlacedText
+	for _, block := range blocks {
+		if strings.TrimSpace(block.Text) == "" {
+			continue
+		}
+		if err := block.Validate(); err != nil {
+			return nil, err
+		}
+		slot, err := findSlot(slots, block.Slot)
+		if err != nil {
+			return nil, err
+		}
+
+		names := block.Fonts
+		switch slot.Face {
+		case FaceTop:
+			names = append(append([]string{}, names...), fonts.Top...)
+		case FaceFront:
+			if slot.Name == SlotFrontRight {
+				names = append(append([]string{}, names...), fonts.Right...)
+			}
+		}
+		chain, err := fonts.chain(block.Text, names)
+		if err != nil {
+			return nil, err
+		}
+
+		text, err := renderBlock(block, slot, chain, pixelsPerMM, baseWidth, baseDepth)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to lay out text in slot %s", slot.Name))
+		}
+		placed = append(placed, text)
+	}
+	return placed, nil
+}
+
+// renderBlock rasterizes a text block in its slot and embosses the active pixels.
+func renderBlock(block TextBlock, slot Slot, fonts FontChain, pixelsPerMM, baseWidth, baseDepth float64) (PlacedText, error) {
+	// Round down so no pixel extends past the edge of the slot
+	width := int(slot.Width * pixelsPerMM)
+	height := int(slot.Height * pixelsPerMM)
+	padding := slotPadding * pixelsPerMM
+	availableWidth := float64(width) - 2*padding
+	availableHeight := float64(height) - 2*padding
+	if availableWidth <= 0 || availableHeight <= 0 {
+		return PlacedText{}, errors.New(errors.ValidationError, "slot is too small for text", nil)
+	}
+
+	align := block.Align
+	if align == "" {
+		align = slot.Align
+	}
+	depth := block.Depth
+	if depth == 0 {
+		depth = voxelDepth
+	}
+	angle := block.Rotation * math.Pi / 180
+
+	dc := gg.NewContext(width, height)
+	dc.SetRGB(0, 0, 0)
+	dc.Clear()
+	dc.SetRGB(1, 1, 1)
+
+	// Measure the text at a reference size; text size scales linearly with font size
+	face, err := fonts.NewFace(referenceFontSize)
+	if err != nil {
+		return PlacedText{}, errors.New(errors.IOError, "failed to load font", err)
+	}
+	dc.SetFontFace(face)
+	lines := strings.Split(block.Text, "\n")
+	_, textWidth, textHeight := measureLines(dc, face, lines)
+	if textWidth <= 0 || textHeight <= 0 {
+		return PlacedText{}, errors.New(errors.ValidationError, "text has no measurable extent", nil)
+	}
+	rotatedWidth := math.Abs(textWidth*math.Cos(angle)) + math.Abs(textHeight*math.Sin(angle))
+	rotatedHeight := math.Abs(textWidth*math.Sin(angle)) + math.Abs(textHeight*math.Cos(angle))
+	fit := math.Min(availableWidth/rotatedWidth, availableHeight/rotatedHeight)
+
+	scale := fit
+	if block.Size > 0 {
+		scale = block.Size * pixelsPerMM / referenceFontSize
+		if scale > fit*1.001 {
+			if err := logger.GetLogger().Warning("Text %q does not fit in slot %s at %.1f mm and will be cut off; the largest size that fits is %.1f mm",
+				block.Text, slot.Name, block.Size, fit*referenceFontSize/pixelsPerMM); err != nil {
+				return PlacedText{}, err
+			}
+		}
+	}
+
+	face, err = fonts.NewFace(referenceFontSize * scale)
+	if err != nil {
+		return PlacedText{}, errors.New(errors.IOError, "failed to load font", err)
+	}
+	dc.SetFontFace(face)
+	lineWidths, textWidth, textHeight := measureLines(dc, face, lines)
+
+	// Place the centre of the rotated text so its bounding box honours the alignment
+	alignment := 0.5
+	centerX := float64(width) / 2
+	switch align {
+	case AlignLeft:
+		alignment = 0
+		centerX = padding + rotatedWidth*scale/2
+	case AlignRight:
+		alignment = 1
+		centerX = float64(width) - padding - rotatedWidth*scale/2
+	}
+	centerY := float64(height) / 2
+
+	// gg rotates clockwise in image space, where Y points down
+	dc.RotateAbout(-angle, centerX, centerY)
+	metrics := face.Metrics()
+	lineHeight := textHeight / float64(len(lines))
+	left, top := centerX-textWidth/2, centerY-textHeight/2
+	for i, line := range lines {
+		x := left + (textWidth-lineWidths[i])*alignment
+		baseline := top + float64(i)*lineHeight + float64(metrics.Ascent)/64
+		dc.DrawString(line, x, baseline)
+	}
+
+	triangles, err := embossRaster(dc, slot, pixelsPerMM, depth, baseWidth, baseDepth)
+	if err != nil {
+		return PlacedText{}, err
+	}
+
+	return PlacedText{
+		Block:     block,
+		Slot:      slot,
+		FontSize:  referenceFontSize * scale / pixelsPerMM,
+		Bounds:    CalculateBoundingBox(triangles),
+		Triangles: triangles,
+	}, nil
+}
+
+// measureLines returns the width of each line, the width of the widest line and the
+// height of the text block. Lines are spaced by the font's ascent plus descent, so the
+// block encloses accents and descenders.
+func measureLines(dc *gg.Context, face font.Face, lines []string) ([]float64, float64, float64) {
+	metrics := face.Metrics()
+	lineHeight := float64(metrics.Ascent+metrics.Descent) / 64 * lineSpacing
+
+	widths := make([]float64, len(lines))
+	var maxWidth float64
+	for i, line := range lines {
+		widths[i], _ = dc.MeasureString(line)
+		maxWidth = math.Max(maxWidth, widths[i])
+	}
+	return widths, maxWidth, lineHeight * float64(len(lines))
+}
+
+// embossRaster converts the active pixels of a slot raster into boxes standing out
+// of the slot's face. Horizontal runs of pixels are merged into a single box.
+func embossRaster(dc *gg.Context, slot Slot, pixelsPerMM, depth, baseWidth, baseDepth float64) ([]types.Triangle, error) {
+	var triangles []types.Triangle
+	width, height := dc.Width(), dc.Height()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; {
+			if !isPixelActive(dc, x, y) {
+				x++
+				continue
+			}
+			start := x
+			for x < width && isPixelActive(dc, x, y) {
+				x++
+			}
+
+			box, err := faceBox(slot.Face,
+				slot.X+float64(start)/pixelsPerMM, slot.Y+float64(y)/pixelsPerMM,
+				slot.X+float64(x)/pixelsPerMM, slot.Y+float64(y+1)/pixelsPerMM,
+				depth, baseWidth, baseDepth)
+			if err != nil {
+				return nil, errors.New(errors.STLError, "failed to create cube", err)
+			}
+			triangles = append(triangles, box...)
+		}
+	}
+	return triangles, nil
+}
+
+// faceBox creates a box standing depth mm out of a base face, covering the face
+// rectangle from (u0, v0) to (u1, v1) in face coordinates.
+func faceBox(face Face, u0, v0, u1, v1, depth, baseWidth, baseDepth float64) ([]types.Triangle, error) {
+	du, dv := u1-u0, v1-v0
+	switch face {
+	case FaceFront:
+		return CreateCube(u0, -depth, -v1, du, depth, dv)
+	case FaceBack:
+		// Seen from behind, left to right runs towards -X
+		return CreateCube(baseWidth-u1, baseDepth, -v1, du, depth, dv)
+	case FaceLeft:
+		// Seen from the left, left to right runs from the back to the front
+		return CreateCube(-depth, baseDepth-u1, -v1, depth, du, dv)
+	case FaceRight:
+		return CreateCube(baseWidth, u0, -v1, depth, du, dv)
+	case FaceTop:
+		// Seen from above with the front edge at the bottom
+		return CreateCube(u0, baseDepth-v1, 0, du, dv, depth)
+	default:
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf("unknown face %q", face), nil)
+	}
+}
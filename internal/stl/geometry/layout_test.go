@@ -0,0 +1,203 @@
+package geometry
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	layoutWidth  = 142.5
+	layoutDepth  = 27.5
+	layoutHeight = 10.0
+)
+
+func TestParseTextSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    TextBlock
+		wantErr bool
+	}{
+		{
+			name: "slot and text",
+			spec: "top-band:Hello, world: 2024",
+			want: TextBlock{Slot: SlotTopBand, Text: "Hello, world: 2024"},
+		},
+		{
+			name: "all settings",
+			spec: "Back, align=left, rotate=90, size=4, depth=0.6, font=NanumGothic:a\\nb",
+			want: TextBlock{Slot: SlotBack, Text: "a\nb", Align: AlignLeft, Rotation: 90, Size: 4, Depth: 0.6, Fonts: []string{"NanumGothic"}},
+		},
+		{name: "missing text separator", spec: "top-band", wantErr: true},
+		{name: "empty slot", spec: ":text", wantErr: true},
+		{name: "missing value", spec: "back,size:text", wantErr: true},
+		{name: "invalid number", spec: "back,size=big:text", wantErr: true},
+		{name: "unknown setting", spec: "back,color=red:text", wantErr: true},
+		{name: "unknown alignment", spec: "back,align=justify:text", wantErr: true},
+		{name: "negative size", spec: "back,size=-1:text", wantErr: true},
+		{name: "unknown slot", spec: "frnt-left:hello", wantErr: true},
+		{name: "row slot", spec: "row-2:Hubot", want: TextBlock{Slot: "row-2", Text: "Hubot"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTextSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTextSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTextSpec() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLayoutTextFaces verifies each slot embosses text out of the right face, inside the slot.
+func TestLayoutTextFaces(t *testing.T) {
+	const depth = voxelDepth
+	const margin = 2 * CellSize
+
+	tests := []struct {
+		slot string
+		want BoundingBox // Region the embossed text must stay within
+	}{
+		{SlotFrontLeft, box(0, -depth, -layoutHeight, layoutWidth/2, 0, 0)},
+		{SlotFrontRight, box(layoutWidth/2, -depth, -layoutHeight, layoutWidth, 0, 0)},
+		{SlotTopBand, box(margin, 0, 0, layoutWidth-margin, margin, depth)},
+		{SlotTopBack, box(margin, layoutDepth-margin, 0, layoutWidth-margin, layoutDepth, depth)},
+		{SlotBack, box(0, layoutDepth, -layoutHeight, layoutWidth, layoutDepth+depth, 0)},
+		{SlotLeft, box(-depth, 0, -layoutHeight, 0, layoutDepth, 0)},
+		{SlotRight, box(layoutWidth, 0, -layoutHeight, layoutWidth+depth, layoutDepth, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.slot, func(t *testing.T) {
+			placed, err := LayoutText([]TextBlock{{Slot: tt.slot, Text: "Skyline"}}, layoutWidth, layoutDepth, layoutHeight, TextFonts{})
+			if err != nil {
+				t.Fatalf("LayoutText() error = %v", err)
+			}
+			if len(placed) != 1 || len(placed[0].Triangles) == 0 {
+				t.Fatalf("LayoutText() returned no geometry")
+			}
+			got := placed[0].Bounds
+			const tolerance = 1e-6
+			if got.Min.X < tt.want.Min.X-tolerance || got.Min.Y < tt.want.Min.Y-tolerance || got.Min.Z < tt.want.Min.Z-tolerance ||
+				got.Max.X > tt.want.Max.X+tolerance || got.Max.Y > tt.want.Max.Y+tolerance || got.Max.Z > tt.want.Max.Z+tolerance {
+				t.Errorf("text bounds %+v outside slot region %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLayoutTextFitting verifies auto-fit, multi-line text, rotation and alignment.
+func TestLayoutTextFitting(t *testing.T) {
+	layout := func(block TextBlock) PlacedText {
+		t.Helper()
+		placed, err := LayoutText([]TextBlock{block}, layoutWidth, layoutDepth, layoutHeight, TextFonts{})
+		if err != nil {
+			t.Fatalf("LayoutText() error = %v", err)
+		}
+		if len(placed) != 1 {
+			t.Fatalf("LayoutText() placed %d blocks, want 1", len(placed))
+		}
+		return placed[0]
+	}
+
+	short := layout(TextBlock{Slot: SlotBack, Text: "Hi"})
+	long := layout(TextBlock{Slot: SlotBack, Text: "A much longer line of text that needs a smaller font"})
+	if long.FontSize >= short.FontSize {
+		t.Errorf("long text font size %.2f should be smaller than short text %.2f", long.FontSize, short.FontSize)
+	}
+
+	single := layout(TextBlock{Slot: SlotBack, Text: "Skyline 2024"})
+	multi := layout(TextBlock{Slot: SlotBack, Text: "Skyline\n2024"})
+	// Both are limited by the slot height, so two lines use a smaller font and less width
+	if multi.FontSize >= single.FontSize || multi.Bounds.Size().X >= single.Bounds.Size().X {
+		t.Errorf("two lines should use a smaller font and less width: %.2f/%.2f mm vs %.2f/%.2f mm",
+			multi.FontSize, multi.Bounds.Size().X, single.FontSize, single.Bounds.Size().X)
+	}
+
+	rotated := layout(TextBlock{Slot: SlotBack, Text: "Skyline", Rotation: 90})
+	if size := rotated.Bounds.Size(); size.Z <= size.X {
+		t.Errorf("text rotated by 90 degrees should be taller than wide, got %+v", size)
+	}
+
+	left := layout(TextBlock{Slot: SlotBack, Text: "Hi", Align: AlignLeft})
+	right := layout(TextBlock{Slot: SlotBack, Text: "Hi", Align: AlignRight})
+	// The back face is seen from behind, so its left edge is at +X
+	if left.Bounds.Max.X <= right.Bounds.Max.X {
+		t.Errorf("left-aligned text should be at higher X on the back face: %.2f vs %.2f", left.Bounds.Max.X, right.Bounds.Max.X)
+	}
+
+	fixed := layout(TextBlock{Slot: SlotBack, Text: "Hi", Size: 3, Depth: 0.4})
+	if fixed.FontSize != 3 {
+		t.Errorf("FontSize = %.2f, want 3", fixed.FontSize)
+	}
+	if got := fixed.Bounds.Size().Y; got < 0.4-1e-9 || got > 0.4+1e-9 {
+		t.Errorf("emboss depth = %.3f, want 0.4", got)
+	}
+}
+
+func TestLayoutTextErrors(t *testing.T) {
+	if _, err := LayoutText([]TextBlock{{Slot: "roof", Text: "x"}}, layoutWidth, layoutDepth, layoutHeight, TextFonts{}); err == nil {
+		t.Error("LayoutText() expected error for unknown slot")
+	}
+
+	placed, err := LayoutText([]TextBlock{{Slot: SlotBack, Text: "  "}}, layoutWidth, layoutDepth, layoutHeight, TextFonts{})
+	if err != nil || len(placed) != 0 {
+		t.Errorf("LayoutText() should skip empty text, got %d blocks, error %v", len(placed), err)
+	}
+}
+
+func TestDefaultTextBlocks(t *testing.T) {
+	blocks := DefaultTextBlocks("", "2024", "hello")
+	want := []TextBlock{{Slot: SlotFrontRight, Text: "2024"}, {Slot: SlotTopBand, Text: "hello"}}
+	if !reflect.DeepEqual(blocks, want) {
+		t.Errorf("DefaultTextBlocks() = %+v, want %+v", blocks, want)
+	}
+}
+
+func TestRowLabelBlocks(t *testing.T) {
+	const margin = 2 * CellSize
+	width, depth := CalculateMultiYearDimensions(2)
+	blocks := RowLabelBlocks([]string{"mona", "", "hubot"})
+	if len(blocks) != 2 || blocks[0].Slot != "row-1" || blocks[1].Slot != "row-3" {
+		t.Fatalf("RowLabelBlocks() = %+v, want row-1 and row-3", blocks)
+	}
+
+	// The base has two rows, so the third label has no slot
+	if _, err := LayoutText(blocks, width, depth, layoutHeight, TextFonts{}); err == nil {
+		t.Error("LayoutText() accepted a row beyond the grid")
+	}
+
+	placed, err := LayoutText(blocks[:1], width, depth, layoutHeight, TextFonts{})
+	if err != nil {
+		t.Fatalf("LayoutText() error = %v", err)
+	}
+	// The first row is at the back, beside the columns of the grid's first year
+	want := box(0, depth-margin-YearOffset, 0, margin, depth-margin, voxelDepth)
+	got := placed[0].Bounds
+	const tolerance = 1e-6
+	if got.Min.X < want.Min.X-tolerance || got.Min.Y < want.Min.Y-tolerance || got.Max.X > want.Max.X+tolerance || got.Max.Y > want.Max.Y+tolerance {
+		t.Errorf("row label bounds %+v outside slot region %+v", got, want)
+	}
+	if size := got.Size(); size.Y <= size.X {
+		t.Errorf("row label should run along the row, got %+v", size)
+	}
+
+	if face, err := SlotFace("row-3"); err != nil || face != FaceTop {
+		t.Errorf("SlotFace(row-3) = %q, %v, want top", face, err)
+	}
+	if _, err := SlotFace("row-0"); err == nil {
+		t.Error("SlotFace(row-0) succeeded")
+	}
+}
+
+func box(minX, minY, minZ, maxX, maxY, maxZ float64) BoundingBox {
+	return BoundingBox{
+		Min: types.Point3D{X: minX, Y: minY, Z: minZ},
+		Max: types.Point3D{X: maxX, Y: maxY, Z: maxZ},
+	}
+}
@@ -0,0 +1,240 @@
+package geometry
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// MarkerStyle selects how the column of a marked date is highlighted.
+type MarkerStyle string
+
+// Supported marker styles.
+const (
+	// MarkerCap puts a pyramid cap on top of the column.
+	MarkerCap MarkerStyle = "cap"
+	// MarkerPin puts a thin pin with a square head on top of the column.
+	MarkerPin MarkerStyle = "pin"
+	// MarkerBody moves the column itself into the marker's body, so multi-object
+	// formats can print it in another colour.
+	MarkerBody MarkerStyle = "body"
+)
+
+// DefaultMarkerColor is the display colour of marker bodies in multi-object formats.
+const DefaultMarkerColor = "#FFD700"
+
+const (
+	capHeight    = CellSize // Height of the pyramid cap (mm)
+	pinWidth     = 0.8      // Side of the pin's square post (mm)
+	pinHeight    = 6.0      // Height of the post above the column (mm)
+	pinHeadSize  = 2.0      // Side of the pin's cubic head (mm)
+	markerTile   = 0.6      // Height of a body-style marker on a day without contributions (mm)
+	monthDayForm = "01-02"
+	dateForm     = "2006-01-02"
+)
+
+var hexColor = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// DateMarker highlights the column of a date, such as a birthday or release day.
+type DateMarker struct {
+	Date  string      // YYYY-MM-DD, or MM-DD to mark the day in every year
+	Style MarkerStyle // How the column is highlighted
+	Color string      // Display colour of the marker body as #RRGGBB
+	Label string      // Name of the marker body (optional)
+}
+
+// ParseMarkerSpec parses a date marker specification of the form
+//
+//	date[,style=pin][,color=#FFD700][,label=Birthday]
+//
+// where date is YYYY-MM-DD or MM-DD for a date that recurs every year.
+func ParseMarkerSpec(spec string) (DateMarker, error) {
+	parts := strings.Split(spec, ",")
+	marker := DateMarker{Date: strings.TrimSpace(parts[0]), Style: MarkerCap, Color: DefaultMarkerColor}
+
+	for _, part := range parts[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return DateMarker{}, errors.New(errors.ValidationError, fmt.Sprintf("invalid marker setting %q (expected key=value)", part), nil)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "style":
+			marker.Style = MarkerStyle(strings.ToLower(value))
+		case "color", "colour":
+			marker.Color = value
+		case "label":
+			marker.Label = value
+		default:
+			return DateMarker{}, errors.New(errors.ValidationError, fmt.Sprintf("unknown marker setting %q", key), nil)
+		}
+	}
+
+	return marker, marker.Validate()
+}
+
+// ReadMarkerFile reads date markers from a file with one marker specification
+// per line. Blank lines and lines starting with # are ignored.
+func ReadMarkerFile(path string) ([]DateMarker, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to open marker file", err)
+	}
+	defer file.Close()
+
+	var markers []DateMarker
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		marker, err := ParseMarkerSpec(text)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("%s:%d", path, line))
+		}
+		markers = append(markers, marker)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.New(errors.IOError, "failed to read marker file", err)
+	}
+	return markers, nil
+}
+
+// Validate checks that the marker has a valid date, style and colour.
+func (m DateMarker) Validate() error {
+	if _, err := time.Parse(dateForm, m.Date); err != nil {
+		if _, err := time.Parse(monthDayForm, m.Date); err != nil {
+			return errors.New(errors.ValidationError, fmt.Sprintf("invalid marker date %q (expected YYYY-MM-DD or MM-DD)", m.Date), nil)
+		}
+	}
+	switch m.Style {
+	case MarkerCap, MarkerPin, MarkerBody:
+	default:
+		return errors.New(errors.ValidationError, fmt.Sprintf("unknown marker style %q (expected %q, %q or %q)", m.Style, MarkerCap, MarkerPin, MarkerBody), nil)
+	}
+	if m.Color != "" && !hexColor.MatchString(m.Color) {
+		return errors.New(errors.ValidationError, fmt.Sprintf("invalid marker color %q (expected #RRGGBB)", m.Color), nil)
+	}
+	return nil
+}
+
+// Matches reports whether the marker applies to a YYYY-MM-DD date.
+func (m DateMarker) Matches(date string) bool {
+	if len(m.Date) == len(monthDayForm) {
+		return strings.HasSuffix(date, "-"+m.Date)
+	}
+	return date == m.Date
+}
+
+// Name returns the marker's label, or its date when it has none.
+func (m DateMarker) Name() string {
+	if m.Label != "" {
+		return m.Label
+	}
+	return m.Date
+}
+
+// MarkedGeometry is the contribution geometry of one year with the marked days split out.
+type MarkedGeometry struct {
+	Columns []types.Triangle   // Contribution columns, without body-style marked columns
+	Markers [][]types.Triangle // Marker geometry, indexed like the markers it was created for
+	Matched []int              // Number of days each marker matched
+	Types   [][]types.Triangle // Stacked column segments, indexed like ContributionTypes
+}
+
+// CreateMarkedContributionGeometry generates a year's contribution columns and
+// highlights the days matched by markers. A day matched by several markers uses
+// the first. Marked days without contributions get their marker on the base.
+func CreateMarkedContributionGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int, markers []DateMarker) (MarkedGeometry, error) {
+	return createContributionGeometry(contributions, yearIndex, maxContrib, markers, false)
+}
+
+// CreateStackedContributionGeometry is CreateMarkedContributionGeometry with each
+// column split by contribution type into Types instead of Columns.
+func CreateStackedContributionGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int, markers []DateMarker) (MarkedGeometry, error) {
+	return createContributionGeometry(contributions, yearIndex, maxContrib, markers, true)
+}
+
+// createContributionGeometry generates a year's columns and markers, stacking
+// the columns by contribution type when stacked is set.
+func createContributionGeometry(contributions [][]types.ContributionDay, yearIndex int, maxContrib int, markers []DateMarker, stacked bool) (MarkedGeometry, error) {
+	result := MarkedGeometry{Markers: make([][]types.Triangle, len(markers)), Matched: make([]int, len(markers))}
+	if stacked {
+		result.Types = make([][]types.Triangle, len(ContributionTypes))
+	}
+
+	for weekIdx, week := range contributions {
+		for dayIdx, day := range week {
+			height := NormalizeContribution(day.ContributionCount, maxContrib)
+			x, y := columnPosition(weekIdx, dayIdx, yearIndex)
+
+			marker := -1
+			for i, m := range markers {
+				if m.Matches(day.Date) {
+					marker = i
+					break
+				}
+			}
+
+			if marker >= 0 && markers[marker].Style == MarkerBody {
+				result.Matched[marker]++
+				column, err := CreateColumn(x, y, max(height, markerTile), CellSize)
+				if err != nil {
+					return MarkedGeometry{}, err
+				}
+				result.Markers[marker] = append(result.Markers[marker], column...)
+				continue
+			}
+
+			if height > 0 && stacked {
+				segments, err := CreateStackedColumn(x, y, height, dayCounts(day))
+				if err != nil {
+					return MarkedGeometry{}, err
+				}
+				for i, segment := range segments {
+					result.Types[i] = append(result.Types[i], segment...)
+				}
+			} else if height > 0 {
+				column, err := CreateColumn(x, y, height, CellSize)
+				if err != nil {
+					return MarkedGeometry{}, err
+				}
+				result.Columns = append(result.Columns, column...)
+			}
+			if marker >= 0 {
+				result.Matched[marker]++
+				triangles, err := createMarker(markers[marker].Style, x, y, height)
+				if err != nil {
+					return MarkedGeometry{}, err
+				}
+				result.Markers[marker] = append(result.Markers[marker], triangles...)
+			}
+		}
+	}
+	return result, nil
+}
+
+// createMarker creates a cap or pin on top of the column at (x, y) of the given height.
+func createMarker(style MarkerStyle, x, y, height float64) ([]types.Triangle, error) {
+	if style == MarkerCap {
+		return CreatePyramid(x, y, height, CellSize, capHeight)
+	}
+
+	centerX, centerY := x+CellSize/2, y+CellSize/2
+	post, err := CreateCube(centerX-pinWidth/2, centerY-pinWidth/2, height, pinWidth, pinWidth, pinHeight)
+	if err != nil {
+		return nil, err
+	}
+	head, err := CreateCube(centerX-pinHeadSize/2, centerY-pinHeadSize/2, height+pinHeight, pinHeadSize, pinHeadSize, pinHeadSize)
+	if err != nil {
+		return nil, err
+	}
+	return append(post, head...), nil
+}
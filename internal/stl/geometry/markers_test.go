@@ -0,0 +1,178 @@
+package geometry
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestParseMarkerSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    DateMarker
+		wantErr bool
+	}{
+		{
+			name: "date only",
+			spec: "2024-03-15",
+			want: DateMarker{Date: "2024-03-15", Style: MarkerCap, Color: DefaultMarkerColor},
+		},
+		{
+			name: "recurring date with settings",
+			spec: "02-29, style=Pin, color=#ff0000, label=Leap day",
+			want: DateMarker{Date: "02-29", Style: MarkerPin, Color: "#ff0000", Label: "Leap day"},
+		},
+		{name: "invalid date", spec: "2024-13-01", wantErr: true},
+		{name: "free text date", spec: "birthday", wantErr: true},
+		{name: "unknown style", spec: "2024-03-15,style=flag", wantErr: true},
+		{name: "invalid color", spec: "2024-03-15,color=gold", wantErr: true},
+		{name: "missing value", spec: "2024-03-15,style", wantErr: true},
+		{name: "unknown setting", spec: "2024-03-15,size=2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMarkerSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMarkerSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMarkerSpec() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadMarkerFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "markers.txt")
+	content := "# Team dates\n\n2024-03-15,style=pin,label=Launch\n06-01\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	markers, err := ReadMarkerFile(path)
+	if err != nil {
+		t.Fatalf("ReadMarkerFile() error = %v", err)
+	}
+	if len(markers) != 2 || markers[0].Label != "Launch" || markers[1].Date != "06-01" {
+		t.Errorf("ReadMarkerFile() = %+v", markers)
+	}
+
+	broken := filepath.Join(dir, "broken.txt")
+	if err := os.WriteFile(broken, []byte("2024-03-15\nnot a date\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadMarkerFile(broken); err == nil {
+		t.Error("ReadMarkerFile() expected error for an invalid line")
+	}
+	if _, err := ReadMarkerFile(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("ReadMarkerFile() expected error for a missing file")
+	}
+}
+
+func TestDateMarkerMatches(t *testing.T) {
+	exact := DateMarker{Date: "2024-03-15"}
+	recurring := DateMarker{Date: "03-15"}
+	for _, tt := range []struct {
+		marker DateMarker
+		date   string
+		want   bool
+	}{
+		{exact, "2024-03-15", true},
+		{exact, "2023-03-15", false},
+		{recurring, "2023-03-15", true},
+		{recurring, "2023-03-16", false},
+	} {
+		if got := tt.marker.Matches(tt.date); got != tt.want {
+			t.Errorf("%q.Matches(%q) = %v, want %v", tt.marker.Date, tt.date, got, tt.want)
+		}
+	}
+}
+
+// TestCreateMarkedContributionGeometry verifies each marker style's geometry and
+// where it ends up.
+func TestCreateMarkedContributionGeometry(t *testing.T) {
+	contributions := [][]types.ContributionDay{{
+		{Date: "2024-03-14", ContributionCount: 4},
+		{Date: "2024-03-15", ContributionCount: 2},
+		{Date: "2024-03-16", ContributionCount: 0},
+	}}
+	const columnTriangles = 12
+
+	tests := []struct {
+		name        string
+		markers     []DateMarker
+		wantColumns int
+		wantMarkers []int
+		wantMatched []int
+		wantTop     float64 // Top of the first marker's geometry
+	}{
+		{
+			name:        "no markers",
+			wantColumns: 2 * columnTriangles,
+			wantMarkers: []int{},
+			wantMatched: []int{},
+		},
+		{
+			name:        "cap",
+			markers:     []DateMarker{{Date: "2024-03-15", Style: MarkerCap}},
+			wantColumns: 2 * columnTriangles,
+			wantMarkers: []int{6},
+			wantMatched: []int{1},
+			wantTop:     NormalizeContribution(2, 4) + capHeight,
+		},
+		{
+			name:        "pin on a day without contributions",
+			markers:     []DateMarker{{Date: "03-16", Style: MarkerPin}},
+			wantColumns: 2 * columnTriangles,
+			wantMarkers: []int{24},
+			wantMatched: []int{1},
+			wantTop:     pinHeight + pinHeadSize,
+		},
+		{
+			name:        "body moves the column",
+			markers:     []DateMarker{{Date: "2024-03-14", Style: MarkerBody}},
+			wantColumns: columnTriangles,
+			wantMarkers: []int{columnTriangles},
+			wantMatched: []int{1},
+			wantTop:     MaxHeight,
+		},
+		{
+			name:        "first matching marker wins",
+			markers:     []DateMarker{{Date: "03-15", Style: MarkerPin}, {Date: "2024-03-15", Style: MarkerCap}, {Date: "2023-01-01", Style: MarkerCap}},
+			wantColumns: 2 * columnTriangles,
+			wantMarkers: []int{24, 0, 0},
+			wantMatched: []int{1, 0, 0},
+			wantTop:     NormalizeContribution(2, 4) + pinHeight + pinHeadSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateMarkedContributionGeometry(contributions, 0, 4, tt.markers)
+			if err != nil {
+				t.Fatalf("CreateMarkedContributionGeometry() error = %v", err)
+			}
+			if len(got.Columns) != tt.wantColumns {
+				t.Errorf("got %d column triangles, want %d", len(got.Columns), tt.wantColumns)
+			}
+			markers := make([]int, len(got.Markers))
+			for i, m := range got.Markers {
+				markers[i] = len(m)
+			}
+			if !reflect.DeepEqual(markers, tt.wantMarkers) || !reflect.DeepEqual(got.Matched, tt.wantMatched) {
+				t.Errorf("marker triangles %v matched %v, want %v and %v", markers, got.Matched, tt.wantMarkers, tt.wantMatched)
+			}
+			if len(tt.markers) > 0 {
+				if top := CalculateBoundingBox(got.Markers[0]).Max.Z; top < tt.wantTop-1e-9 || top > tt.wantTop+1e-9 {
+					t.Errorf("marker top = %.3f, want %.3f", top, tt.wantTop)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,304 @@
+package geometry
+
+import (
+	"math"
+	"sort"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// DefaultMoldWall is the default thickness of the mold around the cavity (mm).
+const DefaultMoldWall = 3.0
+
+// moldEpsilon is the tolerance for coordinates that coincide (mm).
+const moldEpsilon = 1e-6
+
+// MoldOptions controls the negative mold generated instead of the skyline.
+// The zero value disables the mold.
+type MoldOptions struct {
+	Enabled bool
+	Wall    float64 // Thickness of the sides and floor around the cavity (mm)
+}
+
+// withDefaults fills unset fields with the defaults.
+func (o MoldOptions) withDefaults() MoldOptions {
+	if o.Wall == 0 {
+		o.Wall = DefaultMoldWall
+	}
+	return o
+}
+
+// Validate checks the options of an enabled mold.
+func (o MoldOptions) Validate() error {
+	if !o.Enabled {
+		return nil
+	}
+	if wall := o.withDefaults().Wall; wall < 0 || math.IsNaN(wall) || math.IsInf(wall, 0) {
+		return errors.New(errors.ValidationError, "mold wall thickness must be positive", nil)
+	}
+	return nil
+}
+
+// Box is an axis-aligned box given by its minimum and maximum corners.
+type Box struct {
+	Min, Max types.Point3D
+}
+
+// Triangles returns the twelve triangles of the box.
+func (b Box) Triangles() ([]types.Triangle, error) {
+	return createBox(b.Min.X, b.Min.Y, b.Min.Z, b.Max.X-b.Min.X, b.Max.Y-b.Min.Y, b.Max.Z-b.Min.Z)
+}
+
+// ExtractBoxes splits a mesh into the axis-aligned boxes it was built from, as
+// created by CreateCube and CreateColumn, and the triangles of all other shapes.
+func ExtractBoxes(triangles []types.Triangle) ([]Box, []types.Triangle) {
+	const boxTriangles = 12
+	var boxes []Box
+	var rest []types.Triangle
+	for i := 0; i < len(triangles); {
+		if i+boxTriangles <= len(triangles) {
+			if box, ok := boxFromTriangles(triangles[i : i+boxTriangles]); ok {
+				boxes = append(boxes, box)
+				i += boxTriangles
+				continue
+			}
+		}
+		rest = append(rest, triangles[i])
+		i++
+	}
+	return boxes, rest
+}
+
+// boxFromTriangles reports whether the triangles exactly cover the surface of
+// their bounding box, and returns the box if they do.
+func boxFromTriangles(triangles []types.Triangle) (Box, bool) {
+	bounds := CalculateBoundingBox(triangles)
+	size := bounds.Size()
+	if size.X <= moldEpsilon || size.Y <= moldEpsilon || size.Z <= moldEpsilon {
+		return Box{}, false
+	}
+
+	// Faces in the order -X, +X, -Y, +Y, -Z, +Z
+	faceAreas := [6]float64{size.Y * size.Z, size.Y * size.Z, size.X * size.Z, size.X * size.Z, size.X * size.Y, size.X * size.Y}
+	var covered [6]float64
+	for _, t := range triangles {
+		face := -1
+		vertices := []types.Point3D{t.V1, t.V2, t.V3}
+		for axis := 0; axis < 3 && face < 0; axis++ {
+			for side, bound := range []float64{component(bounds.Min, axis), component(bounds.Max, axis)} {
+				if math.Abs(component(t.V1, axis)-bound) < moldEpsilon &&
+					math.Abs(component(t.V2, axis)-bound) < moldEpsilon &&
+					math.Abs(component(t.V3, axis)-bound) < moldEpsilon {
+					face = 2*axis + side
+					break
+				}
+			}
+		}
+		if face < 0 {
+			return Box{}, false
+		}
+		for _, v := range vertices {
+			for axis := 0; axis < 3; axis++ {
+				c := component(v, axis)
+				if math.Abs(c-component(bounds.Min, axis)) >= moldEpsilon && math.Abs(c-component(bounds.Max, axis)) >= moldEpsilon {
+					return Box{}, false
+				}
+			}
+		}
+		covered[face] += 0.5 * math.Sqrt(lengthSquared(vectorCross(vectorSubtract(t.V2, t.V1), vectorSubtract(t.V3, t.V1))))
+	}
+	for i := range covered {
+		if math.Abs(covered[i]-faceAreas[i]) > moldEpsilon*math.Max(1, faceAreas[i]) {
+			return Box{}, false
+		}
+	}
+	return Box{Min: bounds.Min, Max: bounds.Max}, true
+}
+
+// component returns the coordinate of p along axis 0 (X), 1 (Y) or 2 (Z).
+func component(p types.Point3D, axis int) float64 {
+	switch axis {
+	case 0:
+		return p.X
+	case 1:
+		return p.Y
+	default:
+		return p.Z
+	}
+}
+
+// MoldResult is a negative mold and the parts of the model it could not include.
+type MoldResult struct {
+	Triangles []types.Triangle
+	Skipped   int // Triangles of shapes other than boxes, which are not subtracted
+}
+
+// CreateMold creates a negative mold of a model built from boxes: a block
+// enclosing the model with the model subtracted. The cavity is open where the
+// bottom of the model lies, and the mold is turned upside down so the opening
+// faces up for pouring. Shapes that are not boxes, such as external character
+// models and cap markers, cannot be subtracted and are left out.
+func CreateMold(model []types.Triangle, opts MoldOptions) (MoldResult, error) {
+	if err := opts.Validate(); err != nil {
+		return MoldResult{}, err
+	}
+	opts = opts.withDefaults()
+
+	boxes, rest := ExtractBoxes(model)
+	if len(boxes) == 0 {
+		return MoldResult{}, errors.New(errors.ValidationError, "model has no box geometry to create a mold from", nil)
+	}
+
+	bounds := BoundingBox{Min: boxes[0].Min, Max: boxes[0].Max}
+	for _, box := range boxes[1:] {
+		bounds = bounds.Union(BoundingBox{Min: box.Min, Max: box.Max})
+	}
+	block := Box{
+		Min: types.Point3D{X: bounds.Min.X - opts.Wall, Y: bounds.Min.Y - opts.Wall, Z: bounds.Min.Z},
+		Max: types.Point3D{X: bounds.Max.X + opts.Wall, Y: bounds.Max.Y + opts.Wall, Z: bounds.Max.Z + opts.Wall},
+	}
+
+	var triangles []types.Triangle
+	for _, box := range subtractBoxes(block, boxes) {
+		// Turn the mold over by rotating it half a turn about the X axis within the block
+		flipped := Box{
+			Min: types.Point3D{X: box.Min.X, Y: block.Min.Y + block.Max.Y - box.Max.Y, Z: block.Min.Z + block.Max.Z - box.Max.Z},
+			Max: types.Point3D{X: box.Max.X, Y: block.Min.Y + block.Max.Y - box.Min.Y, Z: block.Min.Z + block.Max.Z - box.Min.Z},
+		}
+		boxTriangles, err := flipped.Triangles()
+		if err != nil {
+			return MoldResult{}, errors.New(errors.STLError, "failed to create mold box", err)
+		}
+		triangles = append(triangles, boxTriangles...)
+	}
+	return MoldResult{Triangles: triangles, Skipped: len(rest)}, nil
+}
+
+// interval is a closed range of Z values.
+type interval struct {
+	lo, hi float64
+}
+
+// subtractBoxes returns boxes that together fill the block minus the union of
+// the given boxes. The block is cut into slabs along Y and X at every box edge;
+// in each cell the free Z ranges become boxes, and boxes with the same ranges
+// in neighbouring cells are merged.
+func subtractBoxes(block Box, boxes []Box) []Box {
+	ys := []float64{block.Min.Y, block.Max.Y}
+	for _, box := range boxes {
+		ys = append(ys, box.Min.Y, box.Max.Y)
+	}
+	ys = uniqueWithin(ys, block.Min.Y, block.Max.Y)
+
+	type runKey struct {
+		x0, x1, z0, z1 float64
+	}
+	var result []Box
+	open := make(map[runKey]int) // Boxes ending at the current slab, by their X and Z extent
+
+	for i := 0; i+1 < len(ys); i++ {
+		y0, y1 := ys[i], ys[i+1]
+		var active []Box
+		for _, box := range boxes {
+			if box.Min.Y <= y0+moldEpsilon && box.Max.Y >= y1-moldEpsilon {
+				active = append(active, box)
+			}
+		}
+
+		xs := []float64{block.Min.X, block.Max.X}
+		for _, box := range active {
+			xs = append(xs, box.Min.X, box.Max.X)
+		}
+		xs = uniqueWithin(xs, block.Min.X, block.Max.X)
+
+		next := make(map[runKey]int)
+		extend := func(x0, x1 float64, free []interval) {
+			for _, gap := range free {
+				key := runKey{x0, x1, gap.lo, gap.hi}
+				if index, ok := open[key]; ok {
+					result[index].Max.Y = y1
+					next[key] = index
+					continue
+				}
+				next[key] = len(result)
+				result = append(result, Box{
+					Min: types.Point3D{X: x0, Y: y0, Z: gap.lo},
+					Max: types.Point3D{X: x1, Y: y1, Z: gap.hi},
+				})
+			}
+		}
+
+		// Merge neighbouring X cells with the same free Z ranges into one run
+		runStart := xs[0]
+		var runFree []interval
+		for j := 0; j+1 < len(xs); j++ {
+			x0, x1 := xs[j], xs[j+1]
+			var filled []interval
+			for _, box := range active {
+				if box.Min.X <= x0+moldEpsilon && box.Max.X >= x1-moldEpsilon {
+					filled = append(filled, interval{box.Min.Z, box.Max.Z})
+				}
+			}
+			free := freeIntervals(block.Min.Z, block.Max.Z, filled)
+			if j > 0 && !equalIntervals(free, runFree) {
+				extend(runStart, x0, runFree)
+				runStart = x0
+			}
+			runFree = free
+		}
+		extend(runStart, xs[len(xs)-1], runFree)
+		open = next
+	}
+	return result
+}
+
+// uniqueWithin sorts values, drops those outside [lo, hi] and merges values
+// closer than moldEpsilon.
+func uniqueWithin(values []float64, lo, hi float64) []float64 {
+	sort.Float64s(values)
+	var result []float64
+	for _, v := range values {
+		if v < lo-moldEpsilon || v > hi+moldEpsilon {
+			continue
+		}
+		if len(result) > 0 && v-result[len(result)-1] < moldEpsilon {
+			continue
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+// freeIntervals returns the parts of [lo, hi] not covered by the filled intervals.
+func freeIntervals(lo, hi float64, filled []interval) []interval {
+	sort.Slice(filled, func(i, j int) bool { return filled[i].lo < filled[j].lo })
+	var free []interval
+	z := lo
+	for _, f := range filled {
+		if f.lo > z+moldEpsilon {
+			free = append(free, interval{z, math.Min(f.lo, hi)})
+		}
+		z = math.Max(z, f.hi)
+		if z >= hi-moldEpsilon {
+			return free
+		}
+	}
+	if hi > z+moldEpsilon {
+		free = append(free, interval{z, hi})
+	}
+	return free
+}
+
+// equalIntervals reports whether two interval lists are identical.
+func equalIntervals(a, b []interval) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,153 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// boxVolume returns the total volume of the boxes.
+func boxVolume(boxes []Box) float64 {
+	var volume float64
+	for _, b := range boxes {
+		volume += (b.Max.X - b.Min.X) * (b.Max.Y - b.Min.Y) * (b.Max.Z - b.Min.Z)
+	}
+	return volume
+}
+
+// containsPoint reports whether any box strictly contains p.
+func containsPoint(boxes []Box, p types.Point3D) bool {
+	for _, b := range boxes {
+		if p.X > b.Min.X && p.X < b.Max.X && p.Y > b.Min.Y && p.Y < b.Max.Y && p.Z > b.Min.Z && p.Z < b.Max.Z {
+			return true
+		}
+	}
+	return false
+}
+
+func TestExtractBoxes(t *testing.T) {
+	first, _ := CreateCube(0, 0, 0, 1, 2, 3)
+	pyramid, _ := CreatePyramid(5, 5, 0, 2, 1)
+	second, _ := CreateColumn(10, 0, 4, CellSize)
+
+	var model []types.Triangle
+	model = append(model, first...)
+	model = append(model, pyramid...)
+	model = append(model, second...)
+
+	boxes, rest := ExtractBoxes(model)
+	if len(boxes) != 2 || len(rest) != len(pyramid) {
+		t.Fatalf("got %d boxes and %d other triangles, want 2 and %d", len(boxes), len(rest), len(pyramid))
+	}
+	want := []Box{
+		{Min: types.Point3D{}, Max: types.Point3D{X: 1, Y: 2, Z: 3}},
+		{Min: types.Point3D{X: 10}, Max: types.Point3D{X: 10 + CellSize, Y: CellSize, Z: 4}},
+	}
+	for i := range want {
+		if boxes[i] != want[i] {
+			t.Errorf("box %d = %+v, want %+v", i, boxes[i], want[i])
+		}
+	}
+
+	// Twelve triangles that are not a box: two copies of the same half
+	if boxes, _ := ExtractBoxes(append(append([]types.Triangle{}, first[:6]...), first[:6]...)); len(boxes) != 0 {
+		t.Errorf("duplicated faces were taken for a box")
+	}
+}
+
+func TestSubtractBoxes(t *testing.T) {
+	block := Box{Max: types.Point3D{X: 10, Y: 10, Z: 10}}
+	tests := []struct {
+		name  string
+		boxes []Box
+	}{
+		{name: "nothing to subtract"},
+		{name: "single box", boxes: []Box{{Min: types.Point3D{X: 2, Y: 2}, Max: types.Point3D{X: 4, Y: 4, Z: 5}}}},
+		{
+			name: "overlapping and touching boxes",
+			boxes: []Box{
+				{Min: types.Point3D{X: 1, Y: 1, Z: 0}, Max: types.Point3D{X: 9, Y: 9, Z: 2}},
+				{Min: types.Point3D{X: 2, Y: 2, Z: 2}, Max: types.Point3D{X: 4, Y: 4, Z: 7}},
+				{Min: types.Point3D{X: 3, Y: 3, Z: 1}, Max: types.Point3D{X: 5, Y: 5, Z: 6}},
+			},
+		},
+		{name: "box sticking out of the block", boxes: []Box{{Min: types.Point3D{X: -5, Y: 4, Z: 4}, Max: types.Point3D{X: 15, Y: 6, Z: 6}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := subtractBoxes(block, tt.boxes)
+
+			// Sample the block on a grid: each point is in exactly one of the model or the result
+			for x := 0.25; x < 10; x += 0.5 {
+				for y := 0.25; y < 10; y += 0.5 {
+					for z := 0.25; z < 10; z += 0.5 {
+						p := types.Point3D{X: x, Y: y, Z: z}
+						inModel, inResult := containsPoint(tt.boxes, p), 0
+						for _, b := range result {
+							if containsPoint([]Box{b}, p) {
+								inResult++
+							}
+						}
+						if inModel && inResult != 0 || !inModel && inResult != 1 {
+							t.Fatalf("point %+v: in model %v, in %d result boxes", p, inModel, inResult)
+						}
+					}
+				}
+			}
+		})
+	}
+
+	if got := subtractBoxes(block, nil); len(got) != 1 || got[0] != block {
+		t.Errorf("empty subtraction = %+v, want the block", got)
+	}
+}
+
+func TestCreateMold(t *testing.T) {
+	base, _ := CreateCuboidBase(20, 10)
+	column, _ := CreateColumn(5, 5, 8, CellSize)
+	pyramid, _ := CreatePyramid(5, 5, 8, CellSize, 1)
+	model := append(append(append([]types.Triangle{}, base...), column...), pyramid...)
+
+	if _, err := CreateMold(model, MoldOptions{Enabled: true, Wall: -1}); err == nil {
+		t.Error("CreateMold() expected error for a negative wall")
+	}
+	if _, err := CreateMold(pyramid, MoldOptions{Enabled: true}); err == nil {
+		t.Error("CreateMold() expected error for a model without boxes")
+	}
+
+	result, err := CreateMold(model, MoldOptions{Enabled: true, Wall: 2})
+	if err != nil {
+		t.Fatalf("CreateMold() error = %v", err)
+	}
+	if result.Skipped != len(pyramid) {
+		t.Errorf("skipped %d triangles, want %d", result.Skipped, len(pyramid))
+	}
+
+	bounds := CalculateBoundingBox(result.Triangles)
+	wantMin := types.Point3D{X: -2, Y: -2, Z: -BaseHeight}
+	wantMax := types.Point3D{X: 22, Y: 12, Z: 10}
+	if bounds.Min != wantMin || bounds.Max != wantMax {
+		t.Errorf("mold bounds = %+v, want %+v to %+v", bounds, wantMin, wantMax)
+	}
+
+	boxes, rest := ExtractBoxes(result.Triangles)
+	if len(rest) != 0 {
+		t.Fatalf("mold has %d triangles outside boxes", len(rest))
+	}
+	blockVolume := 24.0 * 14 * 20
+	modelVolume := 20.0*10*BaseHeight + CellSize*CellSize*8
+	if got := boxVolume(boxes); math.Abs(got-(blockVolume-modelVolume)) > 1e-6 {
+		t.Errorf("mold volume = %.3f, want %.3f", got, blockVolume-modelVolume)
+	}
+
+	// Turned over, the base is the opening at the top and the column a pit below it
+	if containsPoint(boxes, types.Point3D{X: 10, Y: 5, Z: 9.9}) {
+		t.Error("the opening of the cavity is closed")
+	}
+	columnX, columnY := 5+CellSize/2, 10-(5+CellSize/2)
+	if containsPoint(boxes, types.Point3D{X: columnX, Y: columnY, Z: -7.9}) || !containsPoint(boxes, types.Point3D{X: columnX, Y: columnY, Z: -8.1}) {
+		t.Error("the column's pit is not 8 mm deep below the base")
+	}
+}
@@ -0,0 +1,133 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/qrcode"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	// DefaultQRCodeSize is the default side length of a QR code, including its quiet zone (mm).
+	DefaultQRCodeSize = 30.0
+
+	defaultQRCodeDepth = 1.0 // Height of the dark modules above the plate (mm)
+	qrPlateThickness   = 2.0 // Thickness of the plate a back face QR code stands on (mm)
+
+	// minQRModuleSize is the smallest module a 0.4 mm nozzle prints reliably (mm).
+	minQRModuleSize = 0.4
+)
+
+// QRCodeOptions controls the QR code embossed behind the contribution grid.
+// The zero value disables the QR code.
+type QRCodeOptions struct {
+	Enabled bool
+	Content string       // Text or URL to encode
+	Level   qrcode.Level // Error correction level
+	// Face is FaceTop to emboss the code on a tab that extends the base's top face
+	// behind the grid, or FaceBack for a plate standing against the back face.
+	Face  Face
+	Size  float64 // Side length including the quiet zone (mm)
+	Depth float64 // Height of the dark modules (mm)
+}
+
+// withDefaults fills unset fields with the defaults.
+func (o QRCodeOptions) withDefaults() QRCodeOptions {
+	if o.Face == "" {
+		o.Face = FaceTop
+	}
+	if o.Size == 0 {
+		o.Size = DefaultQRCodeSize
+	}
+	if o.Depth == 0 {
+		o.Depth = defaultQRCodeDepth
+	}
+	return o
+}
+
+// Validate checks the options of an enabled QR code.
+func (o QRCodeOptions) Validate() error {
+	if !o.Enabled {
+		return nil
+	}
+	o = o.withDefaults()
+	if o.Content == "" {
+		return errors.New(errors.ValidationError, "QR code content cannot be empty", nil)
+	}
+	if o.Face != FaceTop && o.Face != FaceBack {
+		return errors.New(errors.ValidationError, fmt.Sprintf("QR code face must be %q or %q, got %q", FaceTop, FaceBack, o.Face), nil)
+	}
+	if o.Level < qrcode.Low || o.Level > qrcode.High {
+		return errors.New(errors.ValidationError, fmt.Sprintf("invalid QR code error correction level %d", o.Level), nil)
+	}
+	if o.Size < 0 || o.Depth < 0 || math.IsNaN(o.Size) || math.IsNaN(o.Depth) {
+		return errors.New(errors.ValidationError, "QR code size and depth must be positive", nil)
+	}
+	return nil
+}
+
+// CreateQRCode encodes opts.Content and embosses its dark modules, centred behind
+// the base. On the top face the code stands on a tab level with the base top, with
+// the top of the code at the back; on the back face it stands on a plate against
+// the base, reading upright from behind. The quiet zone is left flat.
+func CreateQRCode(baseWidth, baseDepth float64, opts QRCodeOptions) ([]types.Triangle, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults()
+
+	code, err := qrcode.Encode([]byte(opts.Content), opts.Level)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode QR code")
+	}
+	bitmap := code.Bitmap(qrcode.QuietZone)
+	module := opts.Size / float64(len(bitmap))
+	if module < minQRModuleSize {
+		return nil, errors.New(errors.ValidationError, fmt.Sprintf(
+			"QR code version %d needs %d modules of %.2f mm, below the printable %.1f mm; increase the size to at least %.0f mm or lower the error correction level",
+			code.Version, len(bitmap), module, minQRModuleSize, math.Ceil(minQRModuleSize*float64(len(bitmap)))), nil)
+	}
+
+	left := (baseWidth - opts.Size) / 2
+	var triangles []types.Triangle
+	var moduleBox func(u0, v0, u1, v1 float64) ([]types.Triangle, error)
+	switch opts.Face {
+	case FaceTop:
+		triangles, err = CreateCube(left, baseDepth, -BaseHeight, opts.Size, opts.Size, BaseHeight)
+		// Seen from above with the front edge at the bottom
+		moduleBox = func(u0, v0, u1, v1 float64) ([]types.Triangle, error) {
+			return CreateCube(left+u0, baseDepth+opts.Size-v1, 0, u1-u0, v1-v0, opts.Depth)
+		}
+	default:
+		triangles, err = CreateCube(left, baseDepth, -BaseHeight, opts.Size, qrPlateThickness, opts.Size)
+		// Seen from behind, left to right runs towards -X
+		moduleBox = func(u0, v0, u1, v1 float64) ([]types.Triangle, error) {
+			return CreateCube(left+opts.Size-u1, baseDepth+qrPlateThickness, -BaseHeight+opts.Size-v1, u1-u0, opts.Depth, v1-v0)
+		}
+	}
+	if err != nil {
+		return nil, errors.New(errors.STLError, "failed to create QR code plate", err)
+	}
+
+	// Horizontal runs of dark modules are merged into a single box
+	for y, row := range bitmap {
+		for x := 0; x < len(row); {
+			if !row[x] {
+				x++
+				continue
+			}
+			start := x
+			for x < len(row) && row[x] {
+				x++
+			}
+			box, err := moduleBox(float64(start)*module, float64(y)*module, float64(x)*module, float64(y+1)*module)
+			if err != nil {
+				return nil, errors.New(errors.STLError, "failed to create QR code module", err)
+			}
+			triangles = append(triangles, box...)
+		}
+	}
+	return triangles, nil
+}
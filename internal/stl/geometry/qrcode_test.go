@@ -0,0 +1,105 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/qrcode"
+)
+
+// TestCreateQRCode verifies the plate position and that the embossed modules
+// reproduce the code, upright as seen from outside.
+func TestCreateQRCode(t *testing.T) {
+	const content = "https://github.com/octocat"
+	code, err := qrcode.Encode([]byte(content), qrcode.Medium)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	bitmap := code.Bitmap(qrcode.QuietZone)
+	n := len(bitmap)
+	module := DefaultQRCodeSize / float64(n)
+	left := (layoutWidth - DefaultQRCodeSize) / 2
+
+	tests := []struct {
+		face Face
+		want BoundingBox
+		// cell returns the module row and first column of a run of modules
+		cell func(run BoundingBox) (int, int)
+	}{
+		{
+			face: FaceTop,
+			want: box(left, layoutDepth, -layoutHeight, left+DefaultQRCodeSize, layoutDepth+DefaultQRCodeSize, defaultQRCodeDepth),
+			cell: func(run BoundingBox) (int, int) {
+				return int((layoutDepth + DefaultQRCodeSize - run.Center().Y) / module), int(math.Round((run.Min.X - left) / module))
+			},
+		},
+		{
+			face: FaceBack,
+			want: box(left, layoutDepth, -layoutHeight, left+DefaultQRCodeSize, layoutDepth+qrPlateThickness+defaultQRCodeDepth, -layoutHeight+DefaultQRCodeSize),
+			cell: func(run BoundingBox) (int, int) {
+				return int((-layoutHeight + DefaultQRCodeSize - run.Center().Z) / module), int(math.Round((left + DefaultQRCodeSize - run.Max.X) / module))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.face), func(t *testing.T) {
+			triangles, err := CreateQRCode(layoutWidth, layoutDepth, QRCodeOptions{Enabled: true, Content: content, Level: qrcode.Medium, Face: tt.face})
+			if err != nil {
+				t.Fatalf("CreateQRCode() error = %v", err)
+			}
+			got := CalculateBoundingBox(triangles)
+			const tolerance = 1e-9
+			if math.Abs(got.Min.X-tt.want.Min.X) > tolerance || math.Abs(got.Max.X-tt.want.Max.X) > tolerance ||
+				math.Abs(got.Min.Y-tt.want.Min.Y) > tolerance || math.Abs(got.Max.Y-tt.want.Max.Y) > tolerance ||
+				math.Abs(got.Min.Z-tt.want.Min.Z) > tolerance || math.Abs(got.Max.Z-tt.want.Max.Z) > tolerance {
+				t.Errorf("bounds = %+v, want %+v", got, tt.want)
+			}
+
+			// Every box after the plate is a run of dark modules in one row
+			embossed := make([][]bool, n)
+			for i := range embossed {
+				embossed[i] = make([]bool, n)
+			}
+			for i := 12; i < len(triangles); i += 12 {
+				run := CalculateBoundingBox(triangles[i : i+12])
+				row, col := tt.cell(run)
+				for j := 0; j < int(math.Round(run.Size().X/module)); j++ {
+					embossed[row][col+j] = true
+				}
+			}
+			for y := range bitmap {
+				for x := range bitmap[y] {
+					if embossed[y][x] != bitmap[y][x] {
+						t.Fatalf("module (%d, %d) embossed = %v, want %v", x, y, embossed[y][x], bitmap[y][x])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestCreateQRCodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts QRCodeOptions
+	}{
+		{"empty content", QRCodeOptions{Enabled: true}},
+		{"unsupported face", QRCodeOptions{Enabled: true, Content: "x", Face: FaceLeft}},
+		{"negative size", QRCodeOptions{Enabled: true, Content: "x", Size: -5}},
+		{"modules too small", QRCodeOptions{Enabled: true, Content: "https://github.com/octocat", Size: 10}},
+		{"content too long", QRCodeOptions{Enabled: true, Content: string(make([]byte, 3000)), Size: 200}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CreateQRCode(layoutWidth, layoutDepth, tt.opts); err == nil {
+				t.Error("CreateQRCode() expected error")
+			}
+		})
+	}
+
+	if err := (QRCodeOptions{}).Validate(); err != nil {
+		t.Errorf("disabled QR code should be valid, got %v", err)
+	}
+}
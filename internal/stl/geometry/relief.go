@@ -0,0 +1,102 @@
+package geometry
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+)
+
+// ReliefMode selects how image pixels are converted into embossed depth.
+type ReliefMode string
+
+// Supported relief modes.
+const (
+	// ReliefBinary embosses bright, opaque pixels at a single depth (the original logo behaviour).
+	ReliefBinary ReliefMode = "binary"
+	// ReliefGrayscale maps pixel luminance to emboss depth, producing a bas-relief.
+	ReliefGrayscale ReliefMode = "grayscale"
+)
+
+// binaryThreshold is the 16-bit channel value a pixel must exceed to be embossed in binary mode.
+const binaryThreshold = 32768
+
+// ReliefOptions controls how an image is embossed onto a face of the base.
+// The zero value is valid and reproduces the binary logo embossing.
+type ReliefOptions struct {
+	Mode     ReliefMode // Binary on/off voxels or luminance-driven depth
+	MinDepth float64    // Depth of the darkest embossed pixel in grayscale mode (mm)
+	MaxDepth float64    // Depth of the brightest pixel, or of every pixel in binary mode (mm)
+	Invert   bool       // Emboss dark pixels instead of bright ones
+	Gamma    float64    // Exponent applied to luminance before mapping to depth; zero uses 1
+}
+
+// withDefaults fills unset fields with the defaults used for the embedded logo.
+func (o ReliefOptions) withDefaults() ReliefOptions {
+	if o.Mode == "" {
+		o.Mode = ReliefBinary
+	}
+	if o.MaxDepth == 0 {
+		o.MaxDepth = voxelDepth
+	}
+	if o.Gamma == 0 {
+		o.Gamma = 1.0
+	}
+	return o
+}
+
+// Validate checks that the relief options describe a printable relief.
+func (o ReliefOptions) Validate() error {
+	o = o.withDefaults()
+	switch o.Mode {
+	case ReliefBinary, ReliefGrayscale:
+	default:
+		return errors.New(errors.ValidationError, fmt.Sprintf("unknown relief mode %q (expected %q or %q)", o.Mode, ReliefBinary, ReliefGrayscale), nil)
+	}
+	if o.MinDepth < 0 || o.MaxDepth < 0 {
+		return errors.New(errors.ValidationError, "relief depth cannot be negative", nil)
+	}
+	if o.MinDepth > o.MaxDepth {
+		return errors.New(errors.ValidationError, "relief minimum depth cannot exceed maximum depth", nil)
+	}
+	if o.Gamma <= 0 || math.IsNaN(o.Gamma) || math.IsInf(o.Gamma, 0) {
+		return errors.New(errors.ValidationError, "relief gamma must be a positive number", nil)
+	}
+	return nil
+}
+
+// pixelDepth returns the emboss depth for a pixel, or 0 if no voxel should be created.
+// Options are expected to have defaults applied.
+func (o ReliefOptions) pixelDepth(c color.Color) float64 {
+	r, g, b, a := c.RGBA()
+
+	// Transparent pixels are treated as background in every mode
+	if a <= binaryThreshold {
+		return 0
+	}
+
+	if o.Mode == ReliefBinary {
+		active := r > binaryThreshold
+		if o.Invert {
+			active = !active
+		}
+		if !active {
+			return 0
+		}
+		return o.MaxDepth
+	}
+
+	// RGBA() is alpha-premultiplied, so un-premultiply before computing luminance (Rec. 709)
+	luminance := (0.2126*float64(r) + 0.7152*float64(g) + 0.0722*float64(b)) / float64(a)
+	luminance = math.Max(0, math.Min(1, luminance))
+	if o.Invert {
+		luminance = 1 - luminance
+	}
+
+	depth := o.MinDepth + math.Pow(luminance, o.Gamma)*(o.MaxDepth-o.MinDepth)
+	if depth <= 0 {
+		return 0
+	}
+	return depth
+}
@@ -0,0 +1,151 @@
+package geometry
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestReliefOptionsValidate verifies relief option validation.
+func TestReliefOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ReliefOptions
+		wantErr bool
+	}{
+		{"zero value", ReliefOptions{}, false},
+		{"grayscale", ReliefOptions{Mode: ReliefGrayscale, MinDepth: 0.2, MaxDepth: 1.5, Gamma: 2.2}, false},
+		{"unknown mode", ReliefOptions{Mode: "sepia"}, true},
+		{"negative depth", ReliefOptions{Mode: ReliefGrayscale, MinDepth: -1}, true},
+		{"min above max", ReliefOptions{Mode: ReliefGrayscale, MinDepth: 2, MaxDepth: 1}, true},
+		{"negative gamma", ReliefOptions{Mode: ReliefGrayscale, Gamma: -1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestPixelDepth verifies the mapping from pixel colour to emboss depth.
+func TestPixelDepth(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	black := color.RGBA{0, 0, 0, 255}
+	gray := color.RGBA{128, 128, 128, 255}
+	transparent := color.RGBA{0, 0, 0, 0}
+
+	tests := []struct {
+		name  string
+		opts  ReliefOptions
+		pixel color.Color
+		want  float64
+	}{
+		{"binary white", ReliefOptions{}, white, voxelDepth},
+		{"binary black", ReliefOptions{}, black, 0},
+		{"binary inverted black", ReliefOptions{Invert: true}, black, voxelDepth},
+		{"binary transparent", ReliefOptions{Invert: true}, transparent, 0},
+		{"grayscale white", ReliefOptions{Mode: ReliefGrayscale, MaxDepth: 2}, white, 2},
+		{"grayscale black", ReliefOptions{Mode: ReliefGrayscale, MaxDepth: 2}, black, 0},
+		{"grayscale black with min depth", ReliefOptions{Mode: ReliefGrayscale, MinDepth: 0.5, MaxDepth: 2}, black, 0.5},
+		{"grayscale inverted white", ReliefOptions{Mode: ReliefGrayscale, MinDepth: 0.5, MaxDepth: 2, Invert: true}, white, 0.5},
+		{"grayscale gray", ReliefOptions{Mode: ReliefGrayscale, MaxDepth: 2}, gray, 2 * 128.0 / 255.0},
+		{"grayscale gray with gamma", ReliefOptions{Mode: ReliefGrayscale, MaxDepth: 2, Gamma: 2}, gray, 2 * math.Pow(128.0/255.0, 2)},
+		{"grayscale transparent", ReliefOptions{Mode: ReliefGrayscale, MinDepth: 0.5, MaxDepth: 2}, transparent, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.opts.withDefaults().pixelDepth(tt.pixel)
+			if math.Abs(got-tt.want) > 1e-3 {
+				t.Errorf("pixelDepth() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRenderImageRelief verifies grayscale images produce voxels of varying depth.
+func TestRenderImageRelief(t *testing.T) {
+	tmpfile, err := os.CreateTemp(t.TempDir(), "gradient-*.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A horizontal gradient from black to white
+	img := image.NewGray(image.Rect(0, 0, 4, 1))
+	for x := 0; x < 4; x++ {
+		img.SetGray(x, 0, color.Gray{Y: uint8(x * 85)})
+	}
+	if err := png.Encode(tmpfile, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := tmpfile.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	relief := ReliefOptions{Mode: ReliefGrayscale, MaxDepth: 3}
+	triangles, err := renderImageRelief(tmpfile.Name(), 1.0, relief, 0.1, 0.1, 200.0, 10.0)
+	if err != nil {
+		t.Fatalf("renderImageRelief failed: %v", err)
+	}
+
+	// The black pixel is skipped, leaving three voxels of 12 triangles each
+	if len(triangles) != 3*12 {
+		t.Fatalf("expected %d triangles, got %d", 3*12, len(triangles))
+	}
+
+	// Voxels extend out of the front face (negative Y), deeper for brighter pixels
+	depths := map[float64]bool{}
+	for _, tri := range triangles {
+		for _, v := range []float64{tri.V1.Y, tri.V2.Y, tri.V3.Y} {
+			if v < 0 {
+				depths[math.Round(-v*100)/100] = true
+			}
+		}
+	}
+	if len(depths) != 3 {
+		t.Errorf("expected 3 distinct relief depths, got %v", depths)
+	}
+	if !depths[3] {
+		t.Errorf("expected brightest pixel at full depth 3, got %v", depths)
+	}
+
+	if _, err := renderImageRelief(tmpfile.Name(), 1.0, ReliefOptions{Mode: "bogus"}, 0.1, 0.1, 200.0, 10.0); err == nil {
+		t.Error("expected error for invalid relief mode")
+	}
+}
+
+// TestReliefVoxelsOffsetBounds verifies an image whose bounds do not start at the
+// origin is embossed in full, in the same place as the same pixels starting at it.
+func TestReliefVoxelsOffsetBounds(t *testing.T) {
+	white := func(r image.Rectangle) *image.Gray {
+		img := image.NewGray(r)
+		for x := r.Min.X; x < r.Max.X; x++ {
+			for y := r.Min.Y; y < r.Max.Y; y++ {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+		return img
+	}
+	relief := ReliefOptions{}.withDefaults()
+
+	got, err := reliefVoxels(white(image.Rect(0, 0, 6, 4)).SubImage(image.Rect(2, 1, 6, 4)), 1.0, relief, 0.1, 0.1, 200.0, 10.0)
+	if err != nil {
+		t.Fatalf("reliefVoxels() error = %v", err)
+	}
+	want, err := reliefVoxels(white(image.Rect(0, 0, 4, 3)), 1.0, relief, 0.1, 0.1, 200.0, 10.0)
+	if err != nil {
+		t.Fatalf("reliefVoxels() error = %v", err)
+	}
+	// Every pixel of the 4x3 sub-image is embossed, where the same image at the origin would be
+	if len(got) != 4*3*12 || !reflect.DeepEqual(got, want) {
+		t.Errorf("sub-image gave %d triangles, want the %d of the same image at the origin", len(got), len(want))
+	}
+}
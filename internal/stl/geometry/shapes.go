@@ -45,6 +45,38 @@ func CreateCube(x, y, z, width, height, depth float64) ([]types.Triangle, error)
 	return createBox(x, y, z, width, height, depth)
 }
 
+// CreatePyramid generates triangles for a square pyramid whose base, with the
+// given side length, has its front left corner at (x, y, z).
+func CreatePyramid(x, y, z, size, height float64) ([]types.Triangle, error) {
+	if size <= 0 || height <= 0 {
+		return nil, errors.New(errors.ValidationError, "pyramid size and height must be positive", nil)
+	}
+
+	corners := []types.Point3D{
+		{X: x, Y: y, Z: z},
+		{X: x + size, Y: y, Z: z},
+		{X: x + size, Y: y + size, Z: z},
+		{X: x, Y: y + size, Z: z},
+	}
+	apex := types.Point3D{X: x + size/2, Y: y + size/2, Z: z + height}
+
+	triangles := make([]types.Triangle, 0, 6)
+	for i := range corners {
+		a, b := corners[i], corners[(i+1)%len(corners)]
+		normal, err := calculateNormal(a, b, apex)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to calculate pyramid normal")
+		}
+		triangles = append(triangles, types.Triangle{Normal: normal, V1: a, V2: b, V3: apex})
+	}
+
+	bottom, err := CreateQuad(corners[0], corners[3], corners[2], corners[1])
+	if err != nil {
+		return nil, errors.New(errors.STLError, "failed to create quad", err)
+	}
+	return append(triangles, bottom...), nil
+}
+
 // createBox is an internal helper function that generates triangles for a box shape.
 // The box is created in a right-handed coordinate system where:
 //   - X increases to the right
@@ -166,6 +166,43 @@ func TestCreateCube(t *testing.T) {
 	})
 }
 
+// TestCreatePyramid verifies pyramid creation
+func TestCreatePyramid(t *testing.T) {
+	t.Run("verify closed pyramid with outward normals", func(t *testing.T) {
+		triangles, err := CreatePyramid(1, 2, 3, 2, 4)
+		if err != nil {
+			t.Fatalf("CreatePyramid failed: %v", err)
+		}
+		if len(triangles) != 6 { // 4 sides + 2 for the square bottom
+			t.Fatalf("Expected 6 triangles, got %d", len(triangles))
+		}
+
+		center := types.Point3D{X: 2, Y: 3, Z: 4}
+		for i, tri := range triangles {
+			centroid := types.Point3D{
+				X: (tri.V1.X + tri.V2.X + tri.V3.X) / 3,
+				Y: (tri.V1.Y + tri.V2.Y + tri.V3.Y) / 3,
+				Z: (tri.V1.Z + tri.V2.Z + tri.V3.Z) / 3,
+			}
+			outward := vectorSubtract(centroid, center)
+			if outward.X*tri.Normal.X+outward.Y*tri.Normal.Y+outward.Z*tri.Normal.Z <= 0 {
+				t.Errorf("Triangle %d normal %+v points inwards", i, tri.Normal)
+			}
+		}
+
+		bounds := CalculateBoundingBox(triangles)
+		if bounds.Min != (types.Point3D{X: 1, Y: 2, Z: 3}) || bounds.Max != (types.Point3D{X: 3, Y: 4, Z: 7}) {
+			t.Errorf("Unexpected bounds %+v", bounds)
+		}
+	})
+
+	t.Run("verify zero dimensions", func(t *testing.T) {
+		if _, err := CreatePyramid(0, 0, 0, 0, 1); err == nil {
+			t.Error("Expected error for zero size")
+		}
+	})
+}
+
 // TestCreateBox verifies internal box creation functionality
 func TestCreateBox(t *testing.T) {
 	t.Run("verify negative dimensions", func(t *testing.T) {
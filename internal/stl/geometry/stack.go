@@ -0,0 +1,71 @@
+package geometry
+
+import (
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// ContributionType is a segment of the stacked contribution columns, printed as
+// its own object in multi-object formats.
+type ContributionType struct {
+	Name  string
+	Color string // Display colour as #RRGGBB
+}
+
+// ContributionTypes are the segments of a stacked column from the bottom up,
+// indexed like types.ContributionBreakdown.Counts.
+var ContributionTypes = []ContributionType{
+	{Name: "commits", Color: "#2DA44E"},
+	{Name: "pull requests", Color: "#8250DF"},
+	{Name: "reviews", Color: "#0969DA"},
+	{Name: "issues", Color: "#BF8700"},
+	{Name: "other contributions", Color: "#8C959F"},
+}
+
+// dayCounts returns the contributions of a day by type, with all of them counted
+// as other when the day has no breakdown.
+func dayCounts(day types.ContributionDay) []int {
+	if day.Breakdown != nil {
+		counts := day.Breakdown.Counts()
+		for _, count := range counts {
+			if count > 0 {
+				return counts
+			}
+		}
+	}
+	return types.ContributionBreakdown{Other: day.ContributionCount}.Counts()
+}
+
+// CreateStackedColumn creates a column of the given height as one box per
+// non-zero count, stacked in order with heights proportional to the counts.
+// The result is indexed like counts, with no triangles for zero counts.
+func CreateStackedColumn(x, y, height float64, counts []int) ([][]types.Triangle, error) {
+	total := 0
+	for _, count := range counts {
+		if count < 0 {
+			return nil, errors.New(errors.ValidationError, "contribution counts cannot be negative", nil)
+		}
+		total += count
+	}
+	if total == 0 || height <= 0 {
+		return nil, errors.New(errors.ValidationError, "stacked column needs a positive height and count", nil)
+	}
+
+	segments := make([][]types.Triangle, len(counts))
+	z, seen := 0.0, 0
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		// Each top is placed from the running count, so the last segment ends exactly at height
+		seen += count
+		top := height * float64(seen) / float64(total)
+		box, err := createBox(x, y, z, CellSize, CellSize, top-z)
+		if err != nil {
+			return nil, err
+		}
+		segments[i] = box
+		z = top
+	}
+	return segments, nil
+}
@@ -0,0 +1,69 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestCreateStackedColumn(t *testing.T) {
+	tests := []struct {
+		name     string
+		counts   []int
+		wantTops []float64 // Top of each segment, zero for empty segments
+		wantErr  bool
+	}{
+		{name: "two types", counts: []int{3, 0, 1, 0, 0}, wantTops: []float64{7.5, 0, 10, 0, 0}},
+		{name: "one type", counts: []int{0, 0, 0, 0, 2}, wantTops: []float64{0, 0, 0, 0, 10}},
+		{name: "no contributions", counts: []int{0, 0, 0, 0, 0}, wantErr: true},
+		{name: "negative count", counts: []int{2, -1, 0, 0, 0}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments, err := CreateStackedColumn(0, 0, 10, tt.counts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateStackedColumn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			bottom := 0.0
+			for i, segment := range segments {
+				if tt.wantTops[i] == 0 {
+					if len(segment) != 0 {
+						t.Errorf("segment %d has %d triangles, want none", i, len(segment))
+					}
+					continue
+				}
+				bounds := CalculateBoundingBox(segment)
+				if len(segment) != 12 || math.Abs(bounds.Min.Z-bottom) > 1e-9 || math.Abs(bounds.Max.Z-tt.wantTops[i]) > 1e-9 {
+					t.Errorf("segment %d spans %.2f-%.2f in %d triangles, want %.2f-%.2f in 12", i, bounds.Min.Z, bounds.Max.Z, len(segment), bottom, tt.wantTops[i])
+				}
+				bottom = tt.wantTops[i]
+			}
+		})
+	}
+}
+
+func TestCreateStackedContributionGeometry(t *testing.T) {
+	contributions := [][]types.ContributionDay{{
+		{Date: "2024-01-01", ContributionCount: 4, Breakdown: &types.ContributionBreakdown{Commits: 2, Issues: 2}},
+		{Date: "2024-01-02", ContributionCount: 1}, // No breakdown counts as other
+		{Date: "2024-01-03"},
+	}}
+	marked, err := CreateStackedContributionGeometry(contributions, 0, 4, nil)
+	if err != nil {
+		t.Fatalf("CreateStackedContributionGeometry() error = %v", err)
+	}
+	if len(marked.Columns) != 0 {
+		t.Errorf("stacked geometry has %d plain column triangles, want none", len(marked.Columns))
+	}
+	want := []int{12, 0, 0, 12, 12}
+	for i, segment := range marked.Types {
+		if len(segment) != want[i] {
+			t.Errorf("%s has %d triangles, want %d", ContributionTypes[i].Name, len(segment), want[i])
+		}
+	}
+}
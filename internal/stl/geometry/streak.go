@@ -0,0 +1,105 @@
+package geometry
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// StreakKind selects which contribution streak is highlighted.
+type StreakKind string
+
+// Supported streak kinds.
+const (
+	// StreakLongest highlights the longest streak in the model.
+	StreakLongest StreakKind = "longest"
+	// StreakCurrent highlights the streak still running today.
+	StreakCurrent StreakKind = "current"
+)
+
+// DefaultStreakColor is the display colour of the streak rail in multi-object formats.
+const DefaultStreakColor = "#FF4500"
+
+const (
+	streakRailWidth  = 1.0 // Width of the rail on top of the columns (mm)
+	streakRailHeight = 1.2 // Height of the rail above the column tops (mm)
+)
+
+// StreakOptions controls the streak highlight. The zero value disables it.
+type StreakOptions struct {
+	Kind  StreakKind // Streak to highlight; empty disables the highlight
+	Color string     // Display colour of the rail as #RRGGBB
+	Slot  string     // Text slot the streak length is embossed in (default top-back)
+}
+
+// withDefaults fills unset fields with the defaults.
+func (o StreakOptions) withDefaults() StreakOptions {
+	if o.Color == "" {
+		o.Color = DefaultStreakColor
+	}
+	if o.Slot == "" {
+		o.Slot = SlotTopBack
+	}
+	return o
+}
+
+// Validate checks the options of an enabled streak highlight.
+func (o StreakOptions) Validate() error {
+	if o.Kind == "" {
+		return nil
+	}
+	if o.Kind != StreakLongest && o.Kind != StreakCurrent {
+		return errors.New(errors.ValidationError, fmt.Sprintf("unknown streak %q (expected %q or %q)", o.Kind, StreakLongest, StreakCurrent), nil)
+	}
+	if !hexColor.MatchString(o.withDefaults().Color) {
+		return errors.New(errors.ValidationError, fmt.Sprintf("invalid streak color %q (expected #RRGGBB)", o.Color), nil)
+	}
+	return nil
+}
+
+// Find returns the selected streak of the contribution grid as of today.
+func (o StreakOptions) Find(weeks [][]types.ContributionDay, today time.Time) types.Streak {
+	if o.Kind == StreakCurrent {
+		return types.CurrentStreak(weeks, today)
+	}
+	return types.LongestStreak(weeks)
+}
+
+// TextBlock returns the text block embossing the streak length.
+func (o StreakOptions) TextBlock(streak types.Streak) TextBlock {
+	return TextBlock{Slot: o.withDefaults().Slot, Text: StreakLabel(streak)}
+}
+
+// RailColor returns the display colour of the streak rail.
+func (o StreakOptions) RailColor() string {
+	return o.withDefaults().Color
+}
+
+// StreakLabel describes the length of a streak, e.g. "42-day streak".
+func StreakLabel(streak types.Streak) string {
+	return fmt.Sprintf("%d-day streak", streak.Length)
+}
+
+// CreateStreakRail creates the rail that marks a streak in a year's contribution
+// grid: a narrow ridge along the middle of each streak column's top, stepping with
+// the column heights. Days of the streak outside this year are left out.
+func CreateStreakRail(contributions [][]types.ContributionDay, yearIndex int, maxContrib int, streak types.Streak) ([]types.Triangle, error) {
+	var triangles []types.Triangle
+	for weekIdx, week := range contributions {
+		for dayIdx, day := range week {
+			if !streak.Contains(day.Date) {
+				continue
+			}
+			height := NormalizeContribution(day.ContributionCount, maxContrib)
+			x, y := columnPosition(weekIdx, dayIdx, yearIndex)
+			rail, err := CreateCube(x+(CellSize-streakRailWidth)/2, y, height, streakRailWidth, CellSize, streakRailHeight)
+			if err != nil {
+				return nil, err
+			}
+			triangles = append(triangles, rail...)
+		}
+	}
+	return triangles, nil
+}
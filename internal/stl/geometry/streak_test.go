@@ -0,0 +1,119 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func TestStreakOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    StreakOptions
+		wantErr bool
+	}{
+		{name: "disabled", opts: StreakOptions{}},
+		{name: "longest", opts: StreakOptions{Kind: StreakLongest}},
+		{name: "current with color", opts: StreakOptions{Kind: StreakCurrent, Color: "#00ff00"}},
+		{name: "unknown kind", opts: StreakOptions{Kind: "best"}, wantErr: true},
+		{name: "invalid color", opts: StreakOptions{Kind: StreakLongest, Color: "red"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opts.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStreakOptionsFind(t *testing.T) {
+	weeks := [][]types.ContributionDay{{
+		{Date: "2024-03-10", ContributionCount: 1},
+		{Date: "2024-03-11", ContributionCount: 1},
+		{Date: "2024-03-12", ContributionCount: 1},
+		{Date: "2024-03-13", ContributionCount: 0},
+		{Date: "2024-03-14", ContributionCount: 3},
+	}}
+	today := time.Date(2024, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	if got := (StreakOptions{Kind: StreakLongest}).Find(weeks, today); got.Length != 3 || got.Start != "2024-03-10" {
+		t.Errorf("longest streak = %+v, want 3 days from 2024-03-10", got)
+	}
+	if got := (StreakOptions{Kind: StreakCurrent}).Find(weeks, today); got.Length != 1 || got.Start != "2024-03-14" {
+		t.Errorf("current streak = %+v, want 1 day on 2024-03-14", got)
+	}
+}
+
+func TestStreakTextBlock(t *testing.T) {
+	streak := types.Streak{Length: 42, Start: "2024-01-01", End: "2024-02-11"}
+	block := StreakOptions{Kind: StreakLongest}.TextBlock(streak)
+	if block.Slot != SlotTopBack || block.Text != "42-day streak" {
+		t.Errorf("TextBlock() = %+v, want 42-day streak in %s", block, SlotTopBack)
+	}
+	if block := (StreakOptions{Kind: StreakLongest, Slot: SlotBack}).TextBlock(streak); block.Slot != SlotBack {
+		t.Errorf("TextBlock() slot = %s, want %s", block.Slot, SlotBack)
+	}
+}
+
+func TestCreateStreakRail(t *testing.T) {
+	contributions := [][]types.ContributionDay{
+		{
+			{Date: "2024-03-10", ContributionCount: 4},
+			{Date: "2024-03-11", ContributionCount: 1},
+		},
+		{
+			{Date: "2024-03-17", ContributionCount: 2},
+		},
+	}
+	const cubeTriangles = 12
+
+	tests := []struct {
+		name      string
+		streak    types.Streak
+		wantCount int
+		wantTop   float64
+	}{
+		{name: "no streak", streak: types.Streak{}},
+		{
+			name:      "streak within a week",
+			streak:    types.Streak{Length: 2, Start: "2024-03-10", End: "2024-03-11"},
+			wantCount: 2 * cubeTriangles,
+			wantTop:   MaxHeight + streakRailHeight,
+		},
+		{
+			name:      "streak partly outside the year",
+			streak:    types.Streak{Length: 5, Start: "2024-03-17", End: "2024-03-21"},
+			wantCount: cubeTriangles,
+			wantTop:   NormalizeContribution(2, 4) + streakRailHeight,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CreateStreakRail(contributions, 1, 4, tt.streak)
+			if err != nil {
+				t.Fatalf("CreateStreakRail() error = %v", err)
+			}
+			if len(got) != tt.wantCount {
+				t.Fatalf("got %d triangles, want %d", len(got), tt.wantCount)
+			}
+			if tt.wantCount == 0 {
+				return
+			}
+			bounds := CalculateBoundingBox(got)
+			if math.Abs(bounds.Max.Z-tt.wantTop) > 1e-9 {
+				t.Errorf("rail top = %.3f, want %.3f", bounds.Max.Z, tt.wantTop)
+			}
+			if width := bounds.Max.X - bounds.Min.X; tt.streak.Start == "2024-03-10" && math.Abs(width-streakRailWidth) > 1e-9 {
+				t.Errorf("rail width = %.3f, want %.3f", width, streakRailWidth)
+			}
+			if bounds.Min.Y < 2*CellSize+7*CellSize {
+				t.Errorf("rail starts at y = %.3f, before the second year's grid", bounds.Min.Y)
+			}
+		})
+	}
+}
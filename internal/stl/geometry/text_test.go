@@ -15,7 +15,7 @@ import (
 func TestCreate3DText(t *testing.T) {
 
 	t.Run("verify basic text mesh generation", func(t *testing.T) {
-		triangles, err := Create3DText("test", "2023", 100.0, 5.0)
+		triangles, err := Create3DText("test", "2023", 100.0, 5.0, 20.0, "", TextFonts{})
 		if err != nil {
 			t.Fatalf("Create3DText failed: %v", err)
 		}
@@ -25,7 +25,7 @@ func TestCreate3DText(t *testing.T) {
 	})
 
 	t.Run("verify text generation with empty username", func(t *testing.T) {
-		triangles, err := Create3DText("", "2023", 100.0, 5.0)
+		triangles, err := Create3DText("", "2023", 100.0, 5.0, 20.0, "", TextFonts{})
 		if err != nil {
 			t.Fatalf("Create3DText failed with empty username: %v", err)
 		}
@@ -35,7 +35,7 @@ func TestCreate3DText(t *testing.T) {
 	})
 
 	t.Run("verify normal vectors of text geometry", func(t *testing.T) {
-		triangles, err := Create3DText("test", "2023", 100.0, 5.0)
+		triangles, err := Create3DText("test", "2023", 100.0, 5.0, 20.0, "", TextFonts{})
 		if err != nil {
 			t.Fatalf("Create3DText failed: %v", err)
 		}
@@ -57,22 +57,27 @@ func TestCreate3DText(t *testing.T) {
 	})
 }
 
-// TestRenderText verifies internal text rendering functionality
-func TestRenderText(t *testing.T) {
+// TestRenderBlock verifies internal text rendering functionality
+func TestRenderBlock(t *testing.T) {
 	t.Run("verify text renders", func(t *testing.T) {
-		triangles, err := renderText(
-			"Mona", // text
-			"left", // justification
-			0.1,    // leftOffsetPercent
-			10.0,   // fontSize
-			200.0,  // baseWidth
-			10.0,   // baseHeight
+		fonts, err := DefaultFontRegistry().Chain("Mona")
+		if err != nil {
+			t.Fatalf("Chain failed: %v", err)
+		}
+		slot := TextSlots(200.0, 20.0, 10.0)[0]
+		placed, err := renderBlock(
+			TextBlock{Slot: slot.Name, Text: "Mona"}, // block
+			slot,                                     // slot
+			fonts,                                    // fonts
+			10.0,                                     // pixelsPerMM
+			200.0,                                    // baseWidth
+			20.0,                                     // baseDepth
 		)
 
 		if err != nil {
-			t.Fatalf("renderText failed: %v", err)
+			t.Fatalf("renderBlock failed: %v", err)
 		}
-		if len(triangles) == 0 {
+		if len(placed.Triangles) == 0 {
 			t.Error("Expected non-zero triangles for rendered text")
 		}
 	})
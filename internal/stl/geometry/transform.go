@@ -0,0 +1,275 @@
+package geometry
+
+import (
+	"math"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Matrix4 is a 4x4 affine transformation matrix in row-major order.
+// Points are treated as column vectors, so M.Mul(N) applies N first and then M.
+type Matrix4 [4][4]float64
+
+// Identity returns the identity transformation.
+func Identity() Matrix4 {
+	return Matrix4{
+		{1, 0, 0, 0},
+		{0, 1, 0, 0},
+		{0, 0, 1, 0},
+		{0, 0, 0, 1},
+	}
+}
+
+// Translation returns a transformation that moves points by (dx, dy, dz).
+func Translation(dx, dy, dz float64) Matrix4 {
+	m := Identity()
+	m[0][3], m[1][3], m[2][3] = dx, dy, dz
+	return m
+}
+
+// Scaling returns a non-uniform scaling about the origin.
+// Negative factors mirror along the corresponding axis.
+func Scaling(sx, sy, sz float64) Matrix4 {
+	m := Identity()
+	m[0][0], m[1][1], m[2][2] = sx, sy, sz
+	return m
+}
+
+// Rotation returns a rotation about an axis through the origin, by the given angle
+// in degrees, following the right-hand rule.
+func Rotation(axis types.Point3D, degrees float64) (Matrix4, error) {
+	if err := validateVector(axis); err != nil {
+		return Matrix4{}, err
+	}
+	if isZeroVector(axis) {
+		return Matrix4{}, errors.New(errors.ValidationError, "rotation axis cannot be zero", nil)
+	}
+
+	a := normalizeVector(axis)
+	sin, cos := math.Sincos(degrees * math.Pi / 180)
+	t := 1 - cos
+
+	// Rodrigues' rotation formula in matrix form
+	return Matrix4{
+		{t*a.X*a.X + cos, t*a.X*a.Y - sin*a.Z, t*a.X*a.Z + sin*a.Y, 0},
+		{t*a.X*a.Y + sin*a.Z, t*a.Y*a.Y + cos, t*a.Y*a.Z - sin*a.X, 0},
+		{t*a.X*a.Z - sin*a.Y, t*a.Y*a.Z + sin*a.X, t*a.Z*a.Z + cos, 0},
+		{0, 0, 0, 1},
+	}, nil
+}
+
+// RotationZ returns a rotation about the vertical axis by the given angle in degrees.
+func RotationZ(degrees float64) Matrix4 {
+	m, _ := Rotation(types.Point3D{Z: 1}, degrees) // The Z axis is always valid
+	return m
+}
+
+// Mirror returns a reflection through the plane that passes through the origin
+// with the given normal.
+func Mirror(normal types.Point3D) (Matrix4, error) {
+	if err := validateVector(normal); err != nil {
+		return Matrix4{}, err
+	}
+	if isZeroVector(normal) {
+		return Matrix4{}, errors.New(errors.ValidationError, "mirror plane normal cannot be zero", nil)
+	}
+
+	n := normalizeVector(normal)
+	// Householder reflection: I - 2nn^T
+	return Matrix4{
+		{1 - 2*n.X*n.X, -2 * n.X * n.Y, -2 * n.X * n.Z, 0},
+		{-2 * n.Y * n.X, 1 - 2*n.Y*n.Y, -2 * n.Y * n.Z, 0},
+		{-2 * n.Z * n.X, -2 * n.Z * n.Y, 1 - 2*n.Z*n.Z, 0},
+		{0, 0, 0, 1},
+	}, nil
+}
+
+// Mul returns the product m·n, the transformation that applies n first and then m.
+func (m Matrix4) Mul(n Matrix4) Matrix4 {
+	var result Matrix4
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 4; j++ {
+			for k := 0; k < 4; k++ {
+				result[i][j] += m[i][k] * n[k][j]
+			}
+		}
+	}
+	return result
+}
+
+// Then returns the transformation that applies m first and then n.
+func (m Matrix4) Then(n Matrix4) Matrix4 {
+	return n.Mul(m)
+}
+
+// TransformPoint applies the transformation to a point.
+func (m Matrix4) TransformPoint(p types.Point3D) types.Point3D {
+	return types.Point3D{
+		X: m[0][0]*p.X + m[0][1]*p.Y + m[0][2]*p.Z + m[0][3],
+		Y: m[1][0]*p.X + m[1][1]*p.Y + m[1][2]*p.Z + m[1][3],
+		Z: m[2][0]*p.X + m[2][1]*p.Y + m[2][2]*p.Z + m[2][3],
+	}
+}
+
+// TransformNormal applies the transformation to a surface normal using the inverse
+// transpose of the linear part, so normals stay perpendicular under non-uniform scaling.
+// The result is normalized.
+func (m Matrix4) TransformNormal(n types.Point3D) types.Point3D {
+	// The cofactor matrix equals det(M)·M^-T, which avoids dividing by the determinant
+	c := m.cofactors()
+	transformed := types.Point3D{
+		X: c[0][0]*n.X + c[0][1]*n.Y + c[0][2]*n.Z,
+		Y: c[1][0]*n.X + c[1][1]*n.Y + c[1][2]*n.Z,
+		Z: c[2][0]*n.X + c[2][1]*n.Y + c[2][2]*n.Z,
+	}
+	if m.Determinant() < 0 {
+		transformed = types.Point3D{X: -transformed.X, Y: -transformed.Y, Z: -transformed.Z}
+	}
+	return normalizeVector(transformed)
+}
+
+// Determinant returns the determinant of the linear (3x3) part of the transformation.
+// A negative determinant means the transformation mirrors geometry.
+func (m Matrix4) Determinant() float64 {
+	return m[0][0]*(m[1][1]*m[2][2]-m[1][2]*m[2][1]) -
+		m[0][1]*(m[1][0]*m[2][2]-m[1][2]*m[2][0]) +
+		m[0][2]*(m[1][0]*m[2][1]-m[1][1]*m[2][0])
+}
+
+// cofactors returns the cofactor matrix of the linear part of the transformation.
+func (m Matrix4) cofactors() [3][3]float64 {
+	return [3][3]float64{
+		{
+			m[1][1]*m[2][2] - m[1][2]*m[2][1],
+			m[1][2]*m[2][0] - m[1][0]*m[2][2],
+			m[1][0]*m[2][1] - m[1][1]*m[2][0],
+		},
+		{
+			m[0][2]*m[2][1] - m[0][1]*m[2][2],
+			m[0][0]*m[2][2] - m[0][2]*m[2][0],
+			m[0][1]*m[2][0] - m[0][0]*m[2][1],
+		},
+		{
+			m[0][1]*m[1][2] - m[0][2]*m[1][1],
+			m[0][2]*m[1][0] - m[0][0]*m[1][2],
+			m[0][0]*m[1][1] - m[0][1]*m[1][0],
+		},
+	}
+}
+
+// TransformTriangles applies the transformation to every triangle, returning a new slice.
+// Normals are transformed correctly and, when the transformation mirrors geometry,
+// the vertex winding is reversed so that it still agrees with the outward normal.
+func TransformTriangles(triangles []types.Triangle, m Matrix4) []types.Triangle {
+	mirrored := m.Determinant() < 0
+	transformed := make([]types.Triangle, len(triangles))
+	for i, t := range triangles {
+		v1, v2, v3 := m.TransformPoint(t.V1), m.TransformPoint(t.V2), m.TransformPoint(t.V3)
+		if mirrored {
+			v2, v3 = v3, v2
+		}
+		transformed[i] = types.Triangle{
+			Normal: m.TransformNormal(t.Normal),
+			V1:     v1,
+			V2:     v2,
+			V3:     v3,
+		}
+	}
+	return transformed
+}
+
+// TranslateTriangles moves triangles by (dx, dy, dz).
+func TranslateTriangles(triangles []types.Triangle, dx, dy, dz float64) []types.Triangle {
+	return TransformTriangles(triangles, Translation(dx, dy, dz))
+}
+
+// ScaleTriangles scales triangles uniformly about the origin.
+func ScaleTriangles(triangles []types.Triangle, scale float64) []types.Triangle {
+	return TransformTriangles(triangles, Scaling(scale, scale, scale))
+}
+
+// BoundingBox is an axis-aligned box enclosing a set of points.
+type BoundingBox struct {
+	Min, Max types.Point3D
+}
+
+// CalculateBoundingBox returns the axis-aligned bounding box of the triangles.
+// An empty slice yields an empty box (Min greater than Max).
+func CalculateBoundingBox(triangles []types.Triangle) BoundingBox {
+	box := BoundingBox{
+		Min: types.Point3D{X: math.MaxFloat64, Y: math.MaxFloat64, Z: math.MaxFloat64},
+		Max: types.Point3D{X: -math.MaxFloat64, Y: -math.MaxFloat64, Z: -math.MaxFloat64},
+	}
+	for _, t := range triangles {
+		for _, v := range []types.Point3D{t.V1, t.V2, t.V3} {
+			box.Min.X = math.Min(box.Min.X, v.X)
+			box.Min.Y = math.Min(box.Min.Y, v.Y)
+			box.Min.Z = math.Min(box.Min.Z, v.Z)
+			box.Max.X = math.Max(box.Max.X, v.X)
+			box.Max.Y = math.Max(box.Max.Y, v.Y)
+			box.Max.Z = math.Max(box.Max.Z, v.Z)
+		}
+	}
+	return box
+}
+
+// IsEmpty reports whether the box encloses no points.
+func (b BoundingBox) IsEmpty() bool {
+	return b.Min.X > b.Max.X || b.Min.Y > b.Max.Y || b.Min.Z > b.Max.Z
+}
+
+// Size returns the extent of the box along each axis.
+func (b BoundingBox) Size() types.Point3D {
+	if b.IsEmpty() {
+		return types.Point3D{}
+	}
+	return vectorSubtract(b.Max, b.Min)
+}
+
+// Center returns the midpoint of the box.
+func (b BoundingBox) Center() types.Point3D {
+	return types.Point3D{
+		X: (b.Min.X + b.Max.X) / 2,
+		Y: (b.Min.Y + b.Max.Y) / 2,
+		Z: (b.Min.Z + b.Max.Z) / 2,
+	}
+}
+
+// Intersects reports whether two boxes overlap by more than touching faces.
+func (b BoundingBox) Intersects(other BoundingBox) bool {
+	if b.IsEmpty() || other.IsEmpty() {
+		return false
+	}
+	return b.Min.X < other.Max.X && other.Min.X < b.Max.X &&
+		b.Min.Y < other.Max.Y && other.Min.Y < b.Max.Y &&
+		b.Min.Z < other.Max.Z && other.Min.Z < b.Max.Z
+}
+
+// Union returns the smallest box enclosing both boxes.
+func (b BoundingBox) Union(other BoundingBox) BoundingBox {
+	if b.IsEmpty() {
+		return other
+	}
+	if other.IsEmpty() {
+		return b
+	}
+	return BoundingBox{
+		Min: types.Point3D{X: math.Min(b.Min.X, other.Min.X), Y: math.Min(b.Min.Y, other.Min.Y), Z: math.Min(b.Min.Z, other.Min.Z)},
+		Max: types.Point3D{X: math.Max(b.Max.X, other.Max.X), Y: math.Max(b.Max.Y, other.Max.Y), Z: math.Max(b.Max.Z, other.Max.Z)},
+	}
+}
+
+// CenterTriangles moves triangles so that the center of their bounding box is at the origin.
+func CenterTriangles(triangles []types.Triangle) []types.Triangle {
+	return MoveCenterTo(triangles, types.Point3D{})
+}
+
+// MoveCenterTo moves triangles so that the center of their bounding box is at the target.
+func MoveCenterTo(triangles []types.Triangle, target types.Point3D) []types.Triangle {
+	if len(triangles) == 0 {
+		return []types.Triangle{}
+	}
+	c := CalculateBoundingBox(triangles).Center()
+	return TranslateTriangles(triangles, target.X-c.X, target.Y-c.Y, target.Z-c.Z)
+}
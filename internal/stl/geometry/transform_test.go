@@ -0,0 +1,167 @@
+package geometry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const transformEpsilon = 1e-9
+
+func pointsClose(a, b types.Point3D) bool {
+	return math.Abs(a.X-b.X) < transformEpsilon &&
+		math.Abs(a.Y-b.Y) < transformEpsilon &&
+		math.Abs(a.Z-b.Z) < transformEpsilon
+}
+
+// TestMatrixTransforms verifies point transformation for each matrix constructor.
+func TestMatrixTransforms(t *testing.T) {
+	p := types.Point3D{X: 1, Y: 2, Z: 3}
+
+	rotZ, err := Rotation(types.Point3D{Z: 2}, 90)
+	if err != nil {
+		t.Fatalf("Rotation() error = %v", err)
+	}
+	rotDiag, err := Rotation(types.Point3D{X: 1, Y: 1, Z: 1}, 120)
+	if err != nil {
+		t.Fatalf("Rotation() error = %v", err)
+	}
+	mirrorX, err := Mirror(types.Point3D{X: 1})
+	if err != nil {
+		t.Fatalf("Mirror() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		m    Matrix4
+		want types.Point3D
+	}{
+		{"identity", Identity(), p},
+		{"translation", Translation(1, -2, 0.5), types.Point3D{X: 2, Y: 0, Z: 3.5}},
+		{"scaling", Scaling(2, 3, -1), types.Point3D{X: 2, Y: 6, Z: -3}},
+		{"rotation about Z", rotZ, types.Point3D{X: -2, Y: 1, Z: 3}},
+		{"rotation helper", RotationZ(90), types.Point3D{X: -2, Y: 1, Z: 3}},
+		{"rotation about diagonal cycles axes", rotDiag, types.Point3D{X: 3, Y: 1, Z: 2}},
+		{"mirror", mirrorX, types.Point3D{X: -1, Y: 2, Z: 3}},
+		{"scale then translate", Scaling(2, 2, 2).Then(Translation(1, 0, 0)), types.Point3D{X: 3, Y: 4, Z: 6}},
+		{"translate then scale", Translation(1, 0, 0).Then(Scaling(2, 2, 2)), types.Point3D{X: 4, Y: 4, Z: 6}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.TransformPoint(p); !pointsClose(got, tt.want) {
+				t.Errorf("TransformPoint() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestInvalidTransforms verifies degenerate axes and plane normals are rejected.
+func TestInvalidTransforms(t *testing.T) {
+	if _, err := Rotation(types.Point3D{}, 45); err == nil {
+		t.Error("Rotation() expected error for zero axis")
+	}
+	if _, err := Rotation(types.Point3D{X: math.NaN()}, 45); err == nil {
+		t.Error("Rotation() expected error for NaN axis")
+	}
+	if _, err := Mirror(types.Point3D{}); err == nil {
+		t.Error("Mirror() expected error for zero normal")
+	}
+}
+
+// TestTransformNormal verifies normals stay perpendicular to transformed surfaces.
+func TestTransformNormal(t *testing.T) {
+	t.Run("non-uniform scale", func(t *testing.T) {
+		// A plane x + y = 0 with normal (1,1,0)/√2; stretching X by 2 tilts the plane
+		got := Scaling(2, 1, 1).TransformNormal(normalizeVector(types.Point3D{X: 1, Y: 1}))
+		want := normalizeVector(types.Point3D{X: 0.5, Y: 1})
+		if !pointsClose(got, want) {
+			t.Errorf("TransformNormal() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("mirror flips normal", func(t *testing.T) {
+		m, _ := Mirror(types.Point3D{X: 1})
+		got := m.TransformNormal(types.Point3D{X: 1})
+		if !pointsClose(got, types.Point3D{X: -1}) {
+			t.Errorf("TransformNormal() = %+v, want (-1, 0, 0)", got)
+		}
+	})
+}
+
+// TestTransformTriangles verifies normals and winding stay consistent after transformation.
+func TestTransformTriangles(t *testing.T) {
+	cube, err := CreateCube(0, 0, 0, 1, 2, 3)
+	if err != nil {
+		t.Fatalf("CreateCube() error = %v", err)
+	}
+
+	mirror, _ := Mirror(types.Point3D{X: 1, Y: 1})
+	rotation, _ := Rotation(types.Point3D{X: 1, Y: 2, Z: 3}, 33)
+	transforms := map[string]Matrix4{
+		"rotation":          rotation,
+		"non-uniform scale": Scaling(3, 0.5, 2),
+		"mirror":            mirror,
+		"negative scale":    Scaling(-1, 1, 1),
+		"composite":         Scaling(-2, 1, 1).Then(rotation).Then(Translation(5, 5, 5)),
+	}
+
+	for name, m := range transforms {
+		t.Run(name, func(t *testing.T) {
+			for i, tri := range TransformTriangles(cube, m) {
+				computed, err := calculateNormal(tri.V1, tri.V2, tri.V3)
+				if err != nil {
+					t.Fatalf("triangle %d is degenerate: %v", i, err)
+				}
+				if !pointsClose(computed, tri.Normal) {
+					t.Errorf("triangle %d normal %+v disagrees with winding %+v", i, tri.Normal, computed)
+				}
+			}
+		})
+	}
+}
+
+// TestBoundingBox verifies bounding box helpers.
+func TestBoundingBox(t *testing.T) {
+	cube, err := CreateCube(1, 2, 3, 4, 5, 6)
+	if err != nil {
+		t.Fatalf("CreateCube() error = %v", err)
+	}
+
+	box := CalculateBoundingBox(cube)
+	if !pointsClose(box.Min, types.Point3D{X: 1, Y: 2, Z: 3}) || !pointsClose(box.Max, types.Point3D{X: 5, Y: 7, Z: 9}) {
+		t.Errorf("CalculateBoundingBox() = %+v", box)
+	}
+	if !pointsClose(box.Size(), types.Point3D{X: 4, Y: 5, Z: 6}) {
+		t.Errorf("Size() = %+v", box.Size())
+	}
+	if !pointsClose(box.Center(), types.Point3D{X: 3, Y: 4.5, Z: 6}) {
+		t.Errorf("Center() = %+v", box.Center())
+	}
+
+	empty := CalculateBoundingBox(nil)
+	if !empty.IsEmpty() || !pointsClose(empty.Size(), types.Point3D{}) {
+		t.Errorf("expected empty box for no triangles, got %+v", empty)
+	}
+	if !pointsClose(empty.Union(box).Min, box.Min) {
+		t.Error("Union() with empty box should return the other box")
+	}
+
+	touching := BoundingBox{Min: types.Point3D{X: 5, Y: 2, Z: 3}, Max: types.Point3D{X: 6, Y: 7, Z: 9}}
+	overlapping := BoundingBox{Min: types.Point3D{X: 4, Y: 2, Z: 3}, Max: types.Point3D{X: 6, Y: 7, Z: 9}}
+	if box.Intersects(touching) {
+		t.Error("Intersects() should ignore boxes that only touch")
+	}
+	if !box.Intersects(overlapping) {
+		t.Error("Intersects() should detect overlapping boxes")
+	}
+
+	centered := CalculateBoundingBox(CenterTriangles(cube))
+	if !pointsClose(centered.Center(), types.Point3D{}) || !pointsClose(centered.Size(), box.Size()) {
+		t.Errorf("CenterTriangles() produced box %+v", centered)
+	}
+	if len(CenterTriangles(nil)) != 0 {
+		t.Error("CenterTriangles() should return an empty slice for no triangles")
+	}
+}
@@ -0,0 +1,325 @@
+package geometry
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+const (
+	// weldTolerance is the distance (mm) below which vertices are considered the same point.
+	// It is coarser than float32 rounding in STL files, which is ~1e-5 mm at model scale.
+	weldTolerance = 1e-4
+
+	// degenerateArea is the triangle area (mm²) below which a triangle is considered degenerate.
+	degenerateArea = 1e-10
+
+	// maxOverlapSamples limits how many points of a shell are tested against another shell.
+	maxOverlapSamples = 2000
+)
+
+// MeshReport summarises the defects found in a triangle mesh.
+type MeshReport struct {
+	Triangles           int // Total number of triangles
+	Shells              int // Number of connected components
+	DegenerateTriangles int // Triangles with (near) zero area
+	BoundaryEdges       int // Edges used by a single triangle (holes)
+	NonManifoldEdges    int // Edges shared by more than two triangles, other than contact edges
+	ContactEdges        int // Edges where closed boxes touch, crossed equally often in each direction
+	InconsistentEdges   int // Edges whose two triangles traverse them in the same direction
+	FlippedNormals      int // Stored normals pointing against the vertex winding
+	OverlappingShells   int // Pairs of shells whose volumes intersect
+}
+
+// HasDefects reports whether any defect was found.
+func (r MeshReport) HasDefects() bool {
+	return len(r.Defects()) > 0
+}
+
+// Defects returns a human-readable line for each kind of defect found.
+func (r MeshReport) Defects() []string {
+	var defects []string
+	add := func(count int, description string) {
+		if count > 0 {
+			defects = append(defects, fmt.Sprintf("%d %s", count, description))
+		}
+	}
+	add(r.DegenerateTriangles, "degenerate triangles")
+	add(r.BoundaryEdges, "open boundary edges")
+	add(r.NonManifoldEdges, "non-manifold edges")
+	add(r.InconsistentEdges, "edges with inconsistent winding")
+	add(r.FlippedNormals, "normals flipped against vertex order")
+	add(r.OverlappingShells, "pairs of overlapping shells")
+	return defects
+}
+
+// String returns a multi-line summary of the report.
+func (r MeshReport) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Triangles: %d\n", r.Triangles)
+	fmt.Fprintf(&b, "Shells:    %d\n", r.Shells)
+	if r.ContactEdges > 0 {
+		fmt.Fprintf(&b, "Contacts:  %d edges where boxes touch\n", r.ContactEdges)
+	}
+	defects := r.Defects()
+	if len(defects) == 0 {
+		b.WriteString("No defects found\n")
+		return b.String()
+	}
+	b.WriteString("Defects:\n")
+	for _, d := range defects {
+		fmt.Fprintf(&b, "  - %s\n", d)
+	}
+	return b.String()
+}
+
+// vertexKey identifies a welded vertex by its quantized coordinates.
+type vertexKey [3]int64
+
+// vertexWelder assigns the same index to vertices that quantize to the same key.
+type vertexWelder map[vertexKey]int
+
+// weld returns the index of the welded vertex at p.
+func (w vertexWelder) weld(p types.Point3D) int {
+	key := vertexKey{
+		int64(math.Round(p.X / weldTolerance)),
+		int64(math.Round(p.Y / weldTolerance)),
+		int64(math.Round(p.Z / weldTolerance)),
+	}
+	id, ok := w[key]
+	if !ok {
+		id = len(w)
+		w[key] = id
+	}
+	return id
+}
+
+// edgeUse records how often an undirected edge is traversed in each direction.
+type edgeUse struct {
+	forward  int // Traversals from the lower to the higher vertex index
+	backward int
+}
+
+// ValidateMesh checks a triangle mesh for defects that prevent reliable slicing:
+// holes, non-manifold and inconsistently wound edges, flipped normals, degenerate
+// triangles and shells that overlap each other.
+//
+// The generator builds models from closed boxes that touch without being merged, so
+// an edge where two boxes meet is used by four triangles. When such an edge is crossed
+// as often in one direction as in the other, each box is closed around it and slicers
+// union the boxes, so it is counted as a contact edge instead of a defect.
+func ValidateMesh(triangles []types.Triangle) MeshReport {
+	report := MeshReport{Triangles: len(triangles)}
+
+	welder := vertexWelder{}
+	indexed := make([][3]int, 0, len(triangles))
+	valid := make([]types.Triangle, 0, len(triangles))
+	edges := make(map[[2]int]*edgeUse)
+
+	for _, t := range triangles {
+		ids := [3]int{welder.weld(t.V1), welder.weld(t.V2), welder.weld(t.V3)}
+		cross := triangleCross(t)
+		area := 0.5 * math.Sqrt(cross.X*cross.X+cross.Y*cross.Y+cross.Z*cross.Z)
+		if ids[0] == ids[1] || ids[1] == ids[2] || ids[0] == ids[2] || area < degenerateArea {
+			report.DegenerateTriangles++
+			continue
+		}
+
+		if vectorDot(t.Normal, cross) < 0 {
+			report.FlippedNormals++
+		}
+
+		for i := 0; i < 3; i++ {
+			a, b := ids[i], ids[(i+1)%3]
+			key := [2]int{a, b}
+			if a > b {
+				key = [2]int{b, a}
+			}
+			use, ok := edges[key]
+			if !ok {
+				use = &edgeUse{}
+				edges[key] = use
+			}
+			if a < b {
+				use.forward++
+			} else {
+				use.backward++
+			}
+		}
+
+		indexed = append(indexed, ids)
+		valid = append(valid, t)
+	}
+
+	for _, use := range edges {
+		switch total := use.forward + use.backward; {
+		case total == 1:
+			report.BoundaryEdges++
+		case total > 2 && total%2 == 0 && use.forward == use.backward:
+			report.ContactEdges++
+		case total > 2:
+			report.NonManifoldEdges++
+		case use.forward != 1:
+			report.InconsistentEdges++
+		}
+	}
+
+	shells := findShells(indexed, valid, len(welder))
+	report.Shells = len(shells)
+	report.OverlappingShells = countOverlappingShells(shells)
+
+	return report
+}
+
+// findShells groups triangles into connected components that share welded vertices.
+func findShells(indexed [][3]int, triangles []types.Triangle, vertexCount int) [][]types.Triangle {
+	parent := make([]int, vertexCount)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	for _, ids := range indexed {
+		for _, id := range ids[1:] {
+			if a, b := find(ids[0]), find(id); a != b {
+				parent[a] = b
+			}
+		}
+	}
+
+	shellIndex := make(map[int]int)
+	var shells [][]types.Triangle
+	for i, ids := range indexed {
+		root := find(ids[0])
+		idx, ok := shellIndex[root]
+		if !ok {
+			idx = len(shells)
+			shellIndex[root] = idx
+			shells = append(shells, nil)
+		}
+		shells[idx] = append(shells[idx], triangles[i])
+	}
+	return shells
+}
+
+// countOverlappingShells counts pairs of shells whose interiors intersect. Each shell is
+// sampled at points just inside its surface near every vertex and tested for containment
+// in the other shell, so crossing shapes that share no interior vertices may be missed.
+func countOverlappingShells(shells [][]types.Triangle) int {
+	boxes := make([]BoundingBox, len(shells))
+	order := make([]int, len(shells))
+	for i, shell := range shells {
+		boxes[i] = CalculateBoundingBox(shell)
+		order[i] = i
+	}
+	// Sweep along X so only shells with overlapping X ranges are compared
+	sort.Slice(order, func(a, b int) bool { return boxes[order[a]].Min.X < boxes[order[b]].Min.X })
+
+	overlapping := 0
+	for i, a := range order {
+		for _, b := range order[i+1:] {
+			if boxes[b].Min.X >= boxes[a].Max.X {
+				break
+			}
+			if !boxes[a].Intersects(boxes[b]) {
+				continue
+			}
+			if shellPenetrates(shells[a], shells[b], boxes[b]) || shellPenetrates(shells[b], shells[a], boxes[a]) {
+				overlapping++
+			}
+		}
+	}
+	return overlapping
+}
+
+// shellPenetrates reports whether any sampled interior point of shell a lies inside shell b.
+func shellPenetrates(a, b []types.Triangle, bBox BoundingBox) bool {
+	stride := 1
+	if samples := len(a) * 3; samples > maxOverlapSamples {
+		stride = samples/maxOverlapSamples + 1
+	}
+
+	for i := 0; i < len(a)*3; i += stride {
+		t := a[i/3]
+		p := interiorSample(t, [3]types.Point3D{t.V1, t.V2, t.V3}[i%3])
+		if p.X <= bBox.Min.X || p.X >= bBox.Max.X ||
+			p.Y <= bBox.Min.Y || p.Y >= bBox.Max.Y ||
+			p.Z <= bBox.Min.Z || p.Z >= bBox.Max.Z {
+			continue
+		}
+		if pointInside(p, b) {
+			return true
+		}
+	}
+	return false
+}
+
+// interiorSample returns a point near vertex v, moved slightly towards the triangle's
+// centroid and slightly behind its surface (into the shell's interior).
+func interiorSample(t types.Triangle, v types.Point3D) types.Point3D {
+	cross := triangleCross(t)
+	length := math.Sqrt(cross.X*cross.X + cross.Y*cross.Y + cross.Z*cross.Z)
+	centroid := types.Point3D{
+		X: (t.V1.X + t.V2.X + t.V3.X) / 3,
+		Y: (t.V1.Y + t.V2.Y + t.V3.Y) / 3,
+		Z: (t.V1.Z + t.V2.Z + t.V3.Z) / 3,
+	}
+	const towardsCentroid = 0.01
+	const inset = 10 * weldTolerance
+	return types.Point3D{
+		X: v.X + (centroid.X-v.X)*towardsCentroid - cross.X/length*inset,
+		Y: v.Y + (centroid.Y-v.Y)*towardsCentroid - cross.Y/length*inset,
+		Z: v.Z + (centroid.Z-v.Z)*towardsCentroid - cross.Z/length*inset,
+	}
+}
+
+// pointInside reports whether p lies inside the closed shell using ray-crossing parity.
+// The ray direction is deliberately skewed so it does not run along axis-aligned edges.
+func pointInside(p types.Point3D, shell []types.Triangle) bool {
+	dir := types.Point3D{X: 1, Y: 0.0137, Z: 0.0291}
+	crossings := 0
+	for _, t := range shell {
+		if rayHitsTriangle(p, dir, t) {
+			crossings++
+		}
+	}
+	return crossings%2 == 1
+}
+
+// rayHitsTriangle implements the Möller–Trumbore ray/triangle intersection test.
+func rayHitsTriangle(origin, dir types.Point3D, t types.Triangle) bool {
+	const epsilon = 1e-12
+	e1 := vectorSubtract(t.V2, t.V1)
+	e2 := vectorSubtract(t.V3, t.V1)
+	h := vectorCross(dir, e2)
+	a := vectorDot(e1, h)
+	if math.Abs(a) < epsilon {
+		return false // Ray is parallel to the triangle
+	}
+	f := 1 / a
+	s := vectorSubtract(origin, t.V1)
+	u := f * vectorDot(s, h)
+	if u < 0 || u > 1 {
+		return false
+	}
+	q := vectorCross(s, e1)
+	v := f * vectorDot(dir, q)
+	if v < 0 || u+v > 1 {
+		return false
+	}
+	return f*vectorDot(e2, q) > epsilon
+}
+
+// triangleCross returns the (unnormalized) normal implied by the triangle's vertex order.
+func triangleCross(t types.Triangle) types.Point3D {
+	return vectorCross(vectorSubtract(t.V2, t.V1), vectorSubtract(t.V3, t.V1))
+}
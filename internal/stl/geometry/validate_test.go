@@ -0,0 +1,106 @@
+package geometry
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func mustCube(t *testing.T, x, y, z, size float64) []types.Triangle {
+	t.Helper()
+	cube, err := CreateCube(x, y, z, size, size, size)
+	if err != nil {
+		t.Fatalf("CreateCube() error = %v", err)
+	}
+	return cube
+}
+
+func TestValidateMesh(t *testing.T) {
+	t.Run("closed cube has no defects", func(t *testing.T) {
+		report := ValidateMesh(mustCube(t, 0, 0, 0, 1))
+		if report.HasDefects() {
+			t.Errorf("unexpected defects: %v", report.Defects())
+		}
+		if report.Triangles != 12 || report.Shells != 1 {
+			t.Errorf("got %d triangles in %d shells, want 12 in 1", report.Triangles, report.Shells)
+		}
+	})
+
+	t.Run("missing triangle leaves a hole", func(t *testing.T) {
+		report := ValidateMesh(mustCube(t, 0, 0, 0, 1)[1:])
+		if report.BoundaryEdges != 3 {
+			t.Errorf("BoundaryEdges = %d, want 3", report.BoundaryEdges)
+		}
+	})
+
+	t.Run("reversed triangle has inconsistent winding and flipped normal", func(t *testing.T) {
+		cube := mustCube(t, 0, 0, 0, 1)
+		cube[0].V2, cube[0].V3 = cube[0].V3, cube[0].V2
+		report := ValidateMesh(cube)
+		if report.InconsistentEdges != 3 {
+			t.Errorf("InconsistentEdges = %d, want 3", report.InconsistentEdges)
+		}
+		if report.FlippedNormals != 1 {
+			t.Errorf("FlippedNormals = %d, want 1", report.FlippedNormals)
+		}
+	})
+
+	t.Run("degenerate triangle", func(t *testing.T) {
+		p := types.Point3D{X: 1, Y: 1, Z: 1}
+		cube := append(mustCube(t, 0, 0, 0, 1), types.Triangle{V1: p, V2: p, V3: types.Point3D{X: 2}})
+		report := ValidateMesh(cube)
+		if report.DegenerateTriangles != 1 {
+			t.Errorf("DegenerateTriangles = %d, want 1", report.DegenerateTriangles)
+		}
+	})
+
+	t.Run("overlapping cubes", func(t *testing.T) {
+		cubes := append(mustCube(t, 0, 0, 0, 2), mustCube(t, 1, 1, 1, 2)...)
+		report := ValidateMesh(cubes)
+		if report.Shells != 2 || report.OverlappingShells != 1 {
+			t.Errorf("got %d shells with %d overlaps, want 2 with 1", report.Shells, report.OverlappingShells)
+		}
+	})
+
+	t.Run("nested cube", func(t *testing.T) {
+		cubes := append(mustCube(t, 0, 0, 0, 3), mustCube(t, 1, 1, 1, 1)...)
+		if report := ValidateMesh(cubes); report.OverlappingShells != 1 {
+			t.Errorf("OverlappingShells = %d, want 1", report.OverlappingShells)
+		}
+	})
+
+	t.Run("separate and touching cubes do not overlap", func(t *testing.T) {
+		cubes := append(mustCube(t, 0, 0, 0, 1), mustCube(t, 5, 0, 0, 1)...)
+		cubes = append(cubes, mustCube(t, 0, 0, 1, 1)...)
+		report := ValidateMesh(cubes)
+		if report.OverlappingShells != 0 {
+			t.Errorf("OverlappingShells = %d, want 0", report.OverlappingShells)
+		}
+		// The stacked cubes share a face, so its four edges are used by four triangles
+		if report.ContactEdges != 4 || report.HasDefects() {
+			t.Errorf("ContactEdges = %d with defects %v, want 4 and none", report.ContactEdges, report.Defects())
+		}
+	})
+
+	t.Run("fin on a cube edge is non-manifold", func(t *testing.T) {
+		cube := mustCube(t, 0, 0, 0, 1)
+		fin := types.Triangle{V1: cube[0].V1, V2: cube[0].V2, V3: types.Point3D{X: -1, Y: -1, Z: -1}}
+		report := ValidateMesh(append(cube, fin))
+		if report.NonManifoldEdges != 1 || report.ContactEdges != 0 {
+			t.Errorf("NonManifoldEdges = %d, ContactEdges = %d, want 1 and 0", report.NonManifoldEdges, report.ContactEdges)
+		}
+	})
+}
+
+func TestMeshReportString(t *testing.T) {
+	clean := MeshReport{Triangles: 12, Shells: 1}.String()
+	if !strings.Contains(clean, "No defects found") {
+		t.Errorf("String() = %q, want no defects message", clean)
+	}
+
+	broken := MeshReport{Triangles: 11, Shells: 1, BoundaryEdges: 3}.String()
+	if !strings.Contains(broken, "3 open boundary edges") {
+		t.Errorf("String() = %q, want boundary edge defect", broken)
+	}
+}
@@ -65,6 +65,11 @@ func vectorCross(u, v types.Point3D) types.Point3D {
 	}
 }
 
+// vectorDot computes the dot product of two 3D vectors.
+func vectorDot(u, v types.Point3D) float64 {
+	return u.X*v.X + u.Y*v.Y + u.Z*v.Z
+}
+
 // normalizeVector converts a vector to a unit vector (magnitude of 1).
 // If the input vector has zero length, returns the original vector unchanged.
 func normalizeVector(v types.Point3D) types.Point3D {
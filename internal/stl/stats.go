@@ -0,0 +1,186 @@
+package stl
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// PrintProfile describes the material and printer settings used for print estimates.
+// The zero value stands for DefaultPrintProfile. In any other profile, a zero field
+// that must be positive takes its default; zero infill and wall lines are kept.
+type PrintProfile struct {
+	FilamentDiameter float64 // Filament diameter in mm
+	Density          float64 // Material density in g/cm³
+	Infill           float64 // Infill fraction between 0 and 1
+	LayerHeight      float64 // Layer height in mm
+	LineWidth        float64 // Extrusion line width in mm
+	WallLines        int     // Number of perimeter lines
+	Speed            float64 // Average print speed in mm/s
+}
+
+// layerChangeTime is the travel and retraction overhead added per layer.
+const layerChangeTime = 2 * time.Second
+
+// DefaultPrintProfile returns settings for a typical PLA print on a 0.4 mm nozzle.
+func DefaultPrintProfile() PrintProfile {
+	return PrintProfile{
+		FilamentDiameter: 1.75,
+		Density:          1.24,
+		Infill:           0.15,
+		LayerHeight:      0.2,
+		LineWidth:        0.4,
+		WallLines:        2,
+		Speed:            60,
+	}
+}
+
+// withDefaults replaces the zero profile with the default profile and fills each
+// other zero field that must be positive with its default.
+func (p PrintProfile) withDefaults() PrintProfile {
+	defaults := DefaultPrintProfile()
+	if p == (PrintProfile{}) {
+		return defaults
+	}
+	pick := func(value, fallback float64) float64 {
+		if value == 0 {
+			return fallback
+		}
+		return value
+	}
+	p.FilamentDiameter = pick(p.FilamentDiameter, defaults.FilamentDiameter)
+	p.Density = pick(p.Density, defaults.Density)
+	p.LayerHeight = pick(p.LayerHeight, defaults.LayerHeight)
+	p.LineWidth = pick(p.LineWidth, defaults.LineWidth)
+	p.Speed = pick(p.Speed, defaults.Speed)
+	return p
+}
+
+// Validate checks that the profile can be used for estimates.
+func (p PrintProfile) Validate() error {
+	p = p.withDefaults()
+	if p.FilamentDiameter <= 0 || p.Density <= 0 || p.LayerHeight <= 0 || p.LineWidth <= 0 || p.Speed <= 0 {
+		return errors.New(errors.ValidationError, "filament diameter, density, layer height, line width and speed must be positive", nil)
+	}
+	if p.WallLines < 0 {
+		return errors.New(errors.ValidationError, "wall line count cannot be negative", nil)
+	}
+	if p.Infill < 0 || p.Infill > 1 {
+		return errors.New(errors.ValidationError, "infill must be between 0 and 1", nil)
+	}
+	return nil
+}
+
+// MeshStats holds geometric measurements of a triangle mesh. Lengths are in mm.
+type MeshStats struct {
+	Triangles   int
+	Bounds      geometry.BoundingBox
+	SurfaceArea float64 // mm², including faces where touching boxes meet inside the model
+	Volume      float64 // Enclosed volume in mm³ (overlapping shells are counted twice)
+}
+
+// PrintEstimate holds the material and time estimates for printing a mesh.
+type PrintEstimate struct {
+	FilamentLength float64       // Filament length in mm
+	FilamentWeight float64       // Filament weight in g
+	Layers         int           // Number of layers
+	PrintTime      time.Duration // Rough print duration
+}
+
+// ComputeMeshStats measures the bounding box, surface area and enclosed volume of a mesh.
+// The volume is the sum of signed tetrahedra against the origin, which is exact for
+// closed, consistently wound shells.
+func ComputeMeshStats(triangles []types.Triangle) MeshStats {
+	stats := MeshStats{
+		Triangles: len(triangles),
+		Bounds:    geometry.CalculateBoundingBox(triangles),
+	}
+
+	var signedVolume float64
+	for _, t := range triangles {
+		c := triangleCross(t)
+		stats.SurfaceArea += 0.5 * math.Sqrt(dot(c, c))
+		signedVolume += dot(t.V1, cross(t.V2, t.V3)) / 6
+	}
+	stats.Volume = math.Abs(signedVolume)
+
+	return stats
+}
+
+// Estimate returns filament and print time estimates for the profile.
+// The material volume assumes solid walls of WallLines perimeters and sparse infill inside.
+// The walls are measured on the whole surface area, so models built from touching boxes
+// are estimated on the heavy side.
+func (s MeshStats) Estimate(profile PrintProfile) PrintEstimate {
+	p := profile.withDefaults()
+
+	wallVolume := math.Min(s.Volume, s.SurfaceArea*float64(p.WallLines)*p.LineWidth)
+	materialVolume := wallVolume + p.Infill*(s.Volume-wallVolume)
+
+	filamentArea := math.Pi * p.FilamentDiameter * p.FilamentDiameter / 4
+	layers := int(math.Ceil(s.Bounds.Size().Z / p.LayerHeight))
+
+	// Volumetric flow of a single extrusion line, in mm³/s
+	flow := p.LayerHeight * p.LineWidth * p.Speed
+	extrusion := time.Duration(materialVolume / flow * float64(time.Second))
+
+	return PrintEstimate{
+		FilamentLength: materialVolume / filamentArea,
+		FilamentWeight: materialVolume / 1000 * p.Density,
+		Layers:         layers,
+		PrintTime:      extrusion + time.Duration(layers)*layerChangeTime,
+	}
+}
+
+// Summary returns a multi-line, human-readable report of the statistics and estimates.
+func (s MeshStats) Summary(profile PrintProfile) string {
+	p := profile.withDefaults()
+	e := s.Estimate(p)
+	size := s.Bounds.Size()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Bounding box: %.2f x %.2f x %.2f mm\n", size.X, size.Y, size.Z)
+	fmt.Fprintf(&b, "Triangles:    %d\n", s.Triangles)
+	fmt.Fprintf(&b, "Surface area: %.2f cm² (including faces where parts touch inside)\n", s.SurfaceArea/100)
+	fmt.Fprintf(&b, "Volume:       %.2f cm³\n", s.Volume/1000)
+	fmt.Fprintf(&b, "Filament:     %.2f m, %.1f g (%.2f mm at %.2f g/cm³, %.0f%% infill)\n",
+		e.FilamentLength/1000, e.FilamentWeight, p.FilamentDiameter, p.Density, p.Infill*100)
+	fmt.Fprintf(&b, "Print time:   ~%s (%d layers of %.2f mm at %.0f mm/s)\n",
+		formatDuration(e.PrintTime), e.Layers, p.LayerHeight, p.Speed)
+	return b.String()
+}
+
+// formatDuration formats a duration as hours and minutes, e.g. "2h05m".
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh%02dm", minutes/60, minutes%60)
+}
+
+// triangleCross returns the (unnormalized) normal implied by the triangle's vertex order.
+func triangleCross(t types.Triangle) types.Point3D {
+	return cross(sub(t.V2, t.V1), sub(t.V3, t.V1))
+}
+
+func sub(a, b types.Point3D) types.Point3D {
+	return types.Point3D{X: a.X - b.X, Y: a.Y - b.Y, Z: a.Z - b.Z}
+}
+
+func cross(u, v types.Point3D) types.Point3D {
+	return types.Point3D{
+		X: u.Y*v.Z - u.Z*v.Y,
+		Y: u.Z*v.X - u.X*v.Z,
+		Z: u.X*v.Y - u.Y*v.X,
+	}
+}
+
+func dot(u, v types.Point3D) float64 {
+	return u.X*v.X + u.Y*v.Y + u.Z*v.Z
+}
@@ -0,0 +1,119 @@
+package stl
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+func mustCube(t *testing.T, x, y, z, size float64) []types.Triangle {
+	t.Helper()
+	cube, err := geometry.CreateCube(x, y, z, size, size, size)
+	if err != nil {
+		t.Fatalf("geometry.CreateCube() error = %v", err)
+	}
+	return cube
+}
+
+func TestComputeMeshStats(t *testing.T) {
+	stats := ComputeMeshStats(mustCube(t, 5, -3, 2, 10))
+
+	if stats.Triangles != 12 {
+		t.Errorf("Triangles = %d, want 12", stats.Triangles)
+	}
+	assertClose(t, "SurfaceArea", stats.SurfaceArea, 600)
+	assertClose(t, "Volume", stats.Volume, 1000)
+	assertClose(t, "height", stats.Bounds.Size().Z, 10)
+
+	empty := ComputeMeshStats(nil)
+	if empty.Volume != 0 || empty.SurfaceArea != 0 {
+		t.Errorf("expected zero stats for empty mesh, got %+v", empty)
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	stats := ComputeMeshStats(mustCube(t, 0, 0, 0, 10))
+	estimate := stats.Estimate(PrintProfile{})
+
+	// 2 walls of 0.4 mm over 600 mm² give 480 mm³ solid, plus 15% of the remaining 520 mm³
+	material := 480 + 0.15*520
+	assertClose(t, "FilamentLength", estimate.FilamentLength, material/(math.Pi*1.75*1.75/4))
+	assertClose(t, "FilamentWeight", estimate.FilamentWeight, material/1000*1.24)
+	if estimate.Layers != 50 {
+		t.Errorf("Layers = %d, want 50", estimate.Layers)
+	}
+	wantTime := time.Duration(material/(0.2*0.4*60)*float64(time.Second)) + 50*layerChangeTime
+	if math.Abs(float64(estimate.PrintTime-wantTime)) > float64(time.Millisecond) {
+		t.Errorf("PrintTime = %v, want %v", estimate.PrintTime, wantTime)
+	}
+
+	solid := DefaultPrintProfile()
+	solid.Infill = 1
+	assertClose(t, "solid weight", stats.Estimate(solid).FilamentWeight, 1.24)
+}
+
+func TestPrintProfileValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(p *PrintProfile)
+		wantErr bool
+	}{
+		{"default", func(_ *PrintProfile) {}, false},
+		{"zero infill", func(p *PrintProfile) { p.Infill = 0 }, false},
+		{"infill above one", func(p *PrintProfile) { p.Infill = 1.5 }, true},
+		{"zero layer height", func(p *PrintProfile) { p.LayerHeight = 0 }, false},
+		{"negative layer height", func(p *PrintProfile) { p.LayerHeight = -0.2 }, true},
+		{"negative speed", func(p *PrintProfile) { p.Speed = -10 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := DefaultPrintProfile()
+			tt.modify(&p)
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := (PrintProfile{}).Validate(); err != nil {
+		t.Errorf("zero profile should validate as the default, got %v", err)
+	}
+}
+
+func TestPrintProfileWithDefaults(t *testing.T) {
+	got := PrintProfile{LayerHeight: 0.12, Infill: 0, WallLines: 3}.withDefaults()
+	want := DefaultPrintProfile()
+	want.LayerHeight = 0.12
+	want.Infill = 0
+	want.WallLines = 3
+	if got != want {
+		t.Errorf("withDefaults() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMeshStatsSummary(t *testing.T) {
+	summary := ComputeMeshStats(mustCube(t, 0, 0, 0, 10)).Summary(PrintProfile{})
+	for _, want := range []string{"10.00 x 10.00 x 10.00 mm", "Volume:       1.00 cm³", "15% infill", "50 layers", "including faces where parts touch"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Summary() = %q, missing %q", summary, want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		90 * time.Second:                "2m",
+		2*time.Hour + 5*time.Minute:     "2h05m",
+		59*time.Minute + 40*time.Second: "1h00m",
+	}
+	for d, want := range tests {
+		if got := formatDuration(d); got != want {
+			t.Errorf("formatDuration(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -16,6 +16,7 @@ package stl
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/binary"
 	"math"
 	"os"
@@ -177,6 +178,43 @@ func writeTriangleToBuffer(buffer []byte, t types.TriangleFloat32) error {
 	return nil
 }
 
+// ReadSTL reads an STL file in either binary or ASCII format and returns the triangles.
+// Binary files are recognised by their size matching the triangle count in the header,
+// since some exporters also begin binary headers with "solid".
+func ReadSTL(filename string) ([]types.Triangle, error) {
+	if filename == "" {
+		return nil, errors.New(errors.ValidationError, "STL filename cannot be empty", nil)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to open STL file", err)
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, errors.New(errors.IOError, "failed to open STL file", err)
+	}
+	header := make([]byte, 84)
+	n, _ := file.Read(header)
+	_ = file.Close()
+
+	if n == len(header) {
+		count := binary.LittleEndian.Uint32(header[80:])
+		if info.Size() == int64(len(header))+int64(count)*triangleSize {
+			return ReadSTLBinary(filename)
+		}
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(header[:n]), []byte("solid")) {
+		triangles, err := ReadASCIISTL(filename)
+		if err != nil {
+			return nil, errors.New(errors.IOError, "failed to read ASCII STL file", err)
+		}
+		return triangles, nil
+	}
+	return nil, errors.New(errors.ValidationError, "file is neither a binary nor an ASCII STL", nil)
+}
+
 // ReadSTLBinary reads a binary STL file and returns the triangles.
 func ReadSTLBinary(filename string) ([]types.Triangle, error) {
 	if filename == "" {
@@ -147,3 +147,58 @@ func TestWriteSTLBinary(t *testing.T) {
 	t.Run("handle empty triangle list", testEmptyTriangleList)
 	t.Run("handle nil triangle list", testNilTriangleList)
 }
+
+func TestReadSTL(t *testing.T) {
+	testDir := t.TempDir()
+	triangles := []types.Triangle{{
+		Normal: types.Point3D{X: 0, Y: 0, Z: 1},
+		V1:     types.Point3D{X: 0, Y: 0, Z: 0},
+		V2:     types.Point3D{X: 1, Y: 0, Z: 0},
+		V3:     types.Point3D{X: 0, Y: 1, Z: 0},
+	}}
+
+	t.Run("binary", func(t *testing.T) {
+		path := filepath.Join(testDir, "binary.stl")
+		if err := WriteSTLBinary(path, triangles); err != nil {
+			t.Fatalf("WriteSTLBinary() error = %v", err)
+		}
+		got, err := ReadSTL(path)
+		if err != nil {
+			t.Fatalf("ReadSTL() error = %v", err)
+		}
+		if len(got) != 1 || got[0].V2.X != 1 {
+			t.Errorf("ReadSTL() = %+v, want %+v", got, triangles)
+		}
+	})
+
+	t.Run("ascii", func(t *testing.T) {
+		path := filepath.Join(testDir, "ascii.stl")
+		content := "solid test\n facet normal 0 0 1\n  outer loop\n   vertex 0 0 0\n   vertex 1 0 0\n   vertex 0 1 0\n  endloop\n endfacet\nendsolid test\n"
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := ReadSTL(path)
+		if err != nil {
+			t.Fatalf("ReadSTL() error = %v", err)
+		}
+		if len(got) != 1 || got[0].V3.Y != 1 {
+			t.Errorf("ReadSTL() = %+v, want %+v", got, triangles)
+		}
+	})
+
+	t.Run("not an STL", func(t *testing.T) {
+		path := filepath.Join(testDir, "text.stl")
+		if err := os.WriteFile(path, []byte("hello"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadSTL(path); err == nil {
+			t.Error("ReadSTL() expected error for non-STL file")
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		if _, err := ReadSTL(filepath.Join(testDir, "missing.stl")); err == nil {
+			t.Error("ReadSTL() expected error for missing file")
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package stl
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/github/gh-skyline/internal/errors"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// Object is a named part of a model. Multi-object formats such as 3MF keep objects
+// separate so slicers can print each with its own filament; STL merges them.
+type Object struct {
+	Name      string
+	Color     string // Display colour as #RRGGBB (optional)
+	Triangles []types.Triangle
+}
+
+// mergeObjects returns the triangles of all objects as one mesh.
+func mergeObjects(objects []Object) []types.Triangle {
+	count := 0
+	for _, object := range objects {
+		count += len(object.Triangles)
+	}
+	triangles := make([]types.Triangle, 0, count)
+	for _, object := range objects {
+		triangles = append(triangles, object.Triangles...)
+	}
+	return triangles
+}
+
+const (
+	threeMFContentTypes = `<?xml version="1.0" encoding="UTF-8"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+ <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+ <Default Extension="model" ContentType="application/vnd.ms-package.3dmanufacturing-3dmodel+xml"/>
+</Types>
+`
+	threeMFRelationships = `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+ <Relationship Target="/3D/3dmodel.model" Id="rel0" Type="http://schemas.microsoft.com/3dmanufacturing/2013/01/3dmodel"/>
+</Relationships>
+`
+	threeMFModelPath = "3D/3dmodel.model"
+)
+
+// Write3MF writes objects to a 3MF package, one mesh object per non-empty object.
+// Coloured objects reference a base material with their display colour. Vertices
+// shared by triangles are written once, and triangles that collapse when their
+// vertices are merged are dropped, as 3MF requires.
+func Write3MF(filename string, objects []Object) error {
+	if filename == "" {
+		return errors.New(errors.ValidationError, "3MF filename cannot be empty", nil)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return errors.New(errors.IOError, "failed to create 3MF file", err)
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	parts := []struct {
+		name    string
+		content func(io.Writer) error
+	}{
+		{"[Content_Types].xml", writeString(threeMFContentTypes)},
+		{"_rels/.rels", writeString(threeMFRelationships)},
+		{threeMFModelPath, func(w io.Writer) error { return write3MFModel(w, objects) }},
+	}
+	for _, part := range parts {
+		w, err := archive.Create(part.name)
+		if err != nil {
+			return errors.New(errors.IOError, "failed to add "+part.name+" to 3MF file", err)
+		}
+		if err := part.content(w); err != nil {
+			return errors.New(errors.IOError, "failed to write "+part.name+" to 3MF file", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return errors.New(errors.IOError, "failed to finish 3MF file", err)
+	}
+	if err := file.Close(); err != nil {
+		return errors.New(errors.IOError, "failed to close 3MF file", err)
+	}
+	return nil
+}
+
+// writeString returns a part writer for fixed content.
+func writeString(content string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		_, err := io.WriteString(w, content)
+		return err
+	}
+}
+
+// write3MFModel writes the 3D model part: materials, meshes and build items.
+func write3MFModel(out io.Writer, objects []Object) error {
+	w := bufio.NewWriterSize(out, bufferSize)
+	fmt.Fprintln(w, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(w, `<model unit="millimeter" xml:lang="en-US" xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02">`)
+	fmt.Fprintln(w, ` <resources>`)
+
+	// Resource 1 holds the colours; objects are numbered from 2
+	materials := make(map[string]int)
+	var colors []string
+	for _, object := range objects {
+		if _, ok := materials[object.Color]; object.Color != "" && len(object.Triangles) > 0 && !ok {
+			materials[object.Color] = len(colors)
+			colors = append(colors, object.Color)
+		}
+	}
+	if len(colors) > 0 {
+		fmt.Fprintln(w, `  <basematerials id="1">`)
+		for _, color := range colors {
+			fmt.Fprintf(w, "   <base name=%s displaycolor=%s/>\n", xmlAttr(color), xmlAttr(color))
+		}
+		fmt.Fprintln(w, `  </basematerials>`)
+	}
+
+	var ids []int
+	for _, object := range objects {
+		if len(object.Triangles) == 0 {
+			continue
+		}
+		id := len(ids) + 2
+		ids = append(ids, id)
+
+		fmt.Fprintf(w, "  <object id=\"%d\" type=\"model\" name=%s", id, xmlAttr(object.Name))
+		if object.Color != "" {
+			fmt.Fprintf(w, " pid=\"1\" pindex=\"%d\"", materials[object.Color])
+		}
+		fmt.Fprintln(w, ">\n   <mesh>\n    <vertices>")
+
+		index := make(map[types.Point3DFloat32]int)
+		faces := make([][3]int, 0, len(object.Triangles))
+		for _, triangle := range object.Triangles {
+			var face [3]int
+			for i, vertex := range []types.Point3D{triangle.V1, triangle.V2, triangle.V3} {
+				p := vertex.ToFloat32()
+				n, ok := index[p]
+				if !ok {
+					n = len(index)
+					index[p] = n
+					fmt.Fprintf(w, "     <vertex x=\"%s\" y=\"%s\" z=\"%s\"/>\n", formatCoordinate(p.X), formatCoordinate(p.Y), formatCoordinate(p.Z))
+				}
+				face[i] = n
+			}
+			if face[0] != face[1] && face[1] != face[2] && face[0] != face[2] {
+				faces = append(faces, face)
+			}
+		}
+
+		fmt.Fprintln(w, "    </vertices>\n    <triangles>")
+		for _, face := range faces {
+			fmt.Fprintf(w, "     <triangle v1=\"%d\" v2=\"%d\" v3=\"%d\"/>\n", face[0], face[1], face[2])
+		}
+		fmt.Fprintln(w, "    </triangles>\n   </mesh>\n  </object>")
+	}
+
+	fmt.Fprintln(w, " </resources>\n <build>")
+	for _, id := range ids {
+		fmt.Fprintf(w, "  <item objectid=\"%d\"/>\n", id)
+	}
+	fmt.Fprintln(w, " </build>\n</model>")
+	return w.Flush()
+}
+
+// formatCoordinate formats a coordinate with the shortest exact representation.
+func formatCoordinate(v float32) string {
+	return strconv.FormatFloat(float64(v), 'f', -1, 32)
+}
+
+// xmlAttr returns s quoted and escaped as an XML attribute value.
+func xmlAttr(s string) string {
+	var b bytes.Buffer
+	b.WriteByte('"')
+	_ = xml.EscapeText(&b, []byte(s)) // Writing to a bytes.Buffer cannot fail
+	b.WriteByte('"')
+	return b.String()
+}
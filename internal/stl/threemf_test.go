@@ -0,0 +1,113 @@
+package stl
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"path/filepath"
+	"testing"
+
+	"github.com/github/gh-skyline/internal/stl/geometry"
+	"github.com/github/gh-skyline/internal/types"
+)
+
+// threeMFModel mirrors the parts of the 3MF model XML the writer produces.
+type threeMFModel struct {
+	Unit      string `xml:"unit,attr"`
+	Materials []struct {
+		Colors []string `xml:"displaycolor,attr"`
+	} `xml:"resources>basematerials>base"`
+	Objects []struct {
+		ID       int    `xml:"id,attr"`
+		Name     string `xml:"name,attr"`
+		PID      int    `xml:"pid,attr"`
+		Vertices []struct {
+			X float64 `xml:"x,attr"`
+		} `xml:"mesh>vertices>vertex"`
+		Triangles []struct {
+			V1 int `xml:"v1,attr"`
+		} `xml:"mesh>triangles>triangle"`
+	} `xml:"resources>object"`
+	Items []struct {
+		ObjectID int `xml:"objectid,attr"`
+	} `xml:"build>item"`
+}
+
+// read3MF opens a 3MF package, checks its required parts and parses the model.
+func read3MF(t *testing.T, path string) threeMFModel {
+	t.Helper()
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatalf("failed to open 3MF package: %v", err)
+	}
+	defer archive.Close()
+
+	var model threeMFModel
+	found := make(map[string]bool)
+	for _, file := range archive.File {
+		found[file.Name] = true
+		if file.Name != threeMFModelPath {
+			continue
+		}
+		r, err := file.Open()
+		if err != nil {
+			t.Fatalf("failed to open model part: %v", err)
+		}
+		if err := xml.NewDecoder(r).Decode(&model); err != nil {
+			t.Fatalf("failed to parse model part: %v", err)
+		}
+		r.Close()
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", threeMFModelPath} {
+		if !found[name] {
+			t.Errorf("3MF package is missing %s", name)
+		}
+	}
+	return model
+}
+
+func TestWrite3MF(t *testing.T) {
+	cube, err := geometry.CreateCube(0, 0, 0, 1, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pyramid, err := geometry.CreatePyramid(0, 0, 1, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	degenerate := types.Triangle{V1: types.Point3D{X: 5}, V2: types.Point3D{X: 5}, V3: types.Point3D{X: 6}}
+
+	path := filepath.Join(t.TempDir(), "model.3mf")
+	objects := []Object{
+		{Name: "skyline", Triangles: append(cube, degenerate)},
+		{Name: "empty", Color: "#000000"},
+		{Name: "Mona & Hubot's day", Color: "#FFD700", Triangles: pyramid},
+	}
+	if err := Write3MF(path, objects); err != nil {
+		t.Fatalf("Write3MF() error = %v", err)
+	}
+
+	model := read3MF(t, path)
+	if model.Unit != "millimeter" {
+		t.Errorf("unit = %q, want millimeter", model.Unit)
+	}
+	if len(model.Objects) != 2 || len(model.Items) != 2 {
+		t.Fatalf("got %d objects and %d build items, want 2 each", len(model.Objects), len(model.Items))
+	}
+
+	skyline, marker := model.Objects[0], model.Objects[1]
+	// The cube's 8 corners are shared by its 12 triangles; the degenerate triangle is dropped
+	if len(skyline.Vertices) != 10 || len(skyline.Triangles) != 12 || skyline.PID != 0 {
+		t.Errorf("skyline object has %d vertices, %d triangles, pid %d; want 10, 12, 0",
+			len(skyline.Vertices), len(skyline.Triangles), skyline.PID)
+	}
+	if marker.Name != "Mona & Hubot's day" || marker.PID != 1 || len(marker.Vertices) != 5 {
+		t.Errorf("marker object = %q pid %d with %d vertices", marker.Name, marker.PID, len(marker.Vertices))
+	}
+	if len(model.Materials) != 1 {
+		t.Errorf("got %d materials, want only the colour in use", len(model.Materials))
+	}
+
+	if err := Write3MF("", objects); err == nil {
+		t.Error("Write3MF() expected error for empty filename")
+	}
+}
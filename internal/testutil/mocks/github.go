@@ -2,6 +2,7 @@
 package mocks
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -19,7 +20,10 @@ type MockGitHubClient struct {
 }
 
 // GetAuthenticatedUser implements GitHubClientInterface
-func (m *MockGitHubClient) GetAuthenticatedUser() (string, error) {
+func (m *MockGitHubClient) GetAuthenticatedUser(ctx context.Context) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
 	if m.Err != nil {
 		return "", m.Err
 	}
@@ -30,7 +34,10 @@ func (m *MockGitHubClient) GetAuthenticatedUser() (string, error) {
 }
 
 // GetUserJoinYear implements GitHubClientInterface
-func (m *MockGitHubClient) GetUserJoinYear(_ string) (int, error) {
+func (m *MockGitHubClient) GetUserJoinYear(ctx context.Context, _ string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
 	if m.Err != nil {
 		return 0, m.Err
 	}
@@ -41,7 +48,10 @@ func (m *MockGitHubClient) GetUserJoinYear(_ string) (int, error) {
 }
 
 // FetchContributions implements GitHubClientInterface
-func (m *MockGitHubClient) FetchContributions(username string, year int) (*types.ContributionsResponse, error) {
+func (m *MockGitHubClient) FetchContributions(ctx context.Context, username string, year int) (*types.ContributionsResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if m.Err != nil {
 		return nil, m.Err
 	}
@@ -49,8 +59,11 @@ func (m *MockGitHubClient) FetchContributions(username string, year int) (*types
 	return fixtures.GenerateContributionsResponse(username, year), nil
 }
 
-// Do implements APIClient
-func (m *MockGitHubClient) Do(_ string, _ map[string]interface{}, response interface{}) error {
+// DoWithContext implements APIClient
+func (m *MockGitHubClient) DoWithContext(ctx context.Context, _ string, _ map[string]interface{}, response interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if m.Err != nil {
 		return m.Err
 	}
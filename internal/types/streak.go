@@ -0,0 +1,98 @@
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// Streak is a run of consecutive days with at least one contribution.
+type Streak struct {
+	Length int    // Number of days in the streak
+	Start  string // First day as YYYY-MM-DD, empty when Length is 0
+	End    string // Last day as YYYY-MM-DD, empty when Length is 0
+}
+
+// Contains reports whether a YYYY-MM-DD date lies within the streak.
+func (s Streak) Contains(date string) bool {
+	return s.Length > 0 && date >= s.Start && date <= s.End
+}
+
+// LongestStreak returns the longest streak in the contribution grid. Of several
+// streaks of the same length, the earliest is returned. Weeks of more than one
+// year may be combined into one grid; streaks continue across years.
+func LongestStreak(weeks [][]ContributionDay) Streak {
+	var longest, current Streak
+	var previous time.Time
+	for _, day := range sortedDays(weeks) {
+		date, _ := time.Parse("2006-01-02", day.Date)
+		switch {
+		case day.ContributionCount <= 0:
+			current = Streak{}
+		case current.Length > 0 && date.Equal(previous.AddDate(0, 0, 1)):
+			current.Length++
+			current.End = day.Date
+		default:
+			current = Streak{Length: 1, Start: day.Date, End: day.Date}
+		}
+		previous = date
+		if current.Length > longest.Length {
+			longest = current
+		}
+	}
+	return longest
+}
+
+// CurrentStreak returns the streak that is still running on today: the streak
+// ending today, or yesterday when there are no contributions today yet.
+// Days after today are ignored.
+func CurrentStreak(weeks [][]ContributionDay, today time.Time) Streak {
+	days := sortedDays(weeks)
+	todayDate := today.Format("2006-01-02")
+	for len(days) > 0 && days[len(days)-1].Date > todayDate {
+		days = days[:len(days)-1]
+	}
+	if len(days) > 0 && days[len(days)-1].Date == todayDate && days[len(days)-1].ContributionCount <= 0 {
+		days = days[:len(days)-1]
+	}
+
+	var streak Streak
+	expected := today
+	if len(days) == 0 || days[len(days)-1].Date != todayDate {
+		expected = today.AddDate(0, 0, -1)
+	}
+	for i := len(days) - 1; i >= 0; i-- {
+		day := days[i]
+		if day.Date != expected.Format("2006-01-02") || day.ContributionCount <= 0 {
+			break
+		}
+		if streak.Length == 0 {
+			streak.End = day.Date
+		}
+		streak.Length++
+		streak.Start = day.Date
+		expected = expected.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// sortedDays returns the valid days of the grid in date order, each date once.
+func sortedDays(weeks [][]ContributionDay) []ContributionDay {
+	var days []ContributionDay
+	for _, week := range weeks {
+		for _, day := range week {
+			if _, err := time.Parse("2006-01-02", day.Date); err == nil {
+				days = append(days, day)
+			}
+		}
+	}
+	sort.SliceStable(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+
+	unique := days[:0]
+	for _, day := range days {
+		if len(unique) > 0 && unique[len(unique)-1].Date == day.Date {
+			continue
+		}
+		unique = append(unique, day)
+	}
+	return unique
+}
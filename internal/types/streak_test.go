@@ -0,0 +1,147 @@
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+// consecutiveDays returns a single week-like slice of days starting on start
+// with the given contribution counts.
+func consecutiveDays(start string, counts ...int) []ContributionDay {
+	date, _ := time.Parse("2006-01-02", start)
+	days := make([]ContributionDay, len(counts))
+	for i, count := range counts {
+		days[i] = ContributionDay{ContributionCount: count, Date: date.AddDate(0, 0, i).Format("2006-01-02")}
+	}
+	return days
+}
+
+func TestLongestStreak(t *testing.T) {
+	testCases := []struct {
+		name     string
+		weeks    [][]ContributionDay
+		expected Streak
+	}{
+		{
+			name:     "no contributions",
+			weeks:    [][]ContributionDay{consecutiveDays("2024-03-03", 0, 0, 0)},
+			expected: Streak{},
+		},
+		{
+			name:     "single day",
+			weeks:    [][]ContributionDay{consecutiveDays("2024-03-03", 0, 2, 0)},
+			expected: Streak{Length: 1, Start: "2024-03-04", End: "2024-03-04"},
+		},
+		{
+			name: "streak across weeks",
+			weeks: [][]ContributionDay{
+				consecutiveDays("2024-03-03", 1, 0, 0, 0, 3, 1, 2),
+				consecutiveDays("2024-03-10", 5, 1, 0, 1, 1, 1, 1),
+			},
+			expected: Streak{Length: 5, Start: "2024-03-07", End: "2024-03-11"},
+		},
+		{
+			name: "earliest of equal streaks",
+			weeks: [][]ContributionDay{
+				consecutiveDays("2024-03-03", 1, 1, 0, 1, 1, 0, 0),
+			},
+			expected: Streak{Length: 2, Start: "2024-03-03", End: "2024-03-04"},
+		},
+		{
+			name: "streak across years in any order",
+			weeks: [][]ContributionDay{
+				consecutiveDays("2024-01-01", 1, 1, 0),
+				consecutiveDays("2023-12-29", 0, 1, 1),
+			},
+			expected: Streak{Length: 4, Start: "2023-12-30", End: "2024-01-02"},
+		},
+		{
+			name: "missing days break the streak",
+			weeks: [][]ContributionDay{
+				consecutiveDays("2024-03-01", 1, 1),
+				consecutiveDays("2024-04-01", 1, 1, 1),
+			},
+			expected: Streak{Length: 3, Start: "2024-04-01", End: "2024-04-03"},
+		},
+		{
+			name: "invalid dates are ignored",
+			weeks: [][]ContributionDay{
+				{{ContributionCount: 4, Date: "invalid"}},
+			},
+			expected: Streak{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := LongestStreak(tc.weeks); got != tc.expected {
+				t.Errorf("LongestStreak() = %+v, want %+v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestCurrentStreak(t *testing.T) {
+	today := time.Date(2024, 3, 13, 15, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		weeks    [][]ContributionDay
+		expected Streak
+	}{
+		{
+			name:     "streak ending today",
+			weeks:    [][]ContributionDay{consecutiveDays("2024-03-10", 0, 1, 1, 1, 0)},
+			expected: Streak{Length: 3, Start: "2024-03-11", End: "2024-03-13"},
+		},
+		{
+			name:     "no contributions today yet",
+			weeks:    [][]ContributionDay{consecutiveDays("2024-03-10", 1, 1, 1, 0, 0)},
+			expected: Streak{Length: 3, Start: "2024-03-10", End: "2024-03-12"},
+		},
+		{
+			name:     "broken yesterday",
+			weeks:    [][]ContributionDay{consecutiveDays("2024-03-10", 1, 1, 0, 0)},
+			expected: Streak{},
+		},
+		{
+			name:     "data ends before yesterday",
+			weeks:    [][]ContributionDay{consecutiveDays("2023-12-29", 1, 1, 1)},
+			expected: Streak{},
+		},
+		{
+			name:     "data without today",
+			weeks:    [][]ContributionDay{consecutiveDays("2024-03-11", 2, 2)},
+			expected: Streak{Length: 2, Start: "2024-03-11", End: "2024-03-12"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := CurrentStreak(tc.weeks, today); got != tc.expected {
+				t.Errorf("CurrentStreak() = %+v, want %+v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestStreakContains(t *testing.T) {
+	streak := Streak{Length: 3, Start: "2024-03-11", End: "2024-03-13"}
+	testCases := []struct {
+		date     string
+		expected bool
+	}{
+		{"2024-03-10", false},
+		{"2024-03-11", true},
+		{"2024-03-12", true},
+		{"2024-03-13", true},
+		{"2024-03-14", false},
+	}
+	for _, tc := range testCases {
+		if got := streak.Contains(tc.date); got != tc.expected {
+			t.Errorf("Contains(%q) = %v, want %v", tc.date, got, tc.expected)
+		}
+	}
+	if (Streak{}).Contains("") {
+		t.Error("empty streak should contain no date")
+	}
+}
@@ -12,6 +12,25 @@ import (
 type ContributionDay struct {
 	ContributionCount int    `json:"contributionCount"`
 	Date              string `json:"date"`
+	// Breakdown splits the count by contribution type; nil unless it was fetched.
+	Breakdown *ContributionBreakdown `json:"breakdown,omitempty"`
+}
+
+// ContributionBreakdown counts the contributions of a day by type. Other holds the
+// contributions the calendar counts without a type, such as created repositories,
+// private contributions and commits beyond the first 100 days of a repository.
+type ContributionBreakdown struct {
+	Commits      int `json:"commits"`
+	PullRequests int `json:"pullRequests"`
+	Reviews      int `json:"reviews"`
+	Issues       int `json:"issues"`
+	Other        int `json:"other"`
+}
+
+// Counts returns the counts in stacking order, from the bottom of a column up:
+// commits, pull requests, reviews, issues and other contributions.
+func (b ContributionBreakdown) Counts() []int {
+	return []int{b.Commits, b.PullRequests, b.Reviews, b.Issues, b.Other}
 }
 
 // IsAfter checks if the contribution day is after the given time
@@ -33,6 +52,13 @@ func (c ContributionDay) Validate() error {
 	if c.ContributionCount < 0 {
 		return errors.New("contribution count cannot be negative")
 	}
+	if c.Breakdown != nil {
+		for _, count := range c.Breakdown.Counts() {
+			if count < 0 {
+				return errors.New("breakdown counts cannot be negative")
+			}
+		}
+	}
 	return nil
 }
 
@@ -51,6 +77,20 @@ type ContributionsResponse struct {
 	} `json:"user"`
 }
 
+// ApplyBreakdown sets the breakdown of every day from typed counts by date,
+// counting the rest of each day's contributions as other.
+func (r *ContributionsResponse) ApplyBreakdown(byDate map[string]ContributionBreakdown) {
+	for _, week := range r.User.ContributionsCollection.ContributionCalendar.Weeks {
+		for i := range week.ContributionDays {
+			day := &week.ContributionDays[i]
+			breakdown := byDate[day.Date]
+			typed := breakdown.Commits + breakdown.PullRequests + breakdown.Reviews + breakdown.Issues
+			breakdown.Other = max(day.ContributionCount-typed, 0)
+			day.Breakdown = &breakdown
+		}
+	}
+}
+
 // Point3D represents a point in 3D space using float64 for accuracy in calculations.
 // Each coordinate (X, Y, Z) represents a position in 3D space.
 type Point3D struct {
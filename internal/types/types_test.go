@@ -413,3 +413,37 @@ func TestPoint3DFloat32Construction(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyBreakdown(t *testing.T) {
+	var response ContributionsResponse
+	response.User.ContributionsCollection.ContributionCalendar.Weeks = []struct {
+		ContributionDays []ContributionDay `json:"contributionDays"`
+	}{{ContributionDays: []ContributionDay{
+		{Date: "2024-01-01", ContributionCount: 5},
+		{Date: "2024-01-02", ContributionCount: 1},
+		{Date: "2024-01-03", ContributionCount: 2},
+	}}}
+
+	response.ApplyBreakdown(map[string]ContributionBreakdown{
+		"2024-01-01": {Commits: 2, Reviews: 1},
+		"2024-01-02": {PullRequests: 2}, // More than the calendar counts
+	})
+
+	want := []ContributionBreakdown{
+		{Commits: 2, Reviews: 1, Other: 2},
+		{PullRequests: 2},
+		{Other: 2},
+	}
+	for i, day := range response.User.ContributionsCollection.ContributionCalendar.Weeks[0].ContributionDays {
+		if day.Breakdown == nil || *day.Breakdown != want[i] {
+			t.Errorf("%s breakdown = %+v, want %+v", day.Date, day.Breakdown, want[i])
+		}
+	}
+}
+
+func TestContributionDayValidateBreakdown(t *testing.T) {
+	day := ContributionDay{Date: "2024-01-01", ContributionCount: 1, Breakdown: &ContributionBreakdown{Commits: -1}}
+	if err := day.Validate(); err == nil {
+		t.Error("Validate() accepted a negative breakdown count")
+	}
+}
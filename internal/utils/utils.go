@@ -65,8 +65,9 @@ func FormatYearRange(startYear, endYear int) string {
 // GenerateOutputFilename creates a consistent filename for the STL output
 func GenerateOutputFilename(user string, startYear, endYear int, output string) string {
 	if output != "" {
-		// Ensure the filename ends with .stl
-		if !strings.HasSuffix(strings.ToLower(output), ".stl") {
+		// Ensure the filename ends with .stl, unless it asks for a 3MF file
+		lower := strings.ToLower(output)
+		if !strings.HasSuffix(lower, ".stl") && !strings.HasSuffix(lower, ".3mf") {
 			return output + ".stl"
 		}
 		return output
@@ -162,6 +162,22 @@ func TestGenerateOutputFilename(t *testing.T) {
 			output:    "myoutput.stl",
 			want:      "myoutput.stl",
 		},
+		{
+			name:      "3MF override",
+			user:      "testuser",
+			startYear: 2024,
+			endYear:   2024,
+			output:    "multicolor.3MF",
+			want:      "multicolor.3MF",
+		},
+		{
+			name:      "override without extension",
+			user:      "testuser",
+			startYear: 2024,
+			endYear:   2024,
+			output:    "myoutput",
+			want:      "myoutput.stl",
+		},
 	}
 
 	for _, tt := range tests {
@@ -4,6 +4,8 @@ package main
 import (
 	"context"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/github/gh-skyline/cmd"
 )
@@ -22,7 +24,9 @@ func main() {
 
 func start() exitCode {
 	exitCode := exitOK
-	ctx := context.Background()
+	// Ctrl-C cancels the context so a slow fetch or generation stops cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	if err := cmd.Execute(ctx); err != nil {
 		exitCode = exitError